@@ -2,6 +2,8 @@ package main
 
 import (
 	"log"
+	"path/filepath"
+	"time"
 
 	"finance_go/services"
 	"finance_go/storage"
@@ -34,6 +36,21 @@ func main() {
 		financeService.SetTransactionList(transactionList)
 	}
 
+	// Generate recurring transactions that became due since the last run
+	recurrenceService := services.NewRecurrenceService(financeService)
+	if created := recurrenceService.GenerateDue(time.Now()); len(created) > 0 {
+		log.Printf("Generated %d recurring transactions", len(created))
+	}
+
+	// Remove attachment files no saved transaction references. When the ledger failed to load every
+	// file would look unused, so they are all kept.
+	attachmentService := services.NewAttachmentService(financeService, filepath.Join("data", "attachments"))
+	if err != nil {
+		log.Printf("Skipping attachment cleanup because the data did not load")
+	} else {
+		removeUnusedAttachments(attachmentService)
+	}
+
 	// Initialize UI layer
 	_ = ui.NewMainWindow(w, financeService)
 
@@ -43,7 +60,10 @@ func main() {
 			err := storage.Save(financeService.GetTransactionList())
 			if err != nil {
 				log.Printf("Error saving data: %v", err)
+				return
 			}
+			// Files of deleted transactions and removed attachments go once the ledger no longer references them
+			removeUnusedAttachments(attachmentService)
 		}
 	})
 
@@ -54,5 +74,16 @@ func main() {
 	err = storage.Save(financeService.GetTransactionList())
 	if err != nil {
 		log.Printf("Error saving data on exit: %v", err)
+		return
+	}
+	removeUnusedAttachments(attachmentService)
+}
+
+// removeUnusedAttachments deletes the attachment files the saved ledger no longer references
+func removeUnusedAttachments(attachmentService *services.AttachmentService) {
+	if removed, err := attachmentService.RemoveUnusedFiles(); err != nil {
+		log.Printf("Error cleaning up attachments: %v", err)
+	} else if removed > 0 {
+		log.Printf("Removed %d unused attachment files", removed)
 	}
 }
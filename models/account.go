@@ -0,0 +1,217 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrAccountNotFound is returned when no account has the requested ID
+var ErrAccountNotFound = errors.New("account not found")
+
+// ErrSameAccountTransfer is returned when both legs of a transfer would be in the same account
+var ErrSameAccountTransfer = errors.New("source and destination accounts must differ")
+
+// AccountType classifies an account
+type AccountType string
+
+const (
+	AccountTypeChecking   AccountType = "Conta Corrente"
+	AccountTypeSavings    AccountType = "Poupança"
+	AccountTypeCash       AccountType = "Dinheiro"
+	AccountTypeCreditCard AccountType = "Cartão de Crédito"
+)
+
+// DefaultAccountName is the name of the account created for ledgers without accounts
+const DefaultAccountName = "Conta Corrente"
+
+// Account represents a place where money is kept, such as a bank account or a credit card
+type Account struct {
+	ID   int         `json:"id"`
+	Name string      `json:"name"`
+	Type AccountType `json:"type"`
+	// ClosingDay and DueDay are the statement closing and payment days of a credit card
+	ClosingDay int `json:"closing_day,omitempty"`
+	DueDay     int `json:"due_day,omitempty"`
+}
+
+// IsCreditCard reports whether the account is a credit card with statement days
+func (a Account) IsCreditCard() bool {
+	return a.Type == AccountTypeCreditCard && a.ClosingDay > 0 && a.DueDay > 0
+}
+
+// AccountTypes returns the selectable account types in display order
+func AccountTypes() []AccountType {
+	return []AccountType{AccountTypeChecking, AccountTypeSavings, AccountTypeCash, AccountTypeCreditCard}
+}
+
+// AccountTypeNames returns the account types as strings, for select widgets
+func AccountTypeNames() []string {
+	var names []string
+	for _, t := range AccountTypes() {
+		names = append(names, string(t))
+	}
+	return names
+}
+
+// AddAccount adds a new account with a unique, non-empty name
+func (tl *TransactionList) AddAccount(name string, accountType AccountType) (Account, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Account{}, fmt.Errorf("account name is required")
+	}
+	if _, err := tl.GetAccountByName(name); err == nil {
+		return Account{}, fmt.Errorf("account %q already exists", name)
+	}
+
+	account := Account{
+		ID:   tl.nextAccountID(),
+		Name: name,
+		Type: accountType,
+	}
+	tl.Accounts = append(tl.Accounts, account)
+	return account, nil
+}
+
+// AddCreditCard adds a credit card account whose statements close on closingDay and are due on
+// dueDay. Days past the end of a short month fall on its last day.
+func (tl *TransactionList) AddCreditCard(name string, closingDay, dueDay int) (Account, error) {
+	if err := ValidateCardDays(closingDay, dueDay); err != nil {
+		return Account{}, err
+	}
+	if _, err := tl.AddAccount(name, AccountTypeCreditCard); err != nil {
+		return Account{}, err
+	}
+	tl.Accounts[len(tl.Accounts)-1].ClosingDay = closingDay
+	tl.Accounts[len(tl.Accounts)-1].DueDay = dueDay
+	return tl.Accounts[len(tl.Accounts)-1], nil
+}
+
+// ValidateCardDays checks the statement closing and due days of a credit card
+func ValidateCardDays(closingDay, dueDay int) error {
+	if closingDay < 1 || closingDay > 31 {
+		return fmt.Errorf("closing day must be between 1 and 31")
+	}
+	if dueDay < 1 || dueDay > 31 {
+		return fmt.Errorf("due day must be between 1 and 31")
+	}
+	if closingDay == dueDay {
+		return fmt.Errorf("closing and due days must differ")
+	}
+	return nil
+}
+
+// GetAccounts returns all accounts
+func (tl *TransactionList) GetAccounts() []Account {
+	return tl.Accounts
+}
+
+// GetAccountByID returns the account with the given ID
+func (tl *TransactionList) GetAccountByID(id int) (Account, error) {
+	for _, account := range tl.Accounts {
+		if account.ID == id {
+			return account, nil
+		}
+	}
+	return Account{}, ErrAccountNotFound
+}
+
+// GetAccountByName returns the account with the given name, case-insensitively
+func (tl *TransactionList) GetAccountByName(name string) (Account, error) {
+	for _, account := range tl.Accounts {
+		if strings.EqualFold(account.Name, strings.TrimSpace(name)) {
+			return account, nil
+		}
+	}
+	return Account{}, ErrAccountNotFound
+}
+
+// DefaultAccountID returns the account used for transactions without an account
+func (tl *TransactionList) DefaultAccountID() int {
+	if len(tl.Accounts) == 0 {
+		return 0
+	}
+	return tl.Accounts[0].ID
+}
+
+// EnsureAccounts creates the default account when there is none and assigns it
+// to transactions without an account. It returns how many transactions were assigned.
+func (tl *TransactionList) EnsureAccounts() int {
+	if len(tl.Accounts) == 0 {
+		tl.AddAccount(DefaultAccountName, AccountTypeChecking)
+	}
+
+	assigned := 0
+	for i := range tl.Transactions {
+		if tl.Transactions[i].AccountID == 0 {
+			tl.Transactions[i].AccountID = tl.DefaultAccountID()
+			assigned++
+		}
+	}
+	return assigned
+}
+
+// GetAccountBalance calculates the balance of a single account in the base currency
+func (tl *TransactionList) GetAccountBalance(accountID int) Money {
+	total := NewMoney(0, tl.GetBaseCurrency())
+	for _, tx := range tl.Query(Query{AccountID: accountID}) {
+		total = total.Add(tl.BaseValue(tx))
+	}
+	return total
+}
+
+// AccountCurrency returns the currency of the latest transaction of an account, or the base
+// currency when the account has none
+func (tl *TransactionList) AccountCurrency(accountID int) string {
+	latest := tl.Query(Query{AccountID: accountID, SortBy: SortByDate, Descending: true, Limit: 1})
+	if len(latest) == 0 || latest[0].Value.Currency == "" {
+		return tl.GetBaseCurrency()
+	}
+	return latest[0].Value.Currency
+}
+
+// AddTransfer records a transfer as two linked transactions: an outgoing one in the
+// source account and an incoming one in the destination account. Transfers are
+// neither income nor expense and do not change the overall balance.
+func (tl *TransactionList) AddTransfer(fromAccountID, toAccountID int, value Money, description string, date time.Time) (Transaction, Transaction, error) {
+	if fromAccountID == toAccountID {
+		return Transaction{}, Transaction{}, ErrSameAccountTransfer
+	}
+	if value.IsZero() {
+		return Transaction{}, Transaction{}, fmt.Errorf("transfer amount must not be zero")
+	}
+	from, err := tl.GetAccountByID(fromAccountID)
+	if err != nil {
+		return Transaction{}, Transaction{}, fmt.Errorf("source account %d: %w", fromAccountID, err)
+	}
+	to, err := tl.GetAccountByID(toAccountID)
+	if err != nil {
+		return Transaction{}, Transaction{}, fmt.Errorf("destination account %d: %w", toAccountID, err)
+	}
+
+	outgoing := NewTransactionWithDate(TransactionTypeTransfer, value.Abs().Neg(), description, "", date)
+	outgoing.AccountID = from.ID
+	incoming := NewTransactionWithDate(TransactionTypeTransfer, value.Abs(), description, "", date)
+	incoming.AccountID = to.ID
+	outgoing.LinkedID = incoming.ID
+	incoming.LinkedID = outgoing.ID
+
+	if err := tl.AddTransaction(outgoing); err != nil {
+		return Transaction{}, Transaction{}, err
+	}
+	if err := tl.AddTransaction(incoming); err != nil {
+		return Transaction{}, Transaction{}, err
+	}
+	return outgoing, incoming, nil
+}
+
+func (tl *TransactionList) nextAccountID() int {
+	next := 1
+	for _, account := range tl.Accounts {
+		if account.ID >= next {
+			next = account.ID + 1
+		}
+	}
+	return next
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+// newAccountLedger returns a ledger with the default account and a savings account
+func newAccountLedger(t *testing.T) (*TransactionList, Account, Account) {
+	t.Helper()
+	tl := &TransactionList{BaseCurrency: "BRL"}
+	tl.EnsureAccounts()
+	checking, err := tl.GetAccountByID(tl.DefaultAccountID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	savings, err := tl.AddAccount("Poupança", AccountTypeSavings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tl, checking, savings
+}
+
+func TestAddAccount(t *testing.T) {
+	tl, checking, savings := newAccountLedger(t)
+	if checking.Name != DefaultAccountName || savings.ID != checking.ID+1 {
+		t.Fatalf("accounts = %+v, %+v", checking, savings)
+	}
+	if _, err := tl.AddAccount("  poupança ", AccountTypeCash); err == nil {
+		t.Error("an account with an existing name was added")
+	}
+	if _, err := tl.AddAccount(" ", AccountTypeCash); err == nil {
+		t.Error("an account without a name was added")
+	}
+	if found, err := tl.GetAccountByName("POUPANÇA"); err != nil || found.ID != savings.ID {
+		t.Errorf("GetAccountByName ignoring case = %+v, %v", found, err)
+	}
+	if _, err := tl.GetAccountByID(99); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("GetAccountByID(99) error = %v, want ErrAccountNotFound", err)
+	}
+}
+
+func TestEnsureAccountsAssignsTheDefault(t *testing.T) {
+	tl := &TransactionList{Transactions: []Transaction{
+		{ID: 1, Type: TransactionTypeExpense, Value: NewMoney(-100, "BRL")},
+		{ID: 2, Type: TransactionTypeExpense, Value: NewMoney(-100, "BRL"), AccountID: 7},
+	}}
+	if assigned := tl.EnsureAccounts(); assigned != 1 {
+		t.Errorf("EnsureAccounts assigned %d transactions, want 1", assigned)
+	}
+	if tl.Transactions[0].AccountID != tl.DefaultAccountID() || tl.Transactions[1].AccountID != 7 {
+		t.Errorf("accounts after EnsureAccounts = %d, %d", tl.Transactions[0].AccountID, tl.Transactions[1].AccountID)
+	}
+	if assigned := tl.EnsureAccounts(); assigned != 0 || len(tl.Accounts) != 1 {
+		t.Errorf("running EnsureAccounts again assigned %d and left %d accounts", assigned, len(tl.Accounts))
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	tl, checking, savings := newAccountLedger(t)
+	tl.AddTransaction(NewTransactionWithDate(TransactionTypeIncome, NewMoney(100000, "BRL"), "Salário", "", date(2026, 1, 5)))
+
+	outgoing, incoming, err := tl.AddTransfer(checking.ID, savings.ID, NewMoney(-30000, "BRL"), "Reserva", date(2026, 1, 6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outgoing.Value.Amount != -30000 || incoming.Value.Amount != 30000 || outgoing.LinkedID != incoming.ID || incoming.LinkedID != outgoing.ID {
+		t.Errorf("transfer legs = %+v, %+v", outgoing, incoming)
+	}
+	if got := tl.GetAccountBalance(checking.ID); got.Amount != 70000 {
+		t.Errorf("checking balance = %v, want 700.00", got)
+	}
+	if got := tl.GetAccountBalance(savings.ID); got.Amount != 30000 {
+		t.Errorf("savings balance = %v, want 300.00", got)
+	}
+	if got := tl.GetBalance(); got.Amount != 100000 {
+		t.Errorf("a transfer changed the overall balance to %v", got)
+	}
+
+	if _, _, err := tl.AddTransfer(checking.ID, checking.ID, NewMoney(100, "BRL"), "", date(2026, 1, 6)); !errors.Is(err, ErrSameAccountTransfer) {
+		t.Errorf("transfer to the same account error = %v, want ErrSameAccountTransfer", err)
+	}
+	if _, _, err := tl.AddTransfer(checking.ID, savings.ID, NewMoney(0, "BRL"), "", date(2026, 1, 6)); err == nil {
+		t.Error("a zero transfer was added")
+	}
+	if _, _, err := tl.AddTransfer(checking.ID, 99, NewMoney(100, "BRL"), "", date(2026, 1, 6)); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("transfer to a missing account error = %v, want ErrAccountNotFound", err)
+	}
+}
+
+func TestUpdateTransferKeepsLegsApart(t *testing.T) {
+	tl, checking, savings := newAccountLedger(t)
+	wallet, err := tl.AddAccount("Carteira", AccountTypeCash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outgoing, incoming, err := tl.AddTransfer(checking.ID, savings.ID, NewMoney(30000, "BRL"), "Reserva", date(2026, 1, 6))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moved := outgoing
+	moved.AccountID = savings.ID
+	if err := tl.UpdateTransaction(moved); !errors.Is(err, ErrSameAccountTransfer) {
+		t.Fatalf("moving the outgoing leg to the other leg's account error = %v, want ErrSameAccountTransfer", err)
+	}
+	if current, _ := tl.GetTransactionByID(outgoing.ID); current.AccountID != checking.ID {
+		t.Errorf("the rejected change was saved: account %d", current.AccountID)
+	}
+
+	// Another account is fine, and the amount, description and date follow to the other leg
+	moved.AccountID = wallet.ID
+	moved.Value = NewMoney(-45000, "BRL")
+	moved.Description = "Troco"
+	moved.Date = date(2026, 1, 7)
+	if err := tl.UpdateTransaction(moved); err != nil {
+		t.Fatal(err)
+	}
+	other, _ := tl.GetTransactionByID(incoming.ID)
+	if other.AccountID != savings.ID || other.Value.Amount != 45000 || other.Description != "Troco" || !other.Date.Equal(date(2026, 1, 7)) {
+		t.Errorf("linked leg after the update = %+v", other)
+	}
+
+	// Deleting one leg removes the other
+	if err := tl.DeleteTransaction(incoming.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(tl.Transactions) != 0 {
+		t.Errorf("%d transactions left after deleting a transfer", len(tl.Transactions))
+	}
+}
+
+func TestAccountCurrency(t *testing.T) {
+	tl, checking, savings := newAccountLedger(t)
+	travel := NewTransactionWithDate(TransactionTypeExpense, NewMoney(1000, "USD"), "Hotel", "", date(2026, 2, 1))
+	travel.AccountID = savings.ID
+	tl.AddTransaction(travel)
+	older := NewTransactionWithDate(TransactionTypeIncome, NewMoney(5000, "BRL"), "Juros", "", date(2026, 1, 1))
+	older.AccountID = savings.ID
+	tl.AddTransaction(older)
+
+	if got := tl.AccountCurrency(savings.ID); got != "USD" {
+		t.Errorf("AccountCurrency(savings) = %q, want the currency of the latest transaction", got)
+	}
+	tl.SetBaseCurrency("EUR")
+	if got := tl.AccountCurrency(checking.ID); got != "EUR" {
+		t.Errorf("AccountCurrency of an empty account = %q, want the base currency", got)
+	}
+}
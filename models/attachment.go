@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrAttachmentNotFound is returned when a transaction has no attachment with the requested file
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+// Attachment is a receipt or document linked to a transaction. File is the name of the
+// managed copy in the attachments folder; several transactions may share the same file.
+type Attachment struct {
+	Name    string    `json:"name"`
+	File    string    `json:"file"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// AddAttachment links an attachment to a transaction, ignoring files it already references
+func (tl *TransactionList) AddAttachment(id int, attachment Attachment) error {
+	index := tl.indexOf(id)
+	if index < 0 {
+		return ErrTransactionNotFound
+	}
+
+	for _, existing := range tl.Transactions[index].Attachments {
+		if existing.File == attachment.File {
+			return nil
+		}
+	}
+	tl.Transactions[index].Attachments = append(tl.Transactions[index].Attachments, attachment)
+	return nil
+}
+
+// RemoveAttachment unlinks an attachment from a transaction. A reconciled transaction keeps its
+// attachments, since they document what was checked against the statement.
+func (tl *TransactionList) RemoveAttachment(id int, file string) error {
+	index := tl.indexOf(id)
+	if index < 0 {
+		return ErrTransactionNotFound
+	}
+	if tl.Transactions[index].IsLocked() {
+		return ErrTransactionLocked
+	}
+
+	attachments := tl.Transactions[index].Attachments
+	for i, attachment := range attachments {
+		if attachment.File == file {
+			tl.Transactions[index].Attachments = append(attachments[:i], attachments[i+1:]...)
+			return nil
+		}
+	}
+	return ErrAttachmentNotFound
+}
+
+// IsAttachmentInUse reports whether any transaction still references a file
+func (tl *TransactionList) IsAttachmentInUse(file string) bool {
+	for _, tx := range tl.Transactions {
+		for _, attachment := range tx.Attachments {
+			if attachment.File == file {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAttachments(t *testing.T) {
+	tl, _, _ := newAccountLedger(t)
+	receipt := bankTransaction(t, tl, TransactionTypeExpense, 1000, "Farmácia", 5)
+	other := bankTransaction(t, tl, TransactionTypeExpense, 2000, "Mercado", 6)
+	nota := Attachment{Name: "nota.pdf", File: "a1b2.pdf", AddedAt: date(2026, 1, 5)}
+
+	if err := tl.AddAttachment(receipt.ID, nota); err != nil {
+		t.Fatal(err)
+	}
+	// Adding the same file again keeps a single link
+	if err := tl.AddAttachment(receipt.ID, nota); err != nil {
+		t.Fatal(err)
+	}
+	if err := tl.AddAttachment(other.ID, nota); err != nil {
+		t.Fatal(err)
+	}
+	if tx, _ := tl.GetTransactionByID(receipt.ID); len(tx.Attachments) != 1 || tx.Attachments[0] != nota {
+		t.Errorf("attachments = %+v", tx.Attachments)
+	}
+
+	// A file shared by two transactions is in use until both let it go
+	if err := tl.RemoveAttachment(receipt.ID, nota.File); err != nil {
+		t.Fatal(err)
+	}
+	if !tl.IsAttachmentInUse(nota.File) {
+		t.Error("the file is no longer in use while another transaction references it")
+	}
+	if err := tl.DeleteTransaction(other.ID); err != nil {
+		t.Fatal(err)
+	}
+	if tl.IsAttachmentInUse(nota.File) {
+		t.Error("the file is still in use after every reference is gone")
+	}
+
+	if err := tl.RemoveAttachment(receipt.ID, nota.File); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("removing twice error = %v, want ErrAttachmentNotFound", err)
+	}
+	if err := tl.AddAttachment(other.ID, nota); !errors.Is(err, ErrTransactionNotFound) {
+		t.Errorf("attaching to a deleted transaction error = %v, want ErrTransactionNotFound", err)
+	}
+}
+
+func TestReconciledTransactionsKeepTheirAttachments(t *testing.T) {
+	tl, _, _ := newAccountLedger(t)
+	receipt := bankTransaction(t, tl, TransactionTypeExpense, 1000, "Farmácia", 5)
+	nota := Attachment{Name: "nota.pdf", File: "a1b2.pdf", AddedAt: date(2026, 1, 5)}
+	if err := tl.AddAttachment(receipt.ID, nota); err != nil {
+		t.Fatal(err)
+	}
+	if err := tl.SetCleared(receipt.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tl.FinishReconciliation(receipt.AccountID, receipt.Date, receipt.Value); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tl.RemoveAttachment(receipt.ID, nota.File); !errors.Is(err, ErrTransactionLocked) {
+		t.Errorf("removing an attachment of a reconciled transaction error = %v, want ErrTransactionLocked", err)
+	}
+	if !tl.IsAttachmentInUse(nota.File) {
+		t.Error("the attachment was removed from a reconciled transaction")
+	}
+	// Receipts can still be added to it
+	if err := tl.AddAttachment(receipt.ID, Attachment{Name: "extrato.pdf", File: "c3d4.pdf"}); err != nil {
+		t.Errorf("adding an attachment to a reconciled transaction: %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrBudgetNotFound is returned when no budget has the requested ID
+var ErrBudgetNotFound = errors.New("budget not found")
+
+// Budget is the spending limit of a category in a given month
+type Budget struct {
+	ID       int        `json:"id"`
+	Category string     `json:"category"`
+	Year     int        `json:"year"`
+	Month    time.Month `json:"month"`
+	Limit    Money      `json:"limit"`
+}
+
+// BudgetStatus compares a budget with the actual spending of its month
+type BudgetStatus struct {
+	Budget    Budget
+	Actual    Money
+	Remaining Money
+}
+
+// OverBudget reports whether spending exceeded the limit
+func (bs BudgetStatus) OverBudget() bool {
+	return bs.Remaining.IsNegative()
+}
+
+// UsedPercent returns the spending as a percentage of the limit
+func (bs BudgetStatus) UsedPercent() float64 {
+	if bs.Budget.Limit.IsZero() {
+		return 0
+	}
+	return float64(bs.Actual.Amount) / float64(bs.Budget.Limit.Amount) * 100
+}
+
+// SetBudget creates or replaces the budget of a category in a month
+func (tl *TransactionList) SetBudget(category string, year int, month time.Month, limit Money) (Budget, error) {
+	category = NormalizeCategory(category)
+	if category == "" {
+		return Budget{}, fmt.Errorf("budget category is required")
+	}
+	if month < time.January || month > time.December {
+		return Budget{}, fmt.Errorf("invalid month %d", month)
+	}
+	if limit.IsNegative() || limit.IsZero() {
+		return Budget{}, fmt.Errorf("budget limit must be positive")
+	}
+
+	for i, budget := range tl.Budgets {
+		if budget.Category == category && budget.Year == year && budget.Month == month {
+			tl.Budgets[i].Limit = limit
+			return tl.Budgets[i], nil
+		}
+	}
+
+	budget := Budget{ID: 1, Category: category, Year: year, Month: month, Limit: limit}
+	for _, existing := range tl.Budgets {
+		if existing.ID >= budget.ID {
+			budget.ID = existing.ID + 1
+		}
+	}
+	tl.Budgets = append(tl.Budgets, budget)
+	return budget, nil
+}
+
+// DeleteBudget removes a budget
+func (tl *TransactionList) DeleteBudget(id int) error {
+	for i, budget := range tl.Budgets {
+		if budget.ID == id {
+			tl.Budgets = append(tl.Budgets[:i], tl.Budgets[i+1:]...)
+			return nil
+		}
+	}
+	return ErrBudgetNotFound
+}
+
+// GetBudgets returns the budgets of a month ordered by category
+func (tl *TransactionList) GetBudgets(year int, month time.Month) []Budget {
+	var budgets []Budget
+	for _, budget := range tl.Budgets {
+		if budget.Year == year && budget.Month == month {
+			budgets = append(budgets, budget)
+		}
+	}
+	sort.Slice(budgets, func(i, j int) bool {
+		return budgets[i].Category < budgets[j].Category
+	})
+	return budgets
+}
+
+// GetBudgetStatus returns budget vs. actual vs. remaining for every budget of a month.
+// Actual spending is the sum of expenses of the category and its subcategories in the base currency.
+func (tl *TransactionList) GetBudgetStatus(year int, month time.Month) []BudgetStatus {
+	period := MonthPeriod(year, month, time.Local)
+
+	var statuses []BudgetStatus
+	for _, budget := range tl.GetBudgets(year, month) {
+		// A budget on a parent category covers all of its subcategories
+		actual := NewMoney(0, tl.GetBaseCurrency())
+		for _, tx := range tl.Transactions {
+			if tx.Type != TransactionTypeExpense || !period.Contains(tx.Date) {
+				continue
+			}
+			for _, part := range tx.Parts() {
+				if IsInCategory(part.Category, budget.Category) {
+					actual = actual.Add(tl.BaseAmount(part.Value, tx.Date).Abs())
+				}
+			}
+		}
+
+		limit := tl.BaseAmount(budget.Limit, period.Start)
+		budget.Limit = limit
+		statuses = append(statuses, BudgetStatus{
+			Budget:    budget,
+			Actual:    actual,
+			Remaining: limit.Sub(actual),
+		})
+	}
+	return statuses
+}
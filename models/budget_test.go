@@ -0,0 +1,100 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSetBudget(t *testing.T) {
+	tl := &TransactionList{}
+	budget, err := tl.SetBudget("Casa>Reparos", 2026, time.March, NewMoney(50000, "BRL"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if budget.ID != 1 || budget.Category != "Casa > Reparos" {
+		t.Errorf("budget = %+v", budget)
+	}
+
+	// Setting the same category and month again replaces the limit
+	replaced, err := tl.SetBudget("Casa > Reparos", 2026, time.March, NewMoney(80000, "BRL"))
+	if err != nil || replaced.ID != budget.ID || replaced.Limit.Amount != 80000 || len(tl.Budgets) != 1 {
+		t.Errorf("replaced budget = %+v, %v; %d budgets", replaced, err, len(tl.Budgets))
+	}
+	april, err := tl.SetBudget("Alimentação", 2026, time.April, NewMoney(100000, "BRL"))
+	if err != nil || april.ID != 2 {
+		t.Errorf("second budget = %+v, %v", april, err)
+	}
+
+	for _, invalid := range []struct {
+		category string
+		month    time.Month
+		limit    int64
+	}{
+		{" ", time.March, 100},
+		{"Lazer", 13, 100},
+		{"Lazer", time.March, 0},
+		{"Lazer", time.March, -100},
+	} {
+		if _, err := tl.SetBudget(invalid.category, 2026, invalid.month, NewMoney(invalid.limit, "BRL")); err == nil {
+			t.Errorf("invalid budget %+v was set", invalid)
+		}
+	}
+
+	if err := tl.DeleteBudget(budget.ID); err != nil {
+		t.Fatal(err)
+	}
+	if budgets := tl.GetBudgets(2026, time.March); len(budgets) != 0 {
+		t.Errorf("budgets after the delete = %+v", budgets)
+	}
+	if err := tl.DeleteBudget(budget.ID); !errors.Is(err, ErrBudgetNotFound) {
+		t.Errorf("deleting twice error = %v, want ErrBudgetNotFound", err)
+	}
+}
+
+func TestGetBudgetStatus(t *testing.T) {
+	tl := &TransactionList{BaseCurrency: "BRL"}
+	tl.EnsureAccounts()
+	at := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 12, 0, 0, 0, time.Local)
+	}
+	expense := func(amount int64, category string, when time.Time) Transaction {
+		return NewTransactionWithDate(TransactionTypeExpense, NewMoney(amount, "BRL"), "", category, when)
+	}
+	split := expense(10000, "", at(time.March, 20))
+	split.Splits = []Split{
+		{Value: NewMoney(4000, "BRL"), Category: "Casa > Limpeza"},
+		{Value: NewMoney(6000, "BRL"), Category: "Alimentação"},
+	}
+	for _, tx := range []Transaction{
+		expense(30000, "Casa > Reparos", at(time.March, 5)),
+		expense(25000, "Casa", at(time.March, 10)),
+		split,
+		expense(99900, "Casa", at(time.April, 2)),
+		expense(1000, "Casamento", at(time.March, 10)),
+		NewTransactionWithDate(TransactionTypeIncome, NewMoney(5000, "BRL"), "Estorno", "Casa", at(time.March, 12)),
+	} {
+		if err := tl.AddTransaction(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := tl.SetBudget("Casa", 2026, time.March, NewMoney(50000, "BRL")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tl.SetBudget("Alimentação", 2026, time.March, NewMoney(10000, "BRL")); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses := tl.GetBudgetStatus(2026, time.March)
+	if len(statuses) != 2 || statuses[0].Budget.Category != "Alimentação" || statuses[1].Budget.Category != "Casa" {
+		t.Fatalf("statuses = %+v, want Alimentação and Casa", statuses)
+	}
+	food, home := statuses[0], statuses[1]
+	if food.Actual.Amount != 6000 || food.Remaining.Amount != 4000 || food.OverBudget() || food.UsedPercent() != 60 {
+		t.Errorf("Alimentação = %+v", food)
+	}
+	// The parent budget covers its subcategories and split parts, not other months, look-alike names or income
+	if home.Actual.Amount != 30000+25000+4000 || home.Remaining.Amount != -9000 || !home.OverBudget() || home.UsedPercent() != 118 {
+		t.Errorf("Casa = actual %v, remaining %v, %.0f%%", home.Actual, home.Remaining, home.UsedPercent())
+	}
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// CategorySeparator separates the levels of a hierarchical category, e.g. "Alimentação > Supermercado"
+const CategorySeparator = " > "
+
+// CategoryNode is a category in the hierarchy with totals rolled up from its subcategories
+type CategoryNode struct {
+	Name     string
+	Path     string
+	Depth    int
+	Income   Money
+	Expense  Money
+	Children []*CategoryNode
+}
+
+// Balance returns income minus expense of the node and its subcategories
+func (n *CategoryNode) Balance() Money {
+	return n.Income.Sub(n.Expense)
+}
+
+// SplitCategory returns the levels of a category path
+func SplitCategory(category string) []string {
+	var parts []string
+	for _, part := range strings.Split(category, strings.TrimSpace(CategorySeparator)) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// NormalizeCategory rewrites a category path with canonical spacing, e.g. "a>b " becomes "a > b"
+func NormalizeCategory(category string) string {
+	return strings.Join(SplitCategory(category), CategorySeparator)
+}
+
+// ParentCategory returns the parent path of a category, or an empty string for a top-level category
+func ParentCategory(category string) string {
+	parts := SplitCategory(category)
+	if len(parts) <= 1 {
+		return ""
+	}
+	return strings.Join(parts[:len(parts)-1], CategorySeparator)
+}
+
+// IsInCategory reports whether category is ancestor itself or one of its subcategories
+func IsInCategory(category, ancestor string) bool {
+	category, ancestor = NormalizeCategory(category), NormalizeCategory(ancestor)
+	return category == ancestor || strings.HasPrefix(category, ancestor+CategorySeparator)
+}
+
+// GetCategoryPaths returns every category and all of their ancestors, sorted
+func (tl *TransactionList) GetCategoryPaths() []string {
+	seen := make(map[string]bool)
+	for _, category := range tl.GetCategories() {
+		parts := SplitCategory(category)
+		for i := range parts {
+			seen[strings.Join(parts[:i+1], CategorySeparator)] = true
+		}
+	}
+
+	var paths []string
+	for path := range seen {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// BuildCategoryTree builds a category hierarchy from transactions, attributing each split
+// to its own category and converting amounts with value
+func BuildCategoryTree(transactions []Transaction, value func(Money, time.Time) Money, currency string) []*CategoryNode {
+	nodes := make(map[string]*CategoryNode)
+	var roots []*CategoryNode
+
+	node := func(parts []string) *CategoryNode {
+		var parent *CategoryNode
+		for i := range parts {
+			path := strings.Join(parts[:i+1], CategorySeparator)
+			current, ok := nodes[path]
+			if !ok {
+				current = &CategoryNode{
+					Name:    parts[i],
+					Path:    path,
+					Depth:   i,
+					Income:  NewMoney(0, currency),
+					Expense: NewMoney(0, currency),
+				}
+				nodes[path] = current
+				if parent == nil {
+					roots = append(roots, current)
+				} else {
+					parent.Children = append(parent.Children, current)
+				}
+			}
+			parent = current
+		}
+		return parent
+	}
+
+	for _, tx := range transactions {
+		if tx.Type == TransactionTypeTransfer {
+			continue
+		}
+		for _, part := range tx.Parts() {
+			levels := SplitCategory(part.Category)
+			if len(levels) == 0 {
+				levels = []string{""}
+			}
+			node(levels)
+
+			amount := value(part.Value, tx.Date)
+			for i := range levels {
+				current := nodes[strings.Join(levels[:i+1], CategorySeparator)]
+				if tx.Type == TransactionTypeIncome {
+					current.Income = current.Income.Add(amount)
+				} else {
+					current.Expense = current.Expense.Add(amount.Abs())
+				}
+			}
+		}
+	}
+
+	sortCategoryNodes(roots)
+	return roots
+}
+
+// FlattenCategoryTree lists nodes depth-first, down to maxDepth levels (0 means all levels)
+func FlattenCategoryTree(roots []*CategoryNode, maxDepth int) []*CategoryNode {
+	var flat []*CategoryNode
+	var walk func(nodes []*CategoryNode)
+	walk = func(nodes []*CategoryNode) {
+		for _, n := range nodes {
+			if maxDepth > 0 && n.Depth >= maxDepth {
+				continue
+			}
+			flat = append(flat, n)
+			walk(n.Children)
+		}
+	}
+	walk(roots)
+	return flat
+}
+
+func sortCategoryNodes(nodes []*CategoryNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	for _, n := range nodes {
+		sortCategoryNodes(n.Children)
+	}
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCategoryPaths(t *testing.T) {
+	tests := []struct {
+		in         string
+		normalized string
+		parent     string
+	}{
+		{"Alimentação", "Alimentação", ""},
+		{"Casa>Reparos", "Casa > Reparos", "Casa"},
+		{"  Casa >  Reparos > Elétrica ", "Casa > Reparos > Elétrica", "Casa > Reparos"},
+		{"Casa >> Reparos >", "Casa > Reparos", "Casa"},
+		{" ", "", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeCategory(tt.in); got != tt.normalized {
+			t.Errorf("NormalizeCategory(%q) = %q, want %q", tt.in, got, tt.normalized)
+		}
+		if got := ParentCategory(tt.in); got != tt.parent {
+			t.Errorf("ParentCategory(%q) = %q, want %q", tt.in, got, tt.parent)
+		}
+	}
+
+	inCategory := []struct {
+		category, ancestor string
+		want               bool
+	}{
+		{"Casa", "Casa", true},
+		{"Casa > Reparos", "Casa", true},
+		{"Casa>Reparos>Elétrica", "Casa > Reparos", true},
+		{"Casamento", "Casa", false},
+		{"Casa", "Casa > Reparos", false},
+		{"Lazer > Casa", "Casa", false},
+	}
+	for _, tt := range inCategory {
+		if got := IsInCategory(tt.category, tt.ancestor); got != tt.want {
+			t.Errorf("IsInCategory(%q, %q) = %v, want %v", tt.category, tt.ancestor, got, tt.want)
+		}
+	}
+}
+
+func TestGetCategoryPaths(t *testing.T) {
+	tl := &TransactionList{Transactions: []Transaction{
+		{Type: TransactionTypeExpense, Value: NewMoney(-100, "BRL"), Category: "Casa > Reparos > Elétrica"},
+		{Type: TransactionTypeExpense, Value: NewMoney(-100, "BRL"), Category: "Alimentação"},
+		{Type: TransactionTypeExpense, Value: NewMoney(-100, "BRL"), Splits: []Split{
+			{Value: NewMoney(-60, "BRL"), Category: "Casa > Limpeza"},
+			{Value: NewMoney(-40, "BRL"), Category: "Alimentação > Feira"},
+		}},
+	}}
+	want := []string{"Alimentação", "Alimentação > Feira", "Casa", "Casa > Limpeza", "Casa > Reparos", "Casa > Reparos > Elétrica"}
+	if got := tl.GetCategoryPaths(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetCategoryPaths() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	day := date(2026, 3, 10)
+	split := Transaction{Type: TransactionTypeExpense, Value: NewMoney(-10000, "BRL"), Date: day, Splits: []Split{
+		{Value: NewMoney(-4000, "BRL"), Category: "Casa > Limpeza"},
+		{Value: NewMoney(-6000, "BRL"), Category: "Alimentação"},
+	}}
+	transactions := []Transaction{
+		{Type: TransactionTypeExpense, Value: NewMoney(-30000, "BRL"), Category: "Casa > Reparos", Date: day},
+		{Type: TransactionTypeExpense, Value: NewMoney(-5000, "BRL"), Category: "Casa", Date: day},
+		{Type: TransactionTypeIncome, Value: NewMoney(2000, "BRL"), Category: "Casa > Reparos", Date: day},
+		{Type: TransactionTypeIncome, Value: NewMoney(700000, "BRL"), Date: day},
+		{Type: TransactionTypeTransfer, Value: NewMoney(-90000, "BRL"), Category: "Casa", Date: day},
+		split,
+	}
+	identity := func(value Money, _ time.Time) Money { return value }
+	roots := BuildCategoryTree(transactions, identity, "BRL")
+
+	flat := FlattenCategoryTree(roots, 0)
+	var paths []string
+	for _, n := range flat {
+		paths = append(paths, n.Path)
+	}
+	// Uncategorized transactions get an unnamed root; transfers are left out
+	want := []string{"", "Alimentação", "Casa", "Casa > Limpeza", "Casa > Reparos"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("tree paths = %q, want %q", paths, want)
+	}
+
+	home := flat[2]
+	if home.Expense.Amount != 30000+5000+4000 || home.Income.Amount != 2000 || home.Balance().Amount != 2000-39000 || len(home.Children) != 2 {
+		t.Errorf("Casa = income %v, expense %v, %d children", home.Income, home.Expense, len(home.Children))
+	}
+	if repairs := flat[4]; repairs.Depth != 1 || repairs.Name != "Reparos" || repairs.Expense.Amount != 30000 || repairs.Income.Amount != 2000 {
+		t.Errorf("Casa > Reparos = %+v", repairs)
+	}
+	if food := flat[1]; food.Expense.Amount != 6000 {
+		t.Errorf("Alimentação expense = %v, want the split part 60.00", food.Expense)
+	}
+	if top := FlattenCategoryTree(roots, 1); len(top) != 3 {
+		t.Errorf("FlattenCategoryTree(roots, 1) has %d nodes, want the 3 top-level ones", len(top))
+	}
+
+	// Amounts are converted with the given function
+	double := func(value Money, _ time.Time) Money { return NewMoney(value.Amount*2, "BRL") }
+	if converted := BuildCategoryTree(transactions, double, "BRL"); converted[1].Expense.Amount != 12000 {
+		t.Errorf("converted Alimentação expense = %v, want 120.00", converted[1].Expense)
+	}
+}
@@ -0,0 +1,173 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MinSuggestionConfidence is the confidence below which a suggested category is flagged for review
+const MinSuggestionConfidence = 0.6
+
+// classifierSmoothing is the count given to words never seen in a category. It is well below one
+// so that, with the few examples a personal ledger has, a shared word outweighs category size.
+const classifierSmoothing = 0.1
+
+// unknownCategoryDocuments is the weight, in training examples, of a pseudo-category standing for
+// every category not seen in training. It keeps some probability for "none of these", so a
+// classifier that learned a single category from one example is not sure of it.
+const unknownCategoryDocuments = 2
+
+// CategorySuggestion is a category the classifier proposes for a description
+type CategorySuggestion struct {
+	Category string
+	// Confidence is the probability the classifier gives the category, between 0 and 1. What the
+	// suggestions leave out of 1 goes to a category never seen in training.
+	Confidence float64
+}
+
+// NeedsReview reports whether the suggestion is too uncertain to be used without checking
+func (s CategorySuggestion) NeedsReview() bool {
+	return s.Confidence < MinSuggestionConfidence
+}
+
+// CategoryClassifier is a naive Bayes classifier over description words, trained on how
+// transactions were categorized before
+type CategoryClassifier struct {
+	categories map[string]*categoryCounts
+	vocabulary map[string]bool
+	documents  int
+}
+
+// categoryCounts are the training counts of one category
+type categoryCounts struct {
+	documents int
+	words     int
+	counts    map[string]int
+	types     map[TransactionType]int
+}
+
+// NewCategoryClassifier creates an untrained classifier
+func NewCategoryClassifier() *CategoryClassifier {
+	return &CategoryClassifier{
+		categories: make(map[string]*categoryCounts),
+		vocabulary: make(map[string]bool),
+	}
+}
+
+// TrainClassifier trains a classifier on every categorized transaction of the ledger. Each part of
+// a split transaction counts as its own example; transfers are skipped.
+func (tl *TransactionList) TrainClassifier() *CategoryClassifier {
+	classifier := NewCategoryClassifier()
+	for _, tx := range tl.Transactions {
+		if tx.Type == TransactionTypeTransfer {
+			continue
+		}
+		for _, part := range tx.Parts() {
+			classifier.Train(part.Description, TransactionTypeForValue(part.Value), part.Category)
+		}
+	}
+	return classifier
+}
+
+// Train adds an example of a description and type that belongs to category
+func (c *CategoryClassifier) Train(description string, transactionType TransactionType, category string) {
+	category = NormalizeCategory(category)
+	words := classifierWords(description)
+	if category == "" || len(words) == 0 {
+		return
+	}
+
+	counts := c.categories[category]
+	if counts == nil {
+		counts = &categoryCounts{counts: make(map[string]int), types: make(map[TransactionType]int)}
+		c.categories[category] = counts
+	}
+	counts.documents++
+	counts.types[transactionType]++
+	c.documents++
+	for _, word := range words {
+		counts.counts[word]++
+		counts.words++
+		c.vocabulary[word] = true
+	}
+}
+
+// Suggest returns up to limit categories for a description, most likely first. Words never seen
+// in training are ignored, and nothing is suggested when none of the words was seen.
+func (c *CategoryClassifier) Suggest(description string, transactionType TransactionType, limit int) []CategorySuggestion {
+	var words []string
+	for _, word := range classifierWords(description) {
+		if c.vocabulary[word] {
+			words = append(words, word)
+		}
+	}
+	if len(words) == 0 {
+		return nil
+	}
+
+	// Log-probabilities of the category, of the type within it and of each word, turned into
+	// probabilities relative to the best category. The unknown category has no type preference
+	// and gives every word, seen or not, the same likelihood.
+	vocabulary := float64(len(c.vocabulary))
+	documents := float64(c.documents) + unknownCategoryDocuments
+	unknown := math.Log(unknownCategoryDocuments/documents) + math.Log(0.5) -
+		float64(len(words))*math.Log(vocabulary+1)
+	scores := make(map[string]float64, len(c.categories))
+	best := unknown
+	for category, counts := range c.categories {
+		score := math.Log(float64(counts.documents) / documents)
+		// The type tells income and expenses with similar words apart, e.g. a refund from a purchase
+		score += math.Log(float64(counts.types[transactionType]+1) / float64(counts.documents+2))
+		for _, word := range words {
+			score += math.Log((float64(counts.counts[word]) + classifierSmoothing) /
+				(float64(counts.words) + classifierSmoothing*vocabulary))
+		}
+		scores[category] = score
+		best = math.Max(best, score)
+	}
+
+	total := math.Exp(unknown - best)
+	suggestions := make([]CategorySuggestion, 0, len(scores))
+	for category, score := range scores {
+		probability := math.Exp(score - best)
+		total += probability
+		suggestions = append(suggestions, CategorySuggestion{Category: category, Confidence: probability})
+	}
+	for i := range suggestions {
+		suggestions[i].Confidence /= total
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Confidence != suggestions[j].Confidence {
+			return suggestions[i].Confidence > suggestions[j].Confidence
+		}
+		return suggestions[i].Category < suggestions[j].Category
+	})
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// Best returns the most likely category for a description, if any
+func (c *CategoryClassifier) Best(description string, transactionType TransactionType) (CategorySuggestion, bool) {
+	suggestions := c.Suggest(description, transactionType, 1)
+	if len(suggestions) == 0 {
+		return CategorySuggestion{}, false
+	}
+	return suggestions[0], true
+}
+
+// classifierWords returns the description words the classifier learns from; numbers such as
+// dates, amounts and installment counters are dropped since they say nothing about the category
+func classifierWords(description string) []string {
+	var words []string
+	for _, word := range descriptionWords(description) {
+		if len(word) < 2 || strings.IndexFunc(word, unicode.IsLetter) < 0 {
+			continue
+		}
+		words = append(words, word)
+	}
+	return words
+}
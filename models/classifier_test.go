@@ -0,0 +1,162 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassifierWords(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Padaria São João", []string{"padaria", "sao", "joao"}},
+		{"IFOOD *Restaurante-Sabor", []string{"ifood", "restaurante", "sabor"}},
+		// Numbers are dropped, and so is the single letter left of "R$"
+		{"Netflix 12/2025 R$ 39,90", []string{"netflix"}},
+		{"Geladeira (3/10)", []string{"geladeira"}},
+		{"Uber 3x a", []string{"uber", "3x"}},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		if got := classifierWords(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("classifierWords(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// trainedClassifier returns a classifier trained on a small fixed history
+func trainedClassifier() *CategoryClassifier {
+	history := []struct {
+		description string
+		kind        TransactionType
+		category    string
+	}{
+		{"Supermercado Extra", TransactionTypeExpense, "Alimentação > Mercado"},
+		{"Supermercado Pão de Açúcar", TransactionTypeExpense, "Alimentação > Mercado"},
+		{"Padaria do bairro", TransactionTypeExpense, "Alimentação > Mercado"},
+		{"Uber viagem", TransactionTypeExpense, "Transporte"},
+		{"Uber centro", TransactionTypeExpense, "Transporte"},
+		{"Posto Shell gasolina", TransactionTypeExpense, "Transporte"},
+		{"Netflix assinatura", TransactionTypeExpense, "Assinaturas"},
+		{"Spotify assinatura", TransactionTypeExpense, "Assinaturas"},
+		{"Salário empresa", TransactionTypeIncome, "Salário"},
+		{"Estorno Uber", TransactionTypeIncome, "Reembolsos"},
+		{"Sem categoria", TransactionTypeExpense, ""},
+		{"12/2025", TransactionTypeExpense, "Outros"},
+	}
+	classifier := NewCategoryClassifier()
+	for _, h := range history {
+		classifier.Train(h.description, h.kind, h.category)
+	}
+	return classifier
+}
+
+func TestClassifierSuggest(t *testing.T) {
+	classifier := trainedClassifier()
+	tests := []struct {
+		description string
+		kind        TransactionType
+		want        string
+	}{
+		{"SUPERMERCADO CARREFOUR", TransactionTypeExpense, "Alimentação > Mercado"},
+		{"uber trip", TransactionTypeExpense, "Transporte"},
+		{"Estorno uber", TransactionTypeIncome, "Reembolsos"},
+		{"Assinatura Disney", TransactionTypeExpense, "Assinaturas"},
+		{"salario", TransactionTypeIncome, "Salário"},
+	}
+	for _, tt := range tests {
+		best, ok := classifier.Best(tt.description, tt.kind)
+		if !ok || best.Category != tt.want {
+			t.Errorf("Best(%q) = %+v, %v; want %q", tt.description, best, ok, tt.want)
+		}
+	}
+
+	if _, ok := classifier.Best("Farmácia", TransactionTypeExpense); ok {
+		t.Error("a description with only unknown words got a suggestion")
+	}
+	if _, ok := NewCategoryClassifier().Best("Supermercado", TransactionTypeExpense); ok {
+		t.Error("an untrained classifier made a suggestion")
+	}
+}
+
+func TestClassifierConfidence(t *testing.T) {
+	classifier := trainedClassifier()
+
+	suggestions := classifier.Suggest("Supermercado", TransactionTypeExpense, 0)
+	var total float64
+	for i, suggestion := range suggestions {
+		total += suggestion.Confidence
+		if i > 0 && suggestion.Confidence > suggestions[i-1].Confidence {
+			t.Errorf("suggestions are not sorted by confidence: %+v", suggestions)
+		}
+	}
+	// Part of the probability is kept for a category never seen in training
+	if total < 0.9 || total >= 1 {
+		t.Errorf("confidences add up to %f, want just under 1", total)
+	}
+	if suggestions[0].NeedsReview() {
+		t.Errorf("a word seen only in one category needs review: %+v", suggestions[0])
+	}
+	if limited := classifier.Suggest("Supermercado", TransactionTypeExpense, 2); len(limited) != 2 {
+		t.Errorf("Suggest with limit 2 returned %d suggestions", len(limited))
+	}
+
+	// "uber" was seen both as transport and as a refund; the type tells them apart
+	if best, _ := classifier.Best("Uber", TransactionTypeIncome); best.Category != "Reembolsos" {
+		t.Errorf("Best(Uber, income) = %+v, want Reembolsos", best)
+	}
+	if best, _ := classifier.Best("Uber", TransactionTypeExpense); best.Category != "Transporte" {
+		t.Errorf("Best(Uber, expense) = %+v, want Transporte", best)
+	}
+}
+
+func TestClassifierWithASingleCategory(t *testing.T) {
+	classifier := NewCategoryClassifier()
+	classifier.Train("Supermercado Extra", TransactionTypeExpense, "Mercado")
+	best, ok := classifier.Best("Supermercado", TransactionTypeExpense)
+	if !ok || best.Category != "Mercado" {
+		t.Fatalf("Best(Supermercado) = %+v, %v; want Mercado", best, ok)
+	}
+	if !best.NeedsReview() {
+		t.Errorf("a guess from a single example does not need review: %+v", best)
+	}
+
+	// Consistent history makes the only category a confident guess again
+	classifier.Train("Supermercado Dia", TransactionTypeExpense, "Mercado")
+	classifier.Train("Supermercado Assaí", TransactionTypeExpense, "Mercado")
+	if best, _ := classifier.Best("Supermercado", TransactionTypeExpense); best.NeedsReview() {
+		t.Errorf("after three examples the suggestion still needs review: %+v", best)
+	}
+}
+
+func TestTrainClassifierUsesSplitsAndSkipsTransfers(t *testing.T) {
+	tl := &TransactionList{}
+	tl.EnsureAccounts()
+	second, err := tl.AddAccount("Poupança", AccountTypeSavings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := tl.AddTransfer(tl.DefaultAccountID(), second.ID, NewMoney(1000, "BRL"), "Reserva mensal", date(2026, 1, 5)); err != nil {
+		t.Fatal(err)
+	}
+	tx := NewTransactionWithDate(TransactionTypeExpense, NewMoney(10000, "BRL"), "Atacadão", "", date(2026, 1, 6))
+	if err := tx.SetSplits([]Split{
+		{Value: NewMoney(-7000, "BRL"), Category: "Mercado"},
+		{Value: NewMoney(-3000, "BRL"), Category: "Limpeza", Description: "Detergente"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	tl.AddTransaction(tx)
+
+	classifier := tl.TrainClassifier()
+	if best, ok := classifier.Best("Atacadão", TransactionTypeExpense); !ok || best.Category != "Mercado" {
+		t.Errorf("Best(Atacadão) = %+v, %v; want Mercado", best, ok)
+	}
+	if best, ok := classifier.Best("Detergente", TransactionTypeExpense); !ok || best.Category != "Limpeza" {
+		t.Errorf("Best(Detergente) = %+v, %v; want Limpeza", best, ok)
+	}
+	if _, ok := classifier.Best("Reserva", TransactionTypeExpense); ok {
+		t.Error("the classifier learned from a transfer")
+	}
+}
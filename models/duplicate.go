@@ -0,0 +1,123 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultDuplicateDays is how many days apart a transaction may be from an imported row and still
+// count as the same one, since banks often post a few days after the purchase
+const DefaultDuplicateDays = 3
+
+// accentReplacer folds the accented letters used in Portuguese descriptions
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "ê", "e", "è", "e", "ë", "e",
+	"í", "i", "î", "i", "ì", "i", "ï", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ò", "o", "ö", "o",
+	"ú", "u", "û", "u", "ù", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// ImportFingerprint identifies an imported row by the account it was imported into, its cells and
+// how many identical rows came before it in the same file, so two equal purchases on the same day
+// get different fingerprints while re-importing the file reproduces them
+func ImportFingerprint(accountID int, cells []string, occurrence int) string {
+	parts := []string{strconv.Itoa(accountID), strconv.Itoa(occurrence)}
+	for _, cell := range cells {
+		parts = append(parts, strings.TrimSpace(cell))
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x1f")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// FindByImportID returns the transaction imported from the row with the given fingerprint
+func (tl *TransactionList) FindByImportID(importID string) (Transaction, bool) {
+	if importID == "" {
+		return Transaction{}, false
+	}
+	for _, tx := range tl.Transactions {
+		if tx.ImportID == importID {
+			return tx, true
+		}
+	}
+	return Transaction{}, false
+}
+
+// FindDuplicate returns an existing transaction of the same account that is likely the same as tx:
+// equal value, dates at most days apart and similar descriptions. Transactions in exclude are
+// skipped, so each existing transaction matches at most one imported row. The closest date wins.
+func (tl *TransactionList) FindDuplicate(tx Transaction, days int, exclude map[int]bool) (Transaction, bool) {
+	var best Transaction
+	bestDistance := days + 1
+	for _, existing := range tl.Transactions {
+		if exclude[existing.ID] || existing.AccountID != tx.AccountID || existing.Value != tx.Value {
+			continue
+		}
+		distance := daysApart(existing.Date, tx.Date)
+		if distance < bestDistance && SimilarDescriptions(existing.Description, tx.Description) {
+			best, bestDistance = existing, distance
+		}
+	}
+	return best, bestDistance <= days
+}
+
+// SimilarDescriptions reports whether two descriptions likely name the same transaction: ignoring
+// case, accents and punctuation they are equal, one contains the other, or they share at least
+// half of their words
+func SimilarDescriptions(a, b string) bool {
+	wordsA, wordsB := descriptionWords(a), descriptionWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return len(wordsA) == len(wordsB)
+	}
+	joinedA, joinedB := strings.Join(wordsA, " "), strings.Join(wordsB, " ")
+	if joinedA == joinedB {
+		return true
+	}
+	if shorter := min(len(joinedA), len(joinedB)); shorter >= 3 &&
+		(strings.Contains(joinedA, joinedB) || strings.Contains(joinedB, joinedA)) {
+		return true
+	}
+
+	set := make(map[string]bool, len(wordsA))
+	for _, word := range wordsA {
+		set[word] = true
+	}
+	shared, union := 0, len(set)
+	seen := make(map[string]bool, len(wordsB))
+	for _, word := range wordsB {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		if set[word] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	return shared*2 >= union
+}
+
+// descriptionWords splits a description into lowercase words without accents or punctuation
+func descriptionWords(s string) []string {
+	s = accentReplacer.Replace(strings.ToLower(s))
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// daysApart returns how many calendar days separate two dates, by their wall-clock day
+func daysApart(a, b time.Time) int {
+	dayA := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	dayB := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(dayA.Sub(dayB).Hours() / 24)
+	if days < 0 {
+		return -days
+	}
+	return days
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestImportFingerprint(t *testing.T) {
+	row := []string{"10/01/2026", "Padaria", "-12,50"}
+	fingerprint := ImportFingerprint(1, row, 0)
+	if again := ImportFingerprint(1, []string{" 10/01/2026", "Padaria ", "-12,50"}, 0); again != fingerprint {
+		t.Error("the fingerprint changed with the spacing of the cells")
+	}
+	if len(fingerprint) != 32 {
+		t.Errorf("fingerprint %q has %d characters, want 32", fingerprint, len(fingerprint))
+	}
+	for _, other := range []string{
+		ImportFingerprint(2, row, 0),
+		ImportFingerprint(1, row, 1),
+		ImportFingerprint(1, []string{"10/01/2026", "Padaria", "-12,51"}, 0),
+		// Cells are kept apart, so moving text between them changes the fingerprint
+		ImportFingerprint(1, []string{"10/01/2026 Padaria", "-12,50"}, 0),
+	} {
+		if other == fingerprint {
+			t.Error("different rows share a fingerprint")
+		}
+	}
+}
+
+func TestSimilarDescriptions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"PAG*IFOOD SAO PAULO", "Pag*iFood São Paulo", true},
+		{"Mercado", "Supermercado Extra", true},
+		{"Posto Shell Centro", "shell posto sul", true},
+		{"", "", true},
+		{"Uber trip", "Uber eats", false},
+		{"Farmácia", "Padaria", false},
+		{"ab", "abc", false},
+		{"", "Padaria", false},
+	}
+	for _, tt := range tests {
+		if got := SimilarDescriptions(tt.a, tt.b); got != tt.want {
+			t.Errorf("SimilarDescriptions(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFindDuplicate(t *testing.T) {
+	tl, _, savings := newAccountLedger(t)
+	add := func(amount int64, description string, day int, accountID int) Transaction {
+		tx := NewTransactionWithDate(TransactionTypeExpense, NewMoney(amount, "BRL"), description, "", date(2026, 1, day))
+		tx.AccountID = accountID
+		tx.ImportID = ImportFingerprint(accountID, []string{description}, day)
+		if err := tl.AddTransaction(tx); err != nil {
+			t.Fatal(err)
+		}
+		return tl.Transactions[len(tl.Transactions)-1]
+	}
+	checking := tl.DefaultAccountID()
+	early := add(5000, "Padaria Pão Quente", 8, checking)
+	nearest := add(5000, "Padaria Pao Quente", 11, checking)
+	add(5000, "Padaria Pão Quente", 10, savings.ID)
+	add(5001, "Padaria Pão Quente", 10, checking)
+
+	row := NewTransactionWithDate(TransactionTypeExpense, NewMoney(5000, "BRL"), "PADARIA PAO QUENTE LTDA", "", time.Date(2026, 1, 10, 23, 0, 0, 0, time.UTC))
+	row.AccountID = checking
+
+	// The closest date wins; other accounts and amounts never match
+	found, ok := tl.FindDuplicate(row, DefaultDuplicateDays, nil)
+	if !ok || found.ID != nearest.ID {
+		t.Fatalf("FindDuplicate = %+v, %v; want the transaction of the 11th", found, ok)
+	}
+	// A transaction matched by an earlier row is skipped
+	found, ok = tl.FindDuplicate(row, DefaultDuplicateDays, map[int]bool{nearest.ID: true})
+	if !ok || found.ID != early.ID {
+		t.Errorf("FindDuplicate excluding the 11th = %+v, %v; want the transaction of the 8th", found, ok)
+	}
+	if _, ok := tl.FindDuplicate(row, 1, map[int]bool{nearest.ID: true}); ok {
+		t.Error("a transaction two days apart matched with a one day window")
+	}
+	row.Description = "Farmácia"
+	if _, ok := tl.FindDuplicate(row, DefaultDuplicateDays, nil); ok {
+		t.Error("a different description matched")
+	}
+
+	if found, ok := tl.FindByImportID(early.ImportID); !ok || found.ID != early.ID {
+		t.Errorf("FindByImportID = %+v, %v", found, ok)
+	}
+	if _, ok := tl.FindByImportID(""); ok {
+		t.Error("an empty fingerprint matched a transaction")
+	}
+}
@@ -0,0 +1,228 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ErrExchangeRateNotFound is returned when no rate can convert between two currencies
+var ErrExchangeRateNotFound = errors.New("exchange rate not found")
+
+// ExchangeRate is the price of one unit of Currency in Quote currency on a given date
+type ExchangeRate struct {
+	Date     time.Time `json:"date"`
+	Currency string    `json:"currency"`
+	Quote    string    `json:"quote"`
+	Rate     float64   `json:"rate"`
+}
+
+// SupportedCurrencies returns the currencies offered in the entry form
+func SupportedCurrencies() []string {
+	return []string{"BRL", "USD", "EUR"}
+}
+
+// GetBaseCurrency returns the currency balances and reports are converted to
+func (tl *TransactionList) GetBaseCurrency() string {
+	return normalizeCurrency(tl.BaseCurrency)
+}
+
+// SetBaseCurrency changes the currency balances and reports are converted to
+func (tl *TransactionList) SetBaseCurrency(currency string) {
+	tl.BaseCurrency = normalizeCurrency(currency)
+}
+
+// SetExchangeRate stores a rate, replacing any rate for the same pair and day
+func (tl *TransactionList) SetExchangeRate(rate ExchangeRate) error {
+	rate.Currency = normalizeCurrency(rate.Currency)
+	rate.Quote = normalizeCurrency(rate.Quote)
+	if rate.Currency == rate.Quote {
+		return fmt.Errorf("exchange rate currencies must differ")
+	}
+	if rate.Rate <= 0 {
+		return fmt.Errorf("exchange rate must be positive")
+	}
+	rate.Date = truncateDay(rate.Date)
+
+	for i, existing := range tl.ExchangeRates {
+		if existing.Currency == rate.Currency && existing.Quote == rate.Quote && existing.Date.Equal(rate.Date) {
+			tl.ExchangeRates[i] = rate
+			return nil
+		}
+	}
+	tl.ExchangeRates = append(tl.ExchangeRates, rate)
+	sort.SliceStable(tl.ExchangeRates, func(i, j int) bool {
+		return tl.ExchangeRates[i].Date.Before(tl.ExchangeRates[j].Date)
+	})
+	return nil
+}
+
+// GetExchangeRates returns all stored rates ordered by date
+func (tl *TransactionList) GetExchangeRates() []ExchangeRate {
+	return tl.ExchangeRates
+}
+
+// Convert converts a value to another currency using the latest rate on or before date.
+// Rates are used directly, inverted, or crossed through a common quote currency.
+func (tl *TransactionList) Convert(value Money, currency string, date time.Time) (Money, error) {
+	from := normalizeCurrency(value.Currency)
+	to := normalizeCurrency(currency)
+	if from == to {
+		return NewMoney(value.Amount, to), nil
+	}
+
+	rate, err := tl.rate(from, to, date)
+	if err != nil {
+		return Money{}, fmt.Errorf("%s to %s on %s: %w", from, to, date.Format("2006-01-02"), err)
+	}
+	return NewMoney(int64(math.Round(float64(value.Amount)*rate)), to), nil
+}
+
+// ValueInBase returns the value of a transaction in the base currency, converted at the transaction date
+func (tl *TransactionList) ValueInBase(tx Transaction) (Money, error) {
+	return tl.Convert(tx.Value, tl.GetBaseCurrency(), tx.Date)
+}
+
+// BaseAmount converts an amount to the base currency at the given date. An amount without a rate
+// counts as zero, so totals leave it out; MissingRates lists the currencies that lack a rate.
+func (tl *TransactionList) BaseAmount(value Money, date time.Time) Money {
+	converted, err := tl.Convert(value, tl.GetBaseCurrency(), date)
+	if err != nil {
+		return NewMoney(0, tl.GetBaseCurrency())
+	}
+	return converted
+}
+
+// BaseValue converts a transaction to the base currency at its date; without a rate it counts as zero
+func (tl *TransactionList) BaseValue(tx Transaction) Money {
+	return tl.BaseAmount(tx.Value, tx.Date)
+}
+
+// MissingRate is a currency some amounts cannot be converted from, leaving them out of totals
+type MissingRate struct {
+	Currency string
+	// Since is the earliest date an amount in the currency has no rate for
+	Since time.Time
+	// Count is how many transactions, budgets and goals are affected
+	Count int
+}
+
+// Label describes the missing rate, e.g. "USD sem cotação desde 02/01/2026 (3 itens)"
+func (r MissingRate) Label() string {
+	return fmt.Sprintf("%s sem cotação desde %s (%d itens)", r.Currency, r.Since.Format("02/01/2006"), r.Count)
+}
+
+// MissingRates returns the currencies of transactions, budgets and goals that have no rate to the
+// base currency at their date, ordered by currency. Goal targets are converted at now.
+func (tl *TransactionList) MissingRates(now time.Time) []MissingRate {
+	missing := make(map[string]*MissingRate)
+	check := func(value Money, date time.Time) {
+		if _, err := tl.Convert(value, tl.GetBaseCurrency(), date); err == nil {
+			return
+		}
+		currency := normalizeCurrency(value.Currency)
+		rate, ok := missing[currency]
+		if !ok {
+			rate = &MissingRate{Currency: currency, Since: date}
+			missing[currency] = rate
+		}
+		if date.Before(rate.Since) {
+			rate.Since = date
+		}
+		rate.Count++
+	}
+	for _, tx := range tl.Transactions {
+		check(tx.Value, tx.Date)
+	}
+	for _, budget := range tl.Budgets {
+		check(budget.Limit, MonthPeriod(budget.Year, budget.Month, time.Local).Start)
+	}
+	for _, goal := range tl.Goals {
+		check(goal.Target, now)
+	}
+
+	rates := make([]MissingRate, 0, len(missing))
+	for _, rate := range missing {
+		rates = append(rates, *rate)
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].Currency < rates[j].Currency
+	})
+	return rates
+}
+
+func (tl *TransactionList) rate(from, to string, date time.Time) (float64, error) {
+	if rate, ok := tl.latestRate(from, to, date); ok {
+		return rate, nil
+	}
+	if rate, ok := tl.latestRate(to, from, date); ok {
+		return 1 / rate, nil
+	}
+
+	// Cross rate through any currency both sides are quoted in
+	for _, pivot := range tl.quoteCurrencies() {
+		fromPivot, ok := tl.directOrInverse(from, pivot, date)
+		if !ok {
+			continue
+		}
+		toPivot, ok := tl.directOrInverse(to, pivot, date)
+		if !ok {
+			continue
+		}
+		return fromPivot / toPivot, nil
+	}
+	return 0, ErrExchangeRateNotFound
+}
+
+func (tl *TransactionList) directOrInverse(from, to string, date time.Time) (float64, bool) {
+	if from == to {
+		return 1, true
+	}
+	if rate, ok := tl.latestRate(from, to, date); ok {
+		return rate, true
+	}
+	if rate, ok := tl.latestRate(to, from, date); ok {
+		return 1 / rate, true
+	}
+	return 0, false
+}
+
+// latestRate returns the most recent rate on or before date, or the earliest one if all are later
+func (tl *TransactionList) latestRate(currency, quote string, date time.Time) (float64, bool) {
+	day := truncateDay(date)
+	found := false
+	var best ExchangeRate
+	for _, rate := range tl.ExchangeRates {
+		if rate.Currency != currency || rate.Quote != quote {
+			continue
+		}
+		switch {
+		case !found:
+			best, found = rate, true
+		case !rate.Date.After(day) && (best.Date.After(day) || rate.Date.After(best.Date)):
+			best = rate
+		case best.Date.After(day) && rate.Date.Before(best.Date):
+			best = rate
+		}
+	}
+	return best.Rate, found
+}
+
+func (tl *TransactionList) quoteCurrencies() []string {
+	seen := make(map[string]bool)
+	var quotes []string
+	for _, rate := range tl.ExchangeRates {
+		if !seen[rate.Quote] {
+			seen[rate.Quote] = true
+			quotes = append(quotes, rate.Quote)
+		}
+	}
+	return quotes
+}
+
+// truncateDay returns midnight UTC of the calendar day of t, so rates match regardless of time zone
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
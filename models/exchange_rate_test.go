@@ -0,0 +1,111 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// newRateLedger returns a ledger in BRL with a USD rate of 5.00 from 2026-01-01 and a EUR rate
+// quoted only in USD
+func newRateLedger(t *testing.T) *TransactionList {
+	t.Helper()
+	tl := &TransactionList{BaseCurrency: "BRL"}
+	tl.EnsureAccounts()
+	for _, rate := range []ExchangeRate{
+		{Date: date(2026, 1, 1), Currency: "USD", Quote: "BRL", Rate: 5},
+		{Date: date(2026, 2, 1), Currency: "USD", Quote: "BRL", Rate: 5.5},
+		{Date: date(2026, 1, 1), Currency: "EUR", Quote: "USD", Rate: 1.2},
+	} {
+		if err := tl.SetExchangeRate(rate); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tl
+}
+
+func TestConvert(t *testing.T) {
+	tl := newRateLedger(t)
+	tests := []struct {
+		name     string
+		value    Money
+		currency string
+		date     time.Time
+		want     Money
+	}{
+		{"same currency", NewMoney(1234, "BRL"), "BRL", date(2025, 1, 1), NewMoney(1234, "BRL")},
+		{"direct rate", NewMoney(10000, "USD"), "BRL", date(2026, 1, 15), NewMoney(50000, "BRL")},
+		{"latest rate on or before the date", NewMoney(10000, "USD"), "BRL", date(2026, 2, 1), NewMoney(55000, "BRL")},
+		{"inverse rate", NewMoney(55000, "BRL"), "USD", date(2026, 3, 1), NewMoney(10000, "USD")},
+		{"cross rate", NewMoney(10000, "EUR"), "BRL", date(2026, 1, 15), NewMoney(60000, "BRL")},
+		{"earliest rate before the first quote", NewMoney(10000, "USD"), "BRL", date(2025, 12, 31), NewMoney(50000, "BRL")},
+	}
+	for _, tt := range tests {
+		got, err := tl.Convert(tt.value, tt.currency, tt.date)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := tl.Convert(NewMoney(10000, "GBP"), "BRL", date(2026, 1, 15)); !errors.Is(err, ErrExchangeRateNotFound) {
+		t.Errorf("converting without a rate: got %v, want ErrExchangeRateNotFound", err)
+	}
+}
+
+func TestAmountsWithoutRateAreLeftOutOfTotals(t *testing.T) {
+	tl := newRateLedger(t)
+	tl.AddTransaction(NewTransactionWithDate(TransactionTypeIncome, NewMoney(100000, "BRL"), "Salário", "", date(2026, 1, 5)))
+	tl.AddTransaction(NewTransactionWithDate(TransactionTypeExpense, NewMoney(1000, "USD"), "Livro", "", date(2026, 1, 10)))
+	// Neither GBP nor CHF has a rate
+	tl.AddTransaction(NewTransactionWithDate(TransactionTypeExpense, NewMoney(10000, "CHF"), "Hotel", "", date(2025, 12, 20)))
+	tl.AddTransaction(NewTransactionWithDate(TransactionTypeExpense, NewMoney(2000, "GBP"), "Museu", "", date(2026, 1, 12)))
+	tl.AddTransaction(NewTransactionWithDate(TransactionTypeExpense, NewMoney(3000, "GBP"), "Táxi", "", date(2026, 1, 11)))
+
+	if got, want := tl.GetBalance(), NewMoney(95000, "BRL"); got != want {
+		t.Errorf("GetBalance() = %v, want %v", got, want)
+	}
+	if got := tl.BaseAmount(NewMoney(2000, "GBP"), date(2026, 1, 12)); got != NewMoney(0, "BRL") {
+		t.Errorf("BaseAmount without a rate = %v, want zero in the base currency", got)
+	}
+
+	got := tl.MissingRates(date(2026, 3, 1))
+	want := []MissingRate{
+		{Currency: "CHF", Since: date(2025, 12, 20), Count: 1},
+		{Currency: "GBP", Since: date(2026, 1, 11), Count: 2},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("MissingRates() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].Currency != want[i].Currency || !got[i].Since.Equal(want[i].Since) || got[i].Count != want[i].Count {
+			t.Errorf("MissingRates()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if label := got[1].Label(); label != "GBP sem cotação desde 11/01/2026 (2 itens)" {
+		t.Errorf("Label() = %q", label)
+	}
+}
+
+func TestMissingRatesCoversBudgetsAndGoals(t *testing.T) {
+	tl := newRateLedger(t)
+	if _, err := tl.SetBudget("Viagem", 2026, time.March, NewMoney(50000, "GBP")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tl.AddGoal(Goal{Name: "Carro", Target: NewMoney(100000, "JPY"), Deadline: date(2027, 1, 1), Category: "Carro"}); err != nil {
+		t.Fatal(err)
+	}
+
+	missing := tl.MissingRates(date(2026, 3, 1))
+	if len(missing) != 2 || missing[0].Currency != "GBP" || missing[1].Currency != "JPY" {
+		t.Fatalf("MissingRates() = %+v, want GBP and JPY", missing)
+	}
+
+	statuses := tl.GetBudgetStatus(2026, time.March)
+	if len(statuses) != 1 || !statuses[0].Budget.Limit.IsZero() || statuses[0].Budget.Limit.Currency != "BRL" {
+		t.Errorf("budget limit without a rate = %+v, want zero in BRL", statuses)
+	}
+}
@@ -0,0 +1,170 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrGoalNotFound is returned when no savings goal has the requested ID
+var ErrGoalNotFound = errors.New("goal not found")
+
+// Goal is an amount to save by a deadline. Progress comes either from the balance of a linked
+// account or from the transactions of a linked category.
+type Goal struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Target    Money     `json:"target"`
+	Deadline  time.Time `json:"deadline"`
+	AccountID int       `json:"account_id,omitempty"`
+	Category  string    `json:"category,omitempty"`
+}
+
+// GoalStatus is the progress of a goal at a given moment, in the base currency
+type GoalStatus struct {
+	Goal      Goal
+	Saved     Money
+	Remaining Money
+	// MonthsLeft counts the monthly contributions still possible before the deadline
+	MonthsLeft int
+	// MonthlyNeeded is the contribution per month that reaches the target by the deadline
+	MonthlyNeeded Money
+}
+
+// Reached reports whether the saved amount covers the target
+func (gs GoalStatus) Reached() bool {
+	return gs.Remaining.Amount <= 0
+}
+
+// Overdue reports whether the deadline passed without reaching the target
+func (gs GoalStatus) Overdue() bool {
+	return gs.MonthsLeft == 0 && !gs.Reached()
+}
+
+// Percent returns the saved amount as a percentage of the target, between 0 and 100
+func (gs GoalStatus) Percent() float64 {
+	if gs.Goal.Target.IsZero() || gs.Saved.IsNegative() {
+		return 0
+	}
+	percent := float64(gs.Saved.Amount) / float64(gs.Goal.Target.Amount) * 100
+	if percent > 100 {
+		return 100
+	}
+	return percent
+}
+
+// AddGoal validates and stores a new savings goal
+func (tl *TransactionList) AddGoal(goal Goal) (Goal, error) {
+	goal.Name = strings.TrimSpace(goal.Name)
+	goal.Category = NormalizeCategory(goal.Category)
+	if goal.Name == "" {
+		return Goal{}, fmt.Errorf("goal name is required")
+	}
+	if goal.Target.IsNegative() || goal.Target.IsZero() {
+		return Goal{}, fmt.Errorf("goal target must be positive")
+	}
+	if goal.Deadline.IsZero() {
+		return Goal{}, fmt.Errorf("goal deadline is required")
+	}
+	if (goal.AccountID == 0) == (goal.Category == "") {
+		return Goal{}, fmt.Errorf("goal must be linked to either an account or a category")
+	}
+	if goal.AccountID != 0 {
+		if _, err := tl.GetAccountByID(goal.AccountID); err != nil {
+			return Goal{}, err
+		}
+	}
+
+	goal.ID = 1
+	for _, existing := range tl.Goals {
+		if existing.ID >= goal.ID {
+			goal.ID = existing.ID + 1
+		}
+	}
+	tl.Goals = append(tl.Goals, goal)
+	return goal, nil
+}
+
+// DeleteGoal removes a savings goal; its transactions are kept
+func (tl *TransactionList) DeleteGoal(id int) error {
+	for i, goal := range tl.Goals {
+		if goal.ID == id {
+			tl.Goals = append(tl.Goals[:i], tl.Goals[i+1:]...)
+			return nil
+		}
+	}
+	return ErrGoalNotFound
+}
+
+// GetGoals returns the savings goals ordered by deadline
+func (tl *TransactionList) GetGoals() []Goal {
+	goals := append([]Goal(nil), tl.Goals...)
+	sort.SliceStable(goals, func(i, j int) bool {
+		return goals[i].Deadline.Before(goals[j].Deadline)
+	})
+	return goals
+}
+
+// GetGoalStatus returns the progress of every goal at now. An account goal has saved the
+// account balance; a category goal has saved its expenses (contributions) minus its income
+// (withdrawals) in the category and its subcategories.
+func (tl *TransactionList) GetGoalStatus(now time.Time) []GoalStatus {
+	base := tl.GetBaseCurrency()
+	var statuses []GoalStatus
+	for _, goal := range tl.GetGoals() {
+		saved := NewMoney(0, base)
+		if goal.AccountID != 0 {
+			saved = tl.GetAccountBalance(goal.AccountID)
+		} else {
+			for _, tx := range tl.Transactions {
+				if tx.Type == TransactionTypeTransfer {
+					continue
+				}
+				for _, part := range tx.Parts() {
+					if IsInCategory(part.Category, goal.Category) {
+						saved = saved.Sub(tl.BaseAmount(part.Value, tx.Date))
+					}
+				}
+			}
+		}
+
+		target := tl.BaseAmount(goal.Target, now)
+		goal.Target = target
+		remaining := target.Sub(saved)
+
+		status := GoalStatus{
+			Goal:          goal,
+			Saved:         saved,
+			Remaining:     remaining,
+			MonthsLeft:    monthsUntil(now, goal.Deadline),
+			MonthlyNeeded: NewMoney(0, base),
+		}
+		if remaining.Amount > 0 {
+			months := int64(status.MonthsLeft)
+			if months == 0 {
+				months = 1
+			}
+			// Round up so the contributions never fall short of the target
+			status.MonthlyNeeded = NewMoney((remaining.Amount+months-1)/months, base)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// monthsUntil counts the monthly steps from now that still fall on or before the deadline's day,
+// counting the current month, so a deadline later this month leaves one contribution
+func monthsUntil(now, deadline time.Time) int {
+	today := NewPeriod(PeriodDay, now, 0).Start
+	last := time.Date(deadline.Year(), deadline.Month(), deadline.Day(), 0, 0, 0, 0, now.Location())
+	if last.Before(today) {
+		return 0
+	}
+	months := 1
+	for !today.AddDate(0, months, 0).After(last) {
+		months++
+	}
+	return months
+}
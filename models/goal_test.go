@@ -0,0 +1,110 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMonthsUntil(t *testing.T) {
+	now := time.Date(2026, 1, 15, 18, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		deadline time.Time
+		want     int
+	}{
+		{"yesterday", date(2026, 1, 14), 0},
+		{"today", date(2026, 1, 15), 1},
+		{"later this month", date(2026, 1, 31), 1},
+		{"the day before a month from now", date(2026, 2, 14), 1},
+		{"a month from now", date(2026, 2, 15), 2},
+		{"five months from now", date(2026, 6, 15), 6},
+		{"a day short of a year", date(2027, 1, 14), 12},
+	}
+	for _, tt := range tests {
+		if got := monthsUntil(now, tt.deadline); got != tt.want {
+			t.Errorf("%s: monthsUntil = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAddGoal(t *testing.T) {
+	tl, _, savings := newAccountLedger(t)
+	valid := Goal{Name: " Viagem ", Target: NewMoney(600000, "BRL"), Deadline: date(2026, 12, 1), Category: "Metas>Viagem"}
+	goal, err := tl.AddGoal(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if goal.ID != 1 || goal.Name != "Viagem" || goal.Category != "Metas > Viagem" {
+		t.Errorf("goal = %+v", goal)
+	}
+
+	tests := []struct {
+		name   string
+		change func(*Goal)
+	}{
+		{"no name", func(g *Goal) { g.Name = "" }},
+		{"no target", func(g *Goal) { g.Target = NewMoney(0, "BRL") }},
+		{"negative target", func(g *Goal) { g.Target = NewMoney(-1, "BRL") }},
+		{"no deadline", func(g *Goal) { g.Deadline = time.Time{} }},
+		{"neither account nor category", func(g *Goal) { g.Category = "" }},
+		{"both account and category", func(g *Goal) { g.AccountID = savings.ID }},
+		{"missing account", func(g *Goal) { g.Category, g.AccountID = "", 99 }},
+	}
+	for _, tt := range tests {
+		invalid := valid
+		tt.change(&invalid)
+		if _, err := tl.AddGoal(invalid); err == nil {
+			t.Errorf("%s: the goal was added", tt.name)
+		}
+	}
+
+	if err := tl.DeleteGoal(goal.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := tl.DeleteGoal(goal.ID); !errors.Is(err, ErrGoalNotFound) {
+		t.Errorf("deleting twice error = %v, want ErrGoalNotFound", err)
+	}
+}
+
+func TestGetGoalStatus(t *testing.T) {
+	tl, checking, savings := newAccountLedger(t)
+	now := date(2026, 3, 10)
+	if _, _, err := tl.AddTransfer(checking.ID, savings.ID, NewMoney(250000, "BRL"), "Reserva", date(2026, 2, 1)); err != nil {
+		t.Fatal(err)
+	}
+	contribution := NewTransactionWithDate(TransactionTypeExpense, NewMoney(100000, "BRL"), "Aporte", "Metas > Viagem > Passagens", date(2026, 2, 5))
+	withdrawal := NewTransactionWithDate(TransactionTypeIncome, NewMoney(20000, "BRL"), "Resgate", "Metas > Viagem", date(2026, 3, 1))
+	for _, tx := range []Transaction{contribution, withdrawal} {
+		if err := tl.AddTransaction(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, goal := range []Goal{
+		{Name: "Reserva", Target: NewMoney(200000, "BRL"), Deadline: date(2026, 2, 1), AccountID: savings.ID},
+		{Name: "Viagem", Target: NewMoney(600000, "BRL"), Deadline: date(2026, 8, 10), Category: "Metas > Viagem"},
+		{Name: "Carro", Target: NewMoney(1000000, "BRL"), Deadline: date(2026, 3, 1), Category: "Metas > Carro"},
+	} {
+		if _, err := tl.AddGoal(goal); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	statuses := tl.GetGoalStatus(now)
+	if len(statuses) != 3 {
+		t.Fatalf("%d statuses, want 3", len(statuses))
+	}
+	// Ordered by deadline
+	reserve, car, travel := statuses[0], statuses[1], statuses[2]
+	if reserve.Saved.Amount != 250000 || !reserve.Reached() || reserve.Overdue() || reserve.Percent() != 100 || !reserve.MonthlyNeeded.IsZero() {
+		t.Errorf("Reserva = %+v", reserve)
+	}
+	// 1000.00 contributed and 200.00 withdrawn; 5200.00 left over six months, rounded up
+	if travel.Saved.Amount != 80000 || travel.Remaining.Amount != 520000 || travel.MonthsLeft != 6 || travel.MonthlyNeeded.Amount != 86667 {
+		t.Errorf("Viagem = saved %v, remaining %v, %d months, %v a month", travel.Saved, travel.Remaining, travel.MonthsLeft, travel.MonthlyNeeded)
+	}
+	if !car.Overdue() || car.Percent() != 0 || car.MonthlyNeeded.Amount != 1000000 {
+		t.Errorf("Carro = %+v, want overdue with the whole target due now", car)
+	}
+}
@@ -0,0 +1,346 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrLoanNotFound is returned when no loan has the requested ID
+var ErrLoanNotFound = errors.New("loan not found")
+
+// AmortizationSystem is how a loan's principal is paid back
+type AmortizationSystem string
+
+const (
+	// AmortizationPrice has fixed installments; the interest share shrinks over time
+	AmortizationPrice AmortizationSystem = "Price"
+	// AmortizationSAC has fixed amortization; installments shrink over time
+	AmortizationSAC AmortizationSystem = "SAC"
+)
+
+// AmortizationSystems returns the supported amortization systems
+func AmortizationSystems() []AmortizationSystem {
+	return []AmortizationSystem{AmortizationPrice, AmortizationSAC}
+}
+
+// LoanPayment links an installment to the transaction that paid it
+type LoanPayment struct {
+	Number        int `json:"number"`
+	TransactionID int `json:"transaction_id"`
+}
+
+// Loan is a loan or financing paid in monthly installments
+type Loan struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Principal Money  `json:"principal"`
+	// MonthlyRate is the interest rate per month in percent, e.g. 1.5 for 1.5% a.m.
+	MonthlyRate float64            `json:"monthly_rate"`
+	Term        int                `json:"term"`
+	System      AmortizationSystem `json:"system"`
+	// FirstDueDate is the due date of the first installment; the others follow monthly on the same day
+	FirstDueDate time.Time     `json:"first_due_date"`
+	AccountID    int           `json:"account_id"`
+	Payments     []LoanPayment `json:"payments,omitempty"`
+}
+
+// Installment is a row of an amortization schedule. Balance is the principal still owed after it.
+type Installment struct {
+	Number        int
+	DueDate       time.Time
+	Payment       Money
+	Interest      Money
+	Amortization  Money
+	Balance       Money
+	TransactionID int
+}
+
+// IsPaid reports whether the installment is linked to a transaction
+func (i Installment) IsPaid() bool {
+	return i.TransactionID != 0
+}
+
+// LoanStatus is a loan with its schedule and totals as of its paid installments
+type LoanStatus struct {
+	Loan          Loan
+	Schedule      []Installment
+	PaidCount     int
+	Outstanding   Money
+	InterestPaid  Money
+	TotalInterest Money
+}
+
+// NextInstallment returns the first unpaid installment, or false when the loan is paid off
+func (ls LoanStatus) NextInstallment() (Installment, bool) {
+	for _, installment := range ls.Schedule {
+		if !installment.IsPaid() {
+			return installment, true
+		}
+	}
+	return Installment{}, false
+}
+
+// Validate checks that a schedule can be generated for the loan
+func (l Loan) Validate() error {
+	if strings.TrimSpace(l.Name) == "" {
+		return fmt.Errorf("loan name is required")
+	}
+	if l.Principal.IsNegative() || l.Principal.IsZero() {
+		return fmt.Errorf("loan principal must be positive")
+	}
+	if l.MonthlyRate < 0 {
+		return fmt.Errorf("loan rate cannot be negative")
+	}
+	if l.Term <= 0 {
+		return fmt.Errorf("loan term must be at least one month")
+	}
+	if l.System != AmortizationPrice && l.System != AmortizationSAC {
+		return fmt.Errorf("invalid amortization system %q", l.System)
+	}
+	if l.FirstDueDate.IsZero() {
+		return fmt.Errorf("loan first due date is required")
+	}
+	return nil
+}
+
+// Schedule returns the full amortization table. Interest is rounded to the cent each month and the
+// last installment absorbs the rounding so the balance ends at exactly zero.
+func (l Loan) Schedule() []Installment {
+	currency := l.Principal.Currency
+	rate := l.MonthlyRate / 100
+	balance := l.Principal.Amount
+
+	// Price: fixed payment from the annuity formula; SAC: fixed amortization
+	var fixedPayment, fixedAmortization int64
+	if l.System == AmortizationPrice && rate > 0 {
+		fixedPayment = int64(math.Round(float64(balance) * rate / (1 - math.Pow(1+rate, -float64(l.Term)))))
+	} else {
+		fixedAmortization = balance / int64(l.Term)
+	}
+
+	schedule := make([]Installment, 0, l.Term)
+	for n := 1; n <= l.Term; n++ {
+		interest := int64(math.Round(float64(balance) * rate))
+		amortization := fixedAmortization
+		if fixedPayment > 0 {
+			amortization = fixedPayment - interest
+		}
+		if n == l.Term || amortization > balance {
+			amortization = balance
+		}
+		balance -= amortization
+
+		schedule = append(schedule, Installment{
+			Number:       n,
+			DueDate:      addMonthsClamped(l.FirstDueDate, n-1),
+			Payment:      NewMoney(interest+amortization, currency),
+			Interest:     NewMoney(interest, currency),
+			Amortization: NewMoney(amortization, currency),
+			Balance:      NewMoney(balance, currency),
+		})
+	}
+	return schedule
+}
+
+// AddLoan validates and stores a new loan
+func (tl *TransactionList) AddLoan(loan Loan) (Loan, error) {
+	loan.Name = strings.TrimSpace(loan.Name)
+	if err := loan.Validate(); err != nil {
+		return Loan{}, err
+	}
+	if loan.AccountID == 0 {
+		loan.AccountID = tl.DefaultAccountID()
+	}
+	if _, err := tl.GetAccountByID(loan.AccountID); err != nil {
+		return Loan{}, err
+	}
+
+	loan.ID = 1
+	for _, existing := range tl.Loans {
+		if existing.ID >= loan.ID {
+			loan.ID = existing.ID + 1
+		}
+	}
+	loan.Payments = nil
+	tl.Loans = append(tl.Loans, loan)
+	return loan, nil
+}
+
+// GetLoans returns all loans
+func (tl *TransactionList) GetLoans() []Loan {
+	return tl.Loans
+}
+
+// DeleteLoan removes a loan; the transactions that paid its installments are kept
+func (tl *TransactionList) DeleteLoan(id int) error {
+	for i, loan := range tl.Loans {
+		if loan.ID == id {
+			tl.Loans = append(tl.Loans[:i], tl.Loans[i+1:]...)
+			return nil
+		}
+	}
+	return ErrLoanNotFound
+}
+
+// GetLoanStatus returns the schedule of a loan with its paid installments, the outstanding
+// principal and the interest paid so far. Payments whose transaction was deleted count as unpaid.
+func (tl *TransactionList) GetLoanStatus(id int) (LoanStatus, error) {
+	loan := tl.loan(id)
+	if loan == nil {
+		return LoanStatus{}, ErrLoanNotFound
+	}
+
+	currency := loan.Principal.Currency
+	status := LoanStatus{
+		Loan:          *loan,
+		Schedule:      loan.Schedule(),
+		Outstanding:   loan.Principal,
+		InterestPaid:  NewMoney(0, currency),
+		TotalInterest: NewMoney(0, currency),
+	}
+	for i := range status.Schedule {
+		installment := &status.Schedule[i]
+		status.TotalInterest = status.TotalInterest.Add(installment.Interest)
+		for _, payment := range loan.Payments {
+			if payment.Number == installment.Number && tl.hasID(payment.TransactionID) {
+				installment.TransactionID = payment.TransactionID
+			}
+		}
+		if installment.IsPaid() {
+			status.PaidCount++
+			status.Outstanding = status.Outstanding.Sub(installment.Amortization)
+			status.InterestPaid = status.InterestPaid.Add(installment.Interest)
+		}
+	}
+	return status, nil
+}
+
+// LinkLoanInstallment marks an installment as paid by an existing transaction. A transaction
+// pays a single installment, so one already linked elsewhere is rejected.
+func (tl *TransactionList) LinkLoanInstallment(loanID, number, transactionID int) error {
+	loan := tl.loan(loanID)
+	if loan == nil {
+		return ErrLoanNotFound
+	}
+	if number < 1 || number > loan.Term {
+		return fmt.Errorf("installment %d is outside the loan term of %d months", number, loan.Term)
+	}
+	if !tl.hasID(transactionID) {
+		return ErrTransactionNotFound
+	}
+	for _, other := range tl.Loans {
+		for _, payment := range other.Payments {
+			if payment.TransactionID != transactionID || (other.ID == loanID && payment.Number == number) {
+				continue
+			}
+			return fmt.Errorf("transaction %d already pays installment %d of %s", transactionID, payment.Number, other.Name)
+		}
+	}
+
+	for i, payment := range loan.Payments {
+		if payment.Number == number {
+			loan.Payments[i].TransactionID = transactionID
+			return nil
+		}
+	}
+	loan.Payments = append(loan.Payments, LoanPayment{Number: number, TransactionID: transactionID})
+	return nil
+}
+
+// PayLoanInstallment records the payment of an installment as an expense on the loan's account
+// dated date, and links it to the installment
+func (tl *TransactionList) PayLoanInstallment(loanID, number int, date time.Time) (Transaction, error) {
+	status, err := tl.GetLoanStatus(loanID)
+	if err != nil {
+		return Transaction{}, err
+	}
+	if number < 1 || number > len(status.Schedule) {
+		return Transaction{}, fmt.Errorf("installment %d is outside the loan term of %d months", number, status.Loan.Term)
+	}
+	installment := status.Schedule[number-1]
+	if installment.IsPaid() {
+		return Transaction{}, fmt.Errorf("installment %d is already paid", number)
+	}
+
+	tx := status.Loan.installmentTransaction(installment, date)
+	if err := tl.AddTransaction(tx); err != nil {
+		return Transaction{}, err
+	}
+
+	tx = tl.Transactions[len(tl.Transactions)-1]
+	if err := tl.LinkLoanInstallment(loanID, number, tx.ID); err != nil {
+		return Transaction{}, err
+	}
+	return tx, nil
+}
+
+// UpcomingLoanInstallments returns the unpaid installments of every loan due after from and up to
+// until, as expenses on their due dates. They are not stored; forecasts use them as scheduled items.
+func (tl *TransactionList) UpcomingLoanInstallments(from, until time.Time) []Transaction {
+	var upcoming []Transaction
+	for _, loan := range tl.Loans {
+		status, err := tl.GetLoanStatus(loan.ID)
+		if err != nil {
+			continue
+		}
+		for _, installment := range status.Schedule {
+			if installment.IsPaid() || !installment.DueDate.After(from) || installment.DueDate.After(until) {
+				continue
+			}
+			upcoming = append(upcoming, loan.installmentTransaction(installment, installment.DueDate))
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].Date.Before(upcoming[j].Date)
+	})
+	return upcoming
+}
+
+// IsLoanPayment reports whether a transaction is linked to a loan installment
+func (tl *TransactionList) IsLoanPayment(transactionID int) bool {
+	for _, loan := range tl.Loans {
+		for _, payment := range loan.Payments {
+			if payment.TransactionID == transactionID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// installmentTransaction builds the expense that pays an installment on date. It has no ID
+// until it is stored, so forecasts can build it without using up IDs.
+func (l Loan) installmentTransaction(installment Installment, date time.Time) Transaction {
+	description := fmt.Sprintf("%s - parcela %d/%d", l.Name, installment.Number, l.Term)
+	return Transaction{
+		Type:        TransactionTypeExpense,
+		Value:       TransactionTypeExpense.SignedValue(installment.Payment),
+		Description: description,
+		Category:    "Financiamentos" + CategorySeparator + l.Name,
+		Date:        date,
+		AccountID:   l.AccountID,
+	}
+}
+
+func (tl *TransactionList) loan(id int) *Loan {
+	for i := range tl.Loans {
+		if tl.Loans[i].ID == id {
+			return &tl.Loans[i]
+		}
+	}
+	return nil
+}
+
+// addMonthsClamped adds months to a date, keeping its day or the last day of shorter months
+func addMonthsClamped(date time.Time, months int) time.Time {
+	first := time.Date(date.Year(), date.Month()+time.Month(months), 1, date.Hour(), date.Minute(), date.Second(), date.Nanosecond(), date.Location())
+	day := date.Day()
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
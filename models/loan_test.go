@@ -0,0 +1,230 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoanSchedule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		loan                  Loan
+		firstPayment          int64
+		firstInterest         int64
+		lastPayment           int64
+		lastAmortization      int64
+		totalInterest         int64
+		fixedPaymentUntilLast bool
+	}{
+		{
+			name:                  "price 100k at 1% over 12 months",
+			loan:                  Loan{Principal: NewMoney(10000000, "BRL"), MonthlyRate: 1, Term: 12, System: AmortizationPrice},
+			firstPayment:          888488,
+			firstInterest:         100000,
+			lastPayment:           888485,
+			lastAmortization:      879688,
+			totalInterest:         661853,
+			fixedPaymentUntilLast: true,
+		},
+		{
+			name:                  "price 10k at 1% over 12 months",
+			loan:                  Loan{Principal: NewMoney(1000000, "BRL"), MonthlyRate: 1, Term: 12, System: AmortizationPrice},
+			firstPayment:          88849,
+			firstInterest:         10000,
+			lastPayment:           88847,
+			lastAmortization:      87967,
+			totalInterest:         66186,
+			fixedPaymentUntilLast: true,
+		},
+		{
+			name:             "sac 100k at 1% over 12 months",
+			loan:             Loan{Principal: NewMoney(10000000, "BRL"), MonthlyRate: 1, Term: 12, System: AmortizationSAC},
+			firstPayment:     933333,
+			firstInterest:    100000,
+			lastPayment:      841670,
+			lastAmortization: 833337,
+			totalInterest:    650000,
+		},
+		{
+			name:                  "price without interest",
+			loan:                  Loan{Principal: NewMoney(100000, "BRL"), Term: 3, System: AmortizationPrice},
+			firstPayment:          33333,
+			lastPayment:           33334,
+			lastAmortization:      33334,
+			fixedPaymentUntilLast: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := tt.loan.Schedule()
+			if len(schedule) != tt.loan.Term {
+				t.Fatalf("schedule has %d installments, want %d", len(schedule), tt.loan.Term)
+			}
+			first, last := schedule[0], schedule[len(schedule)-1]
+			if first.Payment.Amount != tt.firstPayment || first.Interest.Amount != tt.firstInterest {
+				t.Errorf("first installment = %v (interest %v), want %d (interest %d)", first.Payment, first.Interest, tt.firstPayment, tt.firstInterest)
+			}
+			// The last installment absorbs the rounding of every month
+			if last.Payment.Amount != tt.lastPayment || last.Amortization.Amount != tt.lastAmortization {
+				t.Errorf("last installment = %v (amortization %v), want %d (amortization %d)", last.Payment, last.Amortization, tt.lastPayment, tt.lastAmortization)
+			}
+			if !last.Balance.IsZero() {
+				t.Errorf("final balance = %v, want zero", last.Balance)
+			}
+
+			var amortized, interest int64
+			balance := tt.loan.Principal.Amount
+			for i, installment := range schedule {
+				amortized += installment.Amortization.Amount
+				interest += installment.Interest.Amount
+				balance -= installment.Amortization.Amount
+				if installment.Number != i+1 || installment.Balance.Amount != balance {
+					t.Errorf("installment %d: number %d, balance %v, want balance %d", i+1, installment.Number, installment.Balance, balance)
+				}
+				if installment.Payment != installment.Interest.Add(installment.Amortization) {
+					t.Errorf("installment %d: payment %v is not interest %v plus amortization %v", i+1, installment.Payment, installment.Interest, installment.Amortization)
+				}
+				if tt.fixedPaymentUntilLast && i < len(schedule)-1 && installment.Payment != first.Payment {
+					t.Errorf("installment %d: payment %v, want the fixed %v", i+1, installment.Payment, first.Payment)
+				}
+			}
+			if amortized != tt.loan.Principal.Amount {
+				t.Errorf("amortizations add up to %d, want the principal %d", amortized, tt.loan.Principal.Amount)
+			}
+			if interest != tt.totalInterest {
+				t.Errorf("total interest = %d, want %d", interest, tt.totalInterest)
+			}
+		})
+	}
+}
+
+func TestLoanDueDatesKeepTheDay(t *testing.T) {
+	loan := Loan{Principal: NewMoney(300000, "BRL"), MonthlyRate: 1, Term: 3, System: AmortizationSAC, FirstDueDate: date(2026, 1, 31)}
+	want := []string{"2026-01-31", "2026-02-28", "2026-03-31"}
+	for i, installment := range loan.Schedule() {
+		if got := installment.DueDate.Format("2006-01-02"); got != want[i] {
+			t.Errorf("installment %d due %s, want %s", i+1, got, want[i])
+		}
+	}
+}
+
+func TestLoanValidate(t *testing.T) {
+	valid := Loan{Name: "Carro", Principal: NewMoney(100000, "BRL"), MonthlyRate: 1, Term: 12, System: AmortizationPrice, FirstDueDate: date(2026, 1, 10)}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("valid loan: %v", err)
+	}
+
+	tests := []struct {
+		change func(*Loan)
+		want   string
+	}{
+		{func(l *Loan) { l.Name = " " }, "name is required"},
+		{func(l *Loan) { l.Principal = NewMoney(0, "BRL") }, "principal must be positive"},
+		{func(l *Loan) { l.MonthlyRate = -0.5 }, "rate cannot be negative"},
+		{func(l *Loan) { l.Term = 0 }, "term must be at least one month"},
+		{func(l *Loan) { l.System = "Americano" }, "invalid amortization system"},
+		{func(l *Loan) { l.FirstDueDate = time.Time{} }, "first due date is required"},
+	}
+	for _, tt := range tests {
+		loan := valid
+		tt.change(&loan)
+		if err := loan.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Validate() = %v, want an error with %q", err, tt.want)
+		}
+	}
+}
+
+func TestLoanPayments(t *testing.T) {
+	tl := &TransactionList{}
+	tl.EnsureAccounts()
+	loan, err := tl.AddLoan(Loan{Name: "Carro", Principal: NewMoney(10000000, "BRL"), MonthlyRate: 1, Term: 12, System: AmortizationPrice, FirstDueDate: date(2026, 1, 10)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := tl.PayLoanInstallment(loan.ID, 1, date(2026, 1, 9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Value.Amount != -888488 || first.Category != "Financiamentos > Carro" || first.Description != "Carro - parcela 1/12" {
+		t.Errorf("payment transaction = %+v", first)
+	}
+	if _, err := tl.PayLoanInstallment(loan.ID, 1, date(2026, 1, 9)); err == nil {
+		t.Error("an installment was paid twice")
+	}
+	if _, err := tl.PayLoanInstallment(loan.ID, 13, date(2026, 1, 9)); err == nil {
+		t.Error("an installment outside the term was paid")
+	}
+	second, err := tl.PayLoanInstallment(loan.ID, 2, date(2026, 2, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := tl.GetLoanStatus(loan.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 7884.88 and 7963.73 amortized, 1000.00 and 921.15 of interest
+	if status.PaidCount != 2 || status.Outstanding.Amount != 10000000-788488-796373 || status.InterestPaid.Amount != 100000+92115 {
+		t.Errorf("status after two payments = %d paid, outstanding %v, interest %v", status.PaidCount, status.Outstanding, status.InterestPaid)
+	}
+	if !tl.IsLoanPayment(first.ID) || tl.IsLoanPayment(first.ID+100) {
+		t.Error("IsLoanPayment does not follow the linked transactions")
+	}
+	before := generateID()
+	if upcoming := tl.UpcomingLoanInstallments(date(2026, 2, 10), date(2026, 4, 10)); len(upcoming) != 2 || !upcoming[0].Date.Equal(date(2026, 3, 10)) || upcoming[0].ID != 0 {
+		t.Errorf("upcoming installments = %+v, want March and April without IDs", upcoming)
+	}
+	if next := generateID(); next != before+1 {
+		t.Errorf("projecting installments used up %d IDs", next-before-1)
+	}
+
+	// A payment whose transaction was deleted counts as unpaid again
+	if err := tl.DeleteTransaction(second.ID); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := tl.GetLoanStatus(loan.ID); status.PaidCount != 1 || status.Schedule[1].IsPaid() {
+		t.Errorf("after deleting the payment, %d installments are paid", status.PaidCount)
+	}
+	if _, err := tl.PayLoanInstallment(loan.ID, 2, date(2026, 2, 11)); err != nil {
+		t.Errorf("paying again after the payment was deleted: %v", err)
+	}
+}
+
+func TestLinkLoanInstallmentRejectsLinkedTransactions(t *testing.T) {
+	tl := &TransactionList{}
+	tl.EnsureAccounts()
+	car, err := tl.AddLoan(Loan{Name: "Carro", Principal: NewMoney(10000000, "BRL"), MonthlyRate: 1, Term: 12, System: AmortizationPrice, FirstDueDate: date(2026, 1, 10)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	house, err := tl.AddLoan(Loan{Name: "Casa", Principal: NewMoney(50000000, "BRL"), MonthlyRate: 1, Term: 24, System: AmortizationSAC, FirstDueDate: date(2026, 1, 15)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payment := NewTransactionWithDate(TransactionTypeExpense, NewMoney(888488, "BRL"), "Parcela", "", date(2026, 1, 10))
+	if err := tl.AddTransaction(payment); err != nil {
+		t.Fatal(err)
+	}
+	id := tl.Transactions[0].ID
+
+	if err := tl.LinkLoanInstallment(car.ID, 1, id); err != nil {
+		t.Fatal(err)
+	}
+	if err := tl.LinkLoanInstallment(car.ID, 1, id); err != nil {
+		t.Errorf("linking the same installment again: %v", err)
+	}
+	if err := tl.LinkLoanInstallment(car.ID, 2, id); err == nil {
+		t.Error("a transaction paid two installments of the same loan")
+	}
+	if err := tl.LinkLoanInstallment(house.ID, 1, id); err == nil {
+		t.Error("a transaction paid installments of two loans")
+	}
+	if status, _ := tl.GetLoanStatus(car.ID); status.PaidCount != 1 {
+		t.Errorf("%d car installments paid, want 1", status.PaidCount)
+	}
+	if status, _ := tl.GetLoanStatus(house.ID); status.PaidCount != 0 {
+		t.Errorf("%d house installments paid, want 0", status.PaidCount)
+	}
+}
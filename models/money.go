@@ -0,0 +1,251 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrCurrencyMismatch is returned when combining amounts in different currencies
+var ErrCurrencyMismatch = errors.New("currencies differ")
+
+// DefaultCurrency is the currency assumed for values without an explicit one
+const DefaultCurrency = "BRL"
+
+// minorUnitsPerUnit is the number of minor units (centavos) in one unit
+const minorUnitsPerUnit = 100
+
+// Money represents an exact monetary amount stored in integer minor units
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// NewMoney creates a money value from an amount in minor units
+func NewMoney(minorUnits int64, currency string) Money {
+	return Money{Amount: minorUnits, Currency: normalizeCurrency(currency)}
+}
+
+// MoneyFromFloat creates a money value from a float, rounding half away from zero to the nearest minor unit
+func MoneyFromFloat(value float64, currency string) Money {
+	return NewMoney(int64(math.Round(value*minorUnitsPerUnit)), currency)
+}
+
+// ParseMoney parses a decimal amount such as "1234.56", "-250,00" or "R$ 1.234,56". A single
+// separator followed by exactly three digits, as in "1.234", is rejected when it could group
+// thousands as well as be a third decimal place.
+func ParseMoney(s string, currency string) (Money, error) {
+	str := strings.TrimSpace(s)
+	for _, symbol := range []string{"R$", "US$", "$", "€"} {
+		str = strings.TrimSpace(strings.Replace(str, symbol, "", 1))
+	}
+	str = strings.ReplaceAll(str, " ", "")
+	if str == "" {
+		return Money{}, fmt.Errorf("empty amount")
+	}
+
+	negative := false
+	switch str[0] {
+	case '-':
+		negative = true
+		str = str[1:]
+	case '+':
+		str = str[1:]
+	}
+
+	// The last separator is the decimal one; any other separator groups thousands
+	intPart, fracPart := str, ""
+	if idx := strings.LastIndexAny(str, ".,"); idx >= 0 {
+		sep := str[idx]
+		tail := str[idx+1:]
+		if strings.Count(str, string(sep)) > 1 {
+			intPart = str
+		} else if len(tail) == 3 && isDigits(tail) && couldGroupThousands(str[:idx]) {
+			return Money{}, fmt.Errorf("ambiguous amount %q: use two decimal places or group thousands with the other separator", s)
+		} else {
+			intPart, fracPart = str[:idx], tail
+		}
+	}
+	intPart = strings.NewReplacer(".", "", ",", "").Replace(intPart)
+	if intPart == "" {
+		intPart = "0"
+	}
+
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+
+	units, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+
+	var minor int64
+	for i := 0; i < 2; i++ {
+		minor *= 10
+		if i < len(fracPart) {
+			minor += int64(fracPart[i] - '0')
+		}
+	}
+	// Round half away from zero on the first discarded digit
+	if len(fracPart) > 2 && fracPart[2] >= '5' {
+		minor++
+	}
+
+	if units > (math.MaxInt64-minor)/minorUnitsPerUnit {
+		return Money{}, fmt.Errorf("amount %q is too large", s)
+	}
+	amount := units*minorUnitsPerUnit + minor
+	if negative {
+		amount = -amount
+	}
+	return NewMoney(amount, currency), nil
+}
+
+// Add returns the sum of two money values. Both must be in the same currency, or one of them
+// without a currency, which holds for totals built from BaseValue and BaseAmount. It panics
+// otherwise; use TryAdd when the operands come from user data that may mix currencies.
+func (m Money) Add(other Money) Money {
+	sum, err := m.TryAdd(other)
+	if err != nil {
+		panic("money: " + err.Error())
+	}
+	return sum
+}
+
+// Sub returns the difference of two money values under the same invariant as Add; use TrySub
+// when the operands may be in different currencies
+func (m Money) Sub(other Money) Money {
+	difference, err := m.TrySub(other)
+	if err != nil {
+		panic("money: " + err.Error())
+	}
+	return difference
+}
+
+// TryAdd returns the sum of two money values, or an error wrapping ErrCurrencyMismatch when
+// both have a currency and they differ
+func (m Money) TryAdd(other Money) (Money, error) {
+	currency, err := m.mergeCurrency(other)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: currency}, nil
+}
+
+// TrySub returns the difference of two money values, or an error wrapping ErrCurrencyMismatch
+// when both have a currency and they differ
+func (m Money) TrySub(other Money) (Money, error) {
+	return m.TryAdd(other.Neg())
+}
+
+// Neg returns the money value with its sign inverted
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// Abs returns the absolute money value
+func (m Money) Abs() Money {
+	if m.Amount < 0 {
+		return m.Neg()
+	}
+	return m
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative reports whether the amount is below zero
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Float64 returns the amount in major units, for display and spreadsheet cells only
+func (m Money) Float64() float64 {
+	return float64(m.Amount) / minorUnitsPerUnit
+}
+
+// Decimal formats the amount as a plain decimal string such as "-1234.56"
+func (m Money) Decimal() string {
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/minorUnitsPerUnit, amount%minorUnitsPerUnit)
+}
+
+// String formats the amount with its currency symbol, e.g. "R$ 1234.56"
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", CurrencySymbol(m.Currency), m.Decimal())
+}
+
+// UnmarshalJSON accepts both the current object form and legacy plain float values
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var legacy float64
+	if err := json.Unmarshal(data, &legacy); err == nil {
+		*m = MoneyFromFloat(legacy, DefaultCurrency)
+		return nil
+	}
+
+	type rawMoney Money
+	var raw rawMoney
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid money value: %w", err)
+	}
+	*m = NewMoney(raw.Amount, raw.Currency)
+	return nil
+}
+
+// CurrencySymbol returns the display symbol for a currency code
+func CurrencySymbol(currency string) string {
+	switch normalizeCurrency(currency) {
+	case "BRL":
+		return "R$"
+	case "USD":
+		return "US$"
+	case "EUR":
+		return "€"
+	default:
+		return normalizeCurrency(currency)
+	}
+}
+
+func (m Money) mergeCurrency(other Money) (string, error) {
+	switch {
+	case m.Currency == "":
+		return other.Currency, nil
+	case other.Currency == "" || other.Currency == m.Currency:
+		return m.Currency, nil
+	default:
+		return "", fmt.Errorf("cannot combine %s and %s amounts: %w", m.Currency, other.Currency, ErrCurrencyMismatch)
+	}
+}
+
+func normalizeCurrency(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return DefaultCurrency
+	}
+	return currency
+}
+
+// couldGroupThousands reports whether s could be the digits before the first thousands separator
+func couldGroupThousands(s string) bool {
+	return len(s) >= 1 && len(s) <= 3 && s[0] != '0' && isDigits(s)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
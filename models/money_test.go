@@ -0,0 +1,192 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in       string
+		currency string
+		want     Money
+	}{
+		{"1234.56", "BRL", NewMoney(123456, "BRL")},
+		{"-250,00", "BRL", NewMoney(-25000, "BRL")},
+		{"+10", "BRL", NewMoney(1000, "BRL")},
+		{"R$ 1.234,56", "BRL", NewMoney(123456, "BRL")},
+		{"US$ 1,234.56", "USD", NewMoney(123456, "USD")},
+		{"1.234.567", "BRL", NewMoney(123456700, "BRL")},
+		{"1,234,567.8", "USD", NewMoney(123456780, "USD")},
+		{"0,5", "BRL", NewMoney(50, "BRL")},
+		{",99", "BRL", NewMoney(99, "BRL")},
+		{"10.", "BRL", NewMoney(1000, "BRL")},
+		{"1.2345", "BRL", NewMoney(123, "BRL")},
+		{"1234.567", "BRL", NewMoney(123457, "BRL")},
+		{"0.125", "BRL", NewMoney(13, "BRL")},
+		{"-0.995", "", NewMoney(-100, "BRL")},
+		{"1,234.565", "USD", NewMoney(123457, "USD")},
+		{" 42 ", "eur", NewMoney(4200, "EUR")},
+		{"92233720368547758.07", "BRL", NewMoney(9223372036854775807, "BRL")},
+		{"-92233720368547758.07", "BRL", NewMoney(-9223372036854775807, "BRL")},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in, tt.currency)
+		if err != nil {
+			t.Errorf("ParseMoney(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseMoneyErrors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", "empty amount"},
+		{"R$", "empty amount"},
+		{"abc", "invalid amount"},
+		{"12a.50", "invalid amount"},
+		{"1.5e3", "invalid amount"},
+		{"--5", "invalid amount"},
+		{"1.234", "ambiguous amount"},
+		{"-1,234", "ambiguous amount"},
+		{"100,000", "ambiguous amount"},
+		{"99999999999999999", "too large"},
+		{"92233720368547758.08", "too large"},
+		{"99999999999999999999", "invalid amount"},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in, "BRL")
+		if err == nil {
+			t.Errorf("ParseMoney(%q) = %v, want an error", tt.in, got)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseMoney(%q) error %q, want it to mention %q", tt.in, err, tt.want)
+		}
+	}
+}
+
+func TestMoneyFormatting(t *testing.T) {
+	tests := []struct {
+		money   Money
+		decimal string
+		str     string
+	}{
+		{NewMoney(123456, "BRL"), "1234.56", "R$ 1234.56"},
+		{NewMoney(-5, "USD"), "-0.05", "US$ -0.05"},
+		{NewMoney(0, "EUR"), "0.00", "€ 0.00"},
+		{NewMoney(100, "gbp"), "1.00", "GBP 1.00"},
+		{NewMoney(-100000, ""), "-1000.00", "R$ -1000.00"},
+	}
+	for _, tt := range tests {
+		if got := tt.money.Decimal(); got != tt.decimal {
+			t.Errorf("%+v.Decimal() = %q, want %q", tt.money, got, tt.decimal)
+		}
+		if got := tt.money.String(); got != tt.str {
+			t.Errorf("%+v.String() = %q, want %q", tt.money, got, tt.str)
+		}
+	}
+}
+
+func TestParseMoneyRoundTrip(t *testing.T) {
+	for _, amount := range []int64{0, 1, -1, 99, 100, -12345, 9223372036854775807} {
+		money := NewMoney(amount, "BRL")
+		parsed, err := ParseMoney(money.Decimal(), "BRL")
+		if err != nil || parsed != money {
+			t.Errorf("ParseMoney(%q) = %v, %v; want %v", money.Decimal(), parsed, err, money)
+		}
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	brl := func(amount int64) Money { return NewMoney(amount, "BRL") }
+	tests := []struct {
+		name string
+		got  Money
+		want Money
+	}{
+		{"add", brl(1050).Add(brl(-2075)), brl(-1025)},
+		{"sub", brl(1050).Sub(brl(2075)), brl(-1025)},
+		{"neg", brl(1050).Neg(), brl(-1050)},
+		{"abs of negative", brl(-1050).Abs(), brl(1050)},
+		{"abs of positive", brl(1050).Abs(), brl(1050)},
+		{"zero value takes the other currency", Money{}.Add(NewMoney(5, "USD")), NewMoney(5, "USD")},
+		{"adding a zero value keeps the currency", NewMoney(5, "USD").Sub(Money{}), NewMoney(5, "USD")},
+		{"float rounds half away from zero", MoneyFromFloat(-0.125, "BRL"), brl(-13)},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, tt.got, tt.want)
+		}
+	}
+	if !brl(0).IsZero() || brl(1).IsZero() || !brl(-1).IsNegative() || brl(0).IsNegative() {
+		t.Error("IsZero/IsNegative disagree with the amount")
+	}
+}
+
+func TestMoneyMixedCurrenciesPanic(t *testing.T) {
+	for name, op := range map[string]func(){
+		"add": func() { NewMoney(100, "USD").Add(NewMoney(100, "BRL")) },
+		"sub": func() { NewMoney(100, "USD").Sub(NewMoney(100, "BRL")) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("combining USD and BRL did not panic")
+				}
+			}()
+			op()
+		})
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+	}{
+		{`{"amount": 123456, "currency": "usd"}`, NewMoney(123456, "USD")},
+		{`{"amount": -5}`, NewMoney(-5, "BRL")},
+		{`-250.5`, NewMoney(-25050, "BRL")},
+		{`0.1`, NewMoney(10, "BRL")},
+	}
+	for _, tt := range tests {
+		var got Money
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+			t.Errorf("Unmarshal(%s) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+	var invalid Money
+	if err := json.Unmarshal([]byte(`"ten"`), &invalid); err == nil {
+		t.Error("Unmarshal of a string succeeded")
+	}
+}
+
+func TestMoneyTryAdd(t *testing.T) {
+	sum, err := NewMoney(1050, "USD").TryAdd(NewMoney(-2075, "USD"))
+	if err != nil || sum != NewMoney(-1025, "USD") {
+		t.Errorf("TryAdd = %+v, %v", sum, err)
+	}
+	difference, err := NewMoney(1050, "USD").TrySub(Money{})
+	if err != nil || difference != NewMoney(1050, "USD") {
+		t.Errorf("TrySub of a zero value = %+v, %v", difference, err)
+	}
+	if _, err := NewMoney(100, "USD").TryAdd(NewMoney(100, "BRL")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("TryAdd of USD and BRL error = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := NewMoney(100, "USD").TrySub(NewMoney(100, "BRL")); err == nil || !strings.Contains(err.Error(), "USD and BRL") {
+		t.Errorf("TrySub of USD and BRL error = %v, want one naming both currencies", err)
+	}
+}
@@ -0,0 +1,219 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// PeriodKind is the granularity of a reporting period
+type PeriodKind string
+
+const (
+	PeriodDay         PeriodKind = "Dia"
+	PeriodWeek        PeriodKind = "Semana"
+	PeriodMonth       PeriodKind = "Mês"
+	PeriodQuarter     PeriodKind = "Trimestre"
+	PeriodYear        PeriodKind = "Ano"
+	PeriodFiscalMonth PeriodKind = "Mês Fiscal"
+	PeriodCustom      PeriodKind = "Personalizado"
+)
+
+// PeriodKinds returns the kinds that can be navigated from a reference date
+func PeriodKinds() []PeriodKind {
+	return []PeriodKind{PeriodDay, PeriodWeek, PeriodMonth, PeriodFiscalMonth, PeriodQuarter, PeriodYear}
+}
+
+// monthNames are the Portuguese month names used in period labels
+var monthNames = []string{"janeiro", "fevereiro", "março", "abril", "maio", "junho",
+	"julho", "agosto", "setembro", "outubro", "novembro", "dezembro"}
+
+// Period is a half-open range of time [Start, End). A zero Start or End leaves that side
+// unbounded, so the zero Period covers all time.
+type Period struct {
+	Kind  PeriodKind
+	Start time.Time
+	End   time.Time
+	// PayDay is the first day of a fiscal month
+	PayDay int
+}
+
+// NewPeriod returns the period of the given kind that contains date, in the location of date.
+// Weeks start on Monday; fiscal months start on payDay, clamped to the last day of short months.
+func NewPeriod(kind PeriodKind, date time.Time, payDay int) Period {
+	loc := date.Location()
+	year, month, day := date.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, loc)
+
+	switch kind {
+	case PeriodWeek:
+		offset := (int(midnight.Weekday()) + 6) % 7
+		start := midnight.AddDate(0, 0, -offset)
+		return Period{Kind: kind, Start: start, End: start.AddDate(0, 0, 7)}
+	case PeriodMonth:
+		return MonthPeriod(year, month, loc)
+	case PeriodQuarter:
+		start := time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, loc)
+		return Period{Kind: kind, Start: start, End: start.AddDate(0, 3, 0)}
+	case PeriodYear:
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+		return Period{Kind: kind, Start: start, End: start.AddDate(1, 0, 0)}
+	case PeriodFiscalMonth:
+		start := fiscalMonthStart(year, month, payDay, loc)
+		if midnight.Before(start) {
+			start = fiscalMonthStart(year, month-1, payDay, loc)
+		}
+		end := fiscalMonthStart(start.Year(), start.Month()+1, payDay, loc)
+		return Period{Kind: kind, Start: start, End: end, PayDay: payDay}
+	default:
+		return Period{Kind: PeriodDay, Start: midnight, End: midnight.AddDate(0, 0, 1)}
+	}
+}
+
+// MonthPeriod returns a calendar month in the given location
+func MonthPeriod(year int, month time.Month, loc *time.Location) Period {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	return Period{Kind: PeriodMonth, Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+// CustomPeriod returns the range [start, end); either side may be zero to leave it open
+func CustomPeriod(start, end time.Time) Period {
+	return Period{Kind: PeriodCustom, Start: start, End: end}
+}
+
+// CustomDayPeriod returns the range from the first day through the last day, both included
+func CustomDayPeriod(first, last time.Time) Period {
+	return CustomPeriod(NewPeriod(PeriodDay, first, 0).Start, NewPeriod(PeriodDay, last, 0).End)
+}
+
+// IsAll reports whether the period is unbounded on both sides
+func (p Period) IsAll() bool {
+	return p.Start.IsZero() && p.End.IsZero()
+}
+
+// Contains reports whether t falls within the period. Times are compared by the wall-clock date
+// and time they show, so a date recorded in another time zone (e.g. midnight UTC from an import)
+// stays on its calendar day.
+func (p Period) Contains(t time.Time) bool {
+	if p.IsAll() {
+		return true
+	}
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), p.location())
+	if !p.Start.IsZero() && wall.Before(p.Start) {
+		return false
+	}
+	return p.End.IsZero() || wall.Before(p.End)
+}
+
+// Intersect returns the range covered by both periods
+func (p Period) Intersect(other Period) Period {
+	if p.IsAll() {
+		return other
+	}
+	if other.IsAll() {
+		return p
+	}
+
+	result := CustomPeriod(p.Start, p.End)
+	if !other.Start.IsZero() && (result.Start.IsZero() || other.Start.After(result.Start)) {
+		result.Start = other.Start
+	}
+	if !other.End.IsZero() && (result.End.IsZero() || other.End.Before(result.End)) {
+		result.End = other.End
+	}
+	return result
+}
+
+// Next returns the following period of the same kind; custom periods move by their own length
+func (p Period) Next() Period {
+	if p.Kind == PeriodCustom || p.Start.IsZero() || p.End.IsZero() {
+		return p.shift(1)
+	}
+	return NewPeriod(p.Kind, p.End, p.PayDay)
+}
+
+// Previous returns the preceding period of the same kind; custom periods move by their own length
+func (p Period) Previous() Period {
+	if p.Kind == PeriodCustom || p.Start.IsZero() || p.End.IsZero() {
+		return p.shift(-1)
+	}
+	return NewPeriod(p.Kind, p.Start.AddDate(0, 0, -1), p.PayDay)
+}
+
+// Label describes the period for display, e.g. "março/2025" or "10/03/2025 a 09/04/2025"
+func (p Period) Label() string {
+	const layout = "02/01/2006"
+	switch {
+	case p.IsAll():
+		return "Todo o período"
+	case p.Start.IsZero():
+		return fmt.Sprintf("até %s", p.End.AddDate(0, 0, -1).Format(layout))
+	case p.End.IsZero():
+		return fmt.Sprintf("desde %s", p.Start.Format(layout))
+	}
+
+	switch p.Kind {
+	case PeriodDay:
+		return p.Start.Format(layout)
+	case PeriodMonth:
+		return fmt.Sprintf("%s/%d", monthNames[p.Start.Month()-1], p.Start.Year())
+	case PeriodQuarter:
+		return fmt.Sprintf("%dº trimestre/%d", (int(p.Start.Month())-1)/3+1, p.Start.Year())
+	case PeriodYear:
+		return fmt.Sprintf("%d", p.Start.Year())
+	default:
+		return fmt.Sprintf("%s a %s", p.Start.Format(layout), p.End.AddDate(0, 0, -1).Format(layout))
+	}
+}
+
+func (p Period) shift(direction int) Period {
+	if p.Start.IsZero() || p.End.IsZero() {
+		return p
+	}
+	length := p.End.Sub(p.Start)
+	return Period{
+		Kind:   p.Kind,
+		Start:  p.Start.Add(time.Duration(direction) * length),
+		End:    p.End.Add(time.Duration(direction) * length),
+		PayDay: p.PayDay,
+	}
+}
+
+func (p Period) location() *time.Location {
+	if !p.Start.IsZero() {
+		return p.Start.Location()
+	}
+	if !p.End.IsZero() {
+		return p.End.Location()
+	}
+	return time.Local
+}
+
+// fiscalMonthStart returns the payday of a month, clamped to its last day
+func fiscalMonthStart(year int, month time.Month, payDay int, loc *time.Location) time.Time {
+	if payDay < 1 {
+		payDay = 1
+	}
+	return dayInMonth(year, month, payDay, loc)
+}
+
+// GetFiscalMonthStart returns the payday on which fiscal months start (1 when not configured)
+func (tl *TransactionList) GetFiscalMonthStart() int {
+	if tl.FiscalMonthStart < 1 {
+		return 1
+	}
+	return tl.FiscalMonthStart
+}
+
+// SetFiscalMonthStart sets the payday on which fiscal months start
+func (tl *TransactionList) SetFiscalMonthStart(day int) error {
+	if day < 1 || day > 31 {
+		return fmt.Errorf("invalid fiscal month start day %d", day)
+	}
+	tl.FiscalMonthStart = day
+	return nil
+}
+
+// PeriodContaining returns the period of a kind that contains date, using the configured payday
+func (tl *TransactionList) PeriodContaining(kind PeriodKind, date time.Time) Period {
+	return NewPeriod(kind, date, tl.GetFiscalMonthStart())
+}
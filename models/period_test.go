@@ -0,0 +1,186 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// newYork has daylight saving time: clocks go forward on 2026-03-08 and back on 2026-11-01
+func newYork(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	return loc
+}
+
+func TestNewPeriodBoundaries(t *testing.T) {
+	tests := []struct {
+		name       string
+		kind       PeriodKind
+		date       time.Time
+		start, end time.Time
+		label      string
+	}{
+		{"day", PeriodDay, time.Date(2026, 3, 14, 18, 30, 0, 0, time.UTC), date(2026, 3, 14), date(2026, 3, 15), "14/03/2026"},
+		{"week starts on monday", PeriodWeek, date(2026, 3, 8), date(2026, 3, 2), date(2026, 3, 9), "02/03/2026 a 08/03/2026"},
+		{"week across the year", PeriodWeek, date(2026, 1, 1), date(2025, 12, 29), date(2026, 1, 5), "29/12/2025 a 04/01/2026"},
+		{"month", PeriodMonth, date(2026, 2, 28), date(2026, 2, 1), date(2026, 3, 1), "fevereiro/2026"},
+		{"december ends in the next year", PeriodMonth, date(2026, 12, 31), date(2026, 12, 1), date(2027, 1, 1), "dezembro/2026"},
+		{"quarter", PeriodQuarter, date(2026, 11, 5), date(2026, 10, 1), date(2027, 1, 1), "4º trimestre/2026"},
+		{"year", PeriodYear, date(2026, 7, 1), date(2026, 1, 1), date(2027, 1, 1), "2026"},
+	}
+	for _, tt := range tests {
+		period := NewPeriod(tt.kind, tt.date, 0)
+		if !period.Start.Equal(tt.start) || !period.End.Equal(tt.end) {
+			t.Errorf("%s: got [%v, %v), want [%v, %v)", tt.name, period.Start, period.End, tt.start, tt.end)
+		}
+		if label := period.Label(); label != tt.label {
+			t.Errorf("%s: Label() = %q, want %q", tt.name, label, tt.label)
+		}
+	}
+}
+
+func TestFiscalMonthLateInTheMonth(t *testing.T) {
+	tests := []struct {
+		name       string
+		payDay     int
+		date       time.Time
+		start, end time.Time
+	}{
+		{"day 31 before a short month's payday", 31, date(2026, 2, 15), date(2026, 1, 31), date(2026, 2, 28)},
+		{"day 31 clamped to february", 31, date(2026, 2, 28), date(2026, 2, 28), date(2026, 3, 31)},
+		{"day 31 clamped to a 30-day month", 31, date(2026, 4, 30), date(2026, 4, 30), date(2026, 5, 31)},
+		{"day 31 on the payday", 31, date(2026, 1, 31), date(2026, 1, 31), date(2026, 2, 28)},
+		{"day 30 at the start of march", 30, date(2026, 3, 1), date(2026, 2, 28), date(2026, 3, 30)},
+		{"day 29 in a leap year", 29, date(2028, 2, 29), date(2028, 2, 29), date(2028, 3, 29)},
+		{"day 29 in a common year", 29, date(2026, 2, 28), date(2026, 2, 28), date(2026, 3, 29)},
+		{"day 29 the day before", 29, date(2026, 3, 28), date(2026, 2, 28), date(2026, 3, 29)},
+	}
+	for _, tt := range tests {
+		period := NewPeriod(PeriodFiscalMonth, tt.date, tt.payDay)
+		if !period.Start.Equal(tt.start) || !period.End.Equal(tt.end) {
+			t.Errorf("%s: got [%v, %v), want [%v, %v)", tt.name, period.Start, period.End, tt.start, tt.end)
+		}
+		if !period.Contains(tt.date) {
+			t.Errorf("%s: period does not contain %v", tt.name, tt.date)
+		}
+	}
+}
+
+func TestFiscalMonthsFollowEachOther(t *testing.T) {
+	for _, payDay := range []int{1, 29, 30, 31} {
+		period := NewPeriod(PeriodFiscalMonth, date(2027, 12, 15), payDay)
+		for i := 0; i < 26; i++ {
+			next := period.Next()
+			if !next.Start.Equal(period.End) || !next.End.After(next.Start) {
+				t.Fatalf("payday %d: %v is not followed by %v", payDay, period, next)
+			}
+			if previous := next.Previous(); !previous.Start.Equal(period.Start) || !previous.End.Equal(period.End) {
+				t.Fatalf("payday %d: going back from %v gives %v, want %v", payDay, next, previous, period)
+			}
+			period = next
+		}
+	}
+}
+
+func TestPeriodsAcrossDaylightSaving(t *testing.T) {
+	loc := newYork(t)
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, loc)
+	}
+
+	spring := NewPeriod(PeriodDay, at(time.March, 8, 12), 0)
+	if !spring.Start.Equal(at(time.March, 8, 0)) || !spring.End.Equal(at(time.March, 9, 0)) {
+		t.Errorf("spring forward day = [%v, %v)", spring.Start, spring.End)
+	}
+	if length := spring.End.Sub(spring.Start); length != 23*time.Hour {
+		t.Errorf("spring forward day lasts %v, want 23h", length)
+	}
+	if next := spring.Next(); !next.Start.Equal(at(time.March, 9, 0)) || next.Start.Hour() != 0 {
+		t.Errorf("day after spring forward starts at %v, want midnight", next.Start)
+	}
+
+	fall := NewPeriod(PeriodDay, at(time.November, 1, 12), 0)
+	if length := fall.End.Sub(fall.Start); length != 25*time.Hour {
+		t.Errorf("fall back day lasts %v, want 25h", length)
+	}
+	if previous := fall.Previous(); !previous.Start.Equal(at(time.October, 31, 0)) {
+		t.Errorf("day before fall back starts at %v, want midnight of October 31", previous.Start)
+	}
+
+	week := NewPeriod(PeriodWeek, at(time.March, 8, 23), 0)
+	if !week.Start.Equal(at(time.March, 2, 0)) || !week.End.Equal(at(time.March, 9, 0)) {
+		t.Errorf("week with the change = [%v, %v)", week.Start, week.End)
+	}
+	if next := week.Next(); !next.Start.Equal(at(time.March, 9, 0)) || !next.End.Equal(at(time.March, 16, 0)) {
+		t.Errorf("week after the change = [%v, %v)", next.Start, next.End)
+	}
+
+	month := NewPeriod(PeriodMonth, at(time.March, 20, 0), 0)
+	if !month.End.Equal(at(time.April, 1, 0)) || !month.Contains(at(time.March, 31, 23)) {
+		t.Errorf("March ends at %v", month.End)
+	}
+	if label := month.Label(); label != "março/2026" {
+		t.Errorf("Label() = %q", label)
+	}
+}
+
+func TestPeriodContains(t *testing.T) {
+	march := MonthPeriod(2026, time.March, time.UTC)
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{"start is included", march.Start, true},
+		{"just before the start", march.Start.Add(-time.Nanosecond), false},
+		{"last instant", march.End.Add(-time.Nanosecond), true},
+		{"end is excluded", march.End, false},
+		{"wall clock of another zone", time.Date(2026, 3, 31, 23, 0, 0, 0, time.FixedZone("BRT", -3*3600)), true},
+		{"midnight in another zone on the end day", time.Date(2026, 4, 1, 0, 0, 0, 0, time.FixedZone("BRT", -3*3600)), false},
+	}
+	for _, tt := range tests {
+		if got := march.Contains(tt.t); got != tt.want {
+			t.Errorf("%s: Contains(%v) = %v, want %v", tt.name, tt.t, got, tt.want)
+		}
+	}
+
+	until := CustomPeriod(time.Time{}, date(2026, 4, 1))
+	if !until.Contains(date(1990, 1, 1)) || until.Contains(date(2026, 4, 1)) {
+		t.Error("a period open at the start must contain everything before its end, and not the end")
+	}
+	since := CustomPeriod(date(2026, 4, 1), time.Time{})
+	if since.Contains(date(2026, 3, 31)) || !since.Contains(date(2100, 1, 1)) {
+		t.Error("a period open at the end must contain everything from its start")
+	}
+	if !(Period{}).Contains(time.Time{}) {
+		t.Error("the zero period must contain everything")
+	}
+
+	days := CustomDayPeriod(date(2026, 3, 10), date(2026, 3, 12))
+	if !days.Contains(time.Date(2026, 3, 12, 23, 59, 0, 0, time.UTC)) || days.Contains(date(2026, 3, 13)) {
+		t.Errorf("CustomDayPeriod must include its last day and nothing after: %+v", days)
+	}
+}
+
+func TestPeriodIntersect(t *testing.T) {
+	march := MonthPeriod(2026, time.March, time.UTC)
+	tests := []struct {
+		name       string
+		other      Period
+		start, end time.Time
+	}{
+		{"all time", Period{}, march.Start, march.End},
+		{"open start", CustomPeriod(time.Time{}, date(2026, 3, 15)), march.Start, date(2026, 3, 15)},
+		{"open end", CustomPeriod(date(2026, 3, 10), time.Time{}), date(2026, 3, 10), march.End},
+		{"wider", CustomPeriod(date(2026, 1, 1), date(2027, 1, 1)), march.Start, march.End},
+	}
+	for _, tt := range tests {
+		got := march.Intersect(tt.other)
+		if !got.Start.Equal(tt.start) || !got.End.Equal(tt.end) {
+			t.Errorf("%s: got [%v, %v), want [%v, %v)", tt.name, got.Start, got.End, tt.start, tt.end)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrPurchaseNotFound is returned when no installment purchase has the requested ID
+var ErrPurchaseNotFound = errors.New("installment purchase not found")
+
+// ErrPurchaseCurrency is returned when an installment is moved out of its purchase's currency
+var ErrPurchaseCurrency = errors.New("installments must stay in the currency of their purchase")
+
+// PurchaseState tells whether the remaining installments of a purchase are still due
+type PurchaseState string
+
+const (
+	PurchaseActive    PurchaseState = "Ativa"
+	PurchasePrepaid   PurchaseState = "Antecipada"
+	PurchaseCancelled PurchaseState = "Cancelada"
+)
+
+// InstallmentPurchase is a purchase paid in monthly installments, such as "10x sem juros".
+// Each installment is a regular expense transaction carrying the purchase ID.
+type InstallmentPurchase struct {
+	ID           int           `json:"id"`
+	Description  string        `json:"description"`
+	Total        Money         `json:"total"`
+	Installments int           `json:"installments"`
+	Category     string        `json:"category"`
+	AccountID    int           `json:"account_id"`
+	FirstDate    time.Time     `json:"first_date"`
+	State        PurchaseState `json:"state"`
+}
+
+// PurchaseStatus is the progress of an installment purchase at a given moment
+type PurchaseStatus struct {
+	Purchase      InstallmentPurchase
+	Paid          int
+	PaidAmount    Money
+	Pending       int
+	PendingAmount Money
+	// NextDate is the date of the next pending installment, zero when none is left
+	NextDate time.Time
+}
+
+// IsCommitted reports whether the transaction is an installment still to come: it belongs to an
+// installment purchase and is dated after the day of now
+func (t Transaction) IsCommitted(now time.Time) bool {
+	return t.PurchaseID != 0 && !t.Date.Before(NewPeriod(PeriodDay, now, 0).End)
+}
+
+// SplitInstallments divides a total into n installments. Cents that do not divide evenly go
+// to the first installment, as card issuers do.
+func SplitInstallments(total Money, n int) []Money {
+	if n <= 0 {
+		return nil
+	}
+	amount, remainder := total.Amount/int64(n), total.Amount%int64(n)
+	values := make([]Money, n)
+	for i := range values {
+		values[i] = NewMoney(amount, total.Currency)
+	}
+	values[0] = NewMoney(amount+remainder, total.Currency)
+	return values
+}
+
+// AddInstallmentPurchase validates and stores a purchase and generates one expense transaction
+// per installment, monthly from its first date
+func (tl *TransactionList) AddInstallmentPurchase(purchase InstallmentPurchase) (InstallmentPurchase, error) {
+	purchase.Description = strings.TrimSpace(purchase.Description)
+	purchase.Category = NormalizeCategory(purchase.Category)
+	if purchase.Description == "" {
+		return InstallmentPurchase{}, fmt.Errorf("purchase description is required")
+	}
+	if purchase.Total.IsNegative() || purchase.Total.IsZero() {
+		return InstallmentPurchase{}, fmt.Errorf("purchase total must be positive")
+	}
+	if purchase.Installments < 2 {
+		return InstallmentPurchase{}, fmt.Errorf("purchase must have at least 2 installments")
+	}
+	if purchase.Total.Amount < int64(purchase.Installments) {
+		return InstallmentPurchase{}, fmt.Errorf("purchase total is too small for %d installments", purchase.Installments)
+	}
+	if purchase.FirstDate.IsZero() {
+		return InstallmentPurchase{}, fmt.Errorf("purchase first installment date is required")
+	}
+	if purchase.AccountID == 0 {
+		purchase.AccountID = tl.DefaultAccountID()
+	}
+	if _, err := tl.GetAccountByID(purchase.AccountID); err != nil {
+		return InstallmentPurchase{}, err
+	}
+
+	purchase.ID = 1
+	for _, existing := range tl.Purchases {
+		if existing.ID >= purchase.ID {
+			purchase.ID = existing.ID + 1
+		}
+	}
+	purchase.State = PurchaseActive
+	tl.Purchases = append(tl.Purchases, purchase)
+
+	for i, value := range SplitInstallments(purchase.Total, purchase.Installments) {
+		description := fmt.Sprintf("%s (%d/%d)", purchase.Description, i+1, purchase.Installments)
+		tx := NewTransactionWithDate(TransactionTypeExpense, value, description, purchase.Category, addMonthsClamped(purchase.FirstDate, i))
+		tx.AccountID = purchase.AccountID
+		tx.PurchaseID = purchase.ID
+		tx.Installment = i + 1
+		if err := tl.AddTransaction(tx); err != nil {
+			return InstallmentPurchase{}, err
+		}
+	}
+	return purchase, nil
+}
+
+// GetInstallmentPurchases returns all installment purchases
+func (tl *TransactionList) GetInstallmentPurchases() []InstallmentPurchase {
+	return tl.Purchases
+}
+
+// GetPurchaseInstallments returns the transactions of a purchase ordered by date
+func (tl *TransactionList) GetPurchaseInstallments(id int) []Transaction {
+	var installments []Transaction
+	for _, tx := range tl.Transactions {
+		if tx.PurchaseID == id {
+			installments = append(installments, tx)
+		}
+	}
+	sort.SliceStable(installments, func(i, j int) bool {
+		return installments[i].Date.Before(installments[j].Date)
+	})
+	return installments
+}
+
+// GetPurchaseStatus returns the paid and pending installments of every purchase at now
+func (tl *TransactionList) GetPurchaseStatus(now time.Time) []PurchaseStatus {
+	var statuses []PurchaseStatus
+	for _, purchase := range tl.Purchases {
+		status := PurchaseStatus{
+			Purchase:      purchase,
+			PaidAmount:    NewMoney(0, purchase.Total.Currency),
+			PendingAmount: NewMoney(0, purchase.Total.Currency),
+		}
+		for _, tx := range tl.GetPurchaseInstallments(purchase.ID) {
+			if tx.IsCommitted(now) {
+				if status.Pending == 0 {
+					status.NextDate = tx.Date
+				}
+				status.Pending++
+				status.PendingAmount = status.PendingAmount.Add(tl.installmentAmount(purchase, tx))
+			} else {
+				status.Paid++
+				status.PaidAmount = status.PaidAmount.Add(tl.installmentAmount(purchase, tx))
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// CancelInstallmentPurchase removes the installments still to come after the day of now, for a
+// returned or cancelled purchase. Installments already due are kept, and so are reconciled ones,
+// which cannot be deleted. It returns how many were removed and how many were kept as reconciled.
+func (tl *TransactionList) CancelInstallmentPurchase(id int, now time.Time) (removed, locked int, err error) {
+	purchase := tl.purchase(id)
+	if purchase == nil {
+		return 0, 0, ErrPurchaseNotFound
+	}
+	deleted, locked := tl.removeCommittedInstallments(id, now)
+	if len(deleted) == 0 && locked > 0 {
+		return 0, locked, fmt.Errorf("all %d pending installments are reconciled: %w", locked, ErrTransactionLocked)
+	}
+	if len(deleted) == 0 {
+		return 0, 0, fmt.Errorf("purchase has no pending installments")
+	}
+	purchase.State = PurchaseCancelled
+	return len(deleted), locked, nil
+}
+
+// PrepayInstallmentPurchase replaces the installments still to come after date with a single
+// payment on date. A zero amount pays their full sum; a smaller amount records a prepayment discount.
+func (tl *TransactionList) PrepayInstallmentPurchase(id int, date time.Time, amount Money) (Transaction, error) {
+	purchase := tl.purchase(id)
+	if purchase == nil {
+		return Transaction{}, ErrPurchaseNotFound
+	}
+	if amount.IsNegative() {
+		return Transaction{}, fmt.Errorf("prepayment amount cannot be negative")
+	}
+
+	removed, _ := tl.removeCommittedInstallments(id, date)
+	if len(removed) == 0 {
+		return Transaction{}, fmt.Errorf("purchase has no pending installments")
+	}
+	if amount.IsZero() {
+		amount = NewMoney(0, purchase.Total.Currency)
+		for _, tx := range removed {
+			amount = amount.Add(tl.installmentAmount(*purchase, tx))
+		}
+	}
+
+	first, last := removed[0].Installment, removed[len(removed)-1].Installment
+	description := fmt.Sprintf("%s (antecipação %d-%d/%d)", purchase.Description, first, last, purchase.Installments)
+	tx := NewTransactionWithDate(TransactionTypeExpense, amount, description, purchase.Category, date)
+	tx.AccountID = purchase.AccountID
+	tx.PurchaseID = purchase.ID
+	tx.Installment = first
+	if err := tl.AddTransaction(tx); err != nil {
+		return Transaction{}, err
+	}
+	purchase.State = PurchasePrepaid
+	return tl.Transactions[len(tl.Transactions)-1], nil
+}
+
+// removeCommittedInstallments deletes the installments of a purchase dated after the day of now
+// and returns them ordered by date, along with how many were kept because they are reconciled
+func (tl *TransactionList) removeCommittedInstallments(id int, now time.Time) (removed []Transaction, locked int) {
+	for _, tx := range tl.GetPurchaseInstallments(id) {
+		if !tx.IsCommitted(now) {
+			continue
+		}
+		if err := tl.DeleteTransaction(tx.ID); errors.Is(err, ErrTransactionLocked) {
+			locked++
+		} else if err == nil {
+			removed = append(removed, tx)
+		}
+	}
+	return removed, locked
+}
+
+// installmentAmount returns the paid amount of an installment in its purchase's currency.
+// Ledgers saved before edits kept that currency may hold installments in another one; they
+// are converted at their date, and without a rate they count as zero, like BaseAmount.
+func (tl *TransactionList) installmentAmount(purchase InstallmentPurchase, tx Transaction) Money {
+	converted, err := tl.Convert(tx.Value.Abs(), purchase.Total.Currency, tx.Date)
+	if err != nil {
+		return NewMoney(0, purchase.Total.Currency)
+	}
+	return converted
+}
+
+func (tl *TransactionList) purchase(id int) *InstallmentPurchase {
+	for i := range tl.Purchases {
+		if tl.Purchases[i].ID == id {
+			return &tl.Purchases[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSplitInstallments(t *testing.T) {
+	tests := []struct {
+		total int64
+		n     int
+		want  []int64
+	}{
+		{30000, 3, []int64{10000, 10000, 10000}},
+		{100000, 3, []int64{33334, 33333, 33333}},
+		{1001, 10, []int64{101, 100, 100, 100, 100, 100, 100, 100, 100, 100}},
+		{500, 0, nil},
+	}
+	for _, tt := range tests {
+		got := SplitInstallments(NewMoney(tt.total, "BRL"), tt.n)
+		if len(got) != len(tt.want) {
+			t.Errorf("SplitInstallments(%d, %d) = %v, want %v", tt.total, tt.n, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i].Amount != tt.want[i] {
+				t.Errorf("SplitInstallments(%d, %d)[%d] = %v, want %d", tt.total, tt.n, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+// addPurchase stores a purchase of total in n installments from first and fails the test on error
+func addPurchase(t *testing.T, tl *TransactionList, total int64, n int, first int) InstallmentPurchase {
+	t.Helper()
+	purchase, err := tl.AddInstallmentPurchase(InstallmentPurchase{
+		Description:  "Geladeira",
+		Total:        NewMoney(total, "BRL"),
+		Installments: n,
+		Category:     "Casa>Eletro",
+		FirstDate:    date(2026, 1, first),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return purchase
+}
+
+func TestAddInstallmentPurchase(t *testing.T) {
+	tl := &TransactionList{}
+	tl.EnsureAccounts()
+	purchase := addPurchase(t, tl, 100000, 3, 31)
+
+	installments := tl.GetPurchaseInstallments(purchase.ID)
+	wantDates := []string{"2026-01-31", "2026-02-28", "2026-03-31"}
+	wantDescriptions := []string{"Geladeira (1/3)", "Geladeira (2/3)", "Geladeira (3/3)"}
+	if len(installments) != 3 {
+		t.Fatalf("%d installments, want 3", len(installments))
+	}
+	for i, tx := range installments {
+		if tx.Date.Format("2006-01-02") != wantDates[i] || tx.Description != wantDescriptions[i] || tx.Installment != i+1 {
+			t.Errorf("installment %d = %s %q #%d", i+1, tx.Date.Format("2006-01-02"), tx.Description, tx.Installment)
+		}
+		if tx.Type != TransactionTypeExpense || !tx.Value.IsNegative() || tx.Category != "Casa > Eletro" || tx.AccountID != tl.DefaultAccountID() {
+			t.Errorf("installment %d = %+v", i+1, tx)
+		}
+	}
+
+	status := tl.GetPurchaseStatus(date(2026, 2, 28))[0]
+	if status.Paid != 2 || status.PaidAmount.Amount != 66667 || status.Pending != 1 || status.PendingAmount.Amount != 33333 || !status.NextDate.Equal(date(2026, 3, 31)) {
+		t.Errorf("status on the second due date = %+v", status)
+	}
+
+	for _, invalid := range []InstallmentPurchase{
+		{Description: "", Total: NewMoney(1000, "BRL"), Installments: 2, FirstDate: date(2026, 1, 1)},
+		{Description: "TV", Total: NewMoney(-1000, "BRL"), Installments: 2, FirstDate: date(2026, 1, 1)},
+		{Description: "TV", Total: NewMoney(1000, "BRL"), Installments: 1, FirstDate: date(2026, 1, 1)},
+		{Description: "TV", Total: NewMoney(1, "BRL"), Installments: 2, FirstDate: date(2026, 1, 1)},
+		{Description: "TV", Total: NewMoney(1000, "BRL"), Installments: 2},
+		{Description: "TV", Total: NewMoney(1000, "BRL"), Installments: 2, FirstDate: date(2026, 1, 1), AccountID: 99},
+	} {
+		if _, err := tl.AddInstallmentPurchase(invalid); err == nil {
+			t.Errorf("invalid purchase %+v was added", invalid)
+		}
+	}
+}
+
+func TestCancelInstallmentPurchase(t *testing.T) {
+	tl := &TransactionList{}
+	tl.EnsureAccounts()
+	purchase := addPurchase(t, tl, 30000, 3, 20)
+
+	removed, locked, err := tl.CancelInstallmentPurchase(purchase.ID, date(2026, 1, 25))
+	if err != nil || removed != 2 || locked != 0 {
+		t.Fatalf("CancelInstallmentPurchase = %d, %d, %v; want 2, 0, nil", removed, locked, err)
+	}
+	if left := tl.GetPurchaseInstallments(purchase.ID); len(left) != 1 || left[0].Installment != 1 {
+		t.Errorf("installments left = %+v, want only the first", left)
+	}
+	if tl.GetInstallmentPurchases()[0].State != PurchaseCancelled {
+		t.Errorf("state = %q, want %q", tl.GetInstallmentPurchases()[0].State, PurchaseCancelled)
+	}
+	if _, _, err := tl.CancelInstallmentPurchase(purchase.ID, date(2026, 1, 25)); err == nil {
+		t.Error("a purchase without pending installments was cancelled again")
+	}
+	if _, _, err := tl.CancelInstallmentPurchase(99, date(2026, 1, 25)); !errors.Is(err, ErrPurchaseNotFound) {
+		t.Errorf("cancelling a missing purchase error = %v, want ErrPurchaseNotFound", err)
+	}
+}
+
+// reconcileInstallment locks an installment by reconciling it alone against a statement on its date
+func reconcileInstallment(t *testing.T, tl *TransactionList, tx Transaction) {
+	t.Helper()
+	if err := tl.SetCleared(tx.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	reconciliation, err := tl.GetReconciliation(tx.AccountID, tx.Date, NewMoney(0, "BRL"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tl.FinishReconciliation(tx.AccountID, tx.Date, reconciliation.ClearedBalance); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCancelInstallmentPurchaseReportsReconciledInstallments(t *testing.T) {
+	tl := &TransactionList{}
+	tl.EnsureAccounts()
+	purchase := addPurchase(t, tl, 30000, 3, 20)
+	reconcileInstallment(t, tl, tl.GetPurchaseInstallments(purchase.ID)[1])
+
+	removed, locked, err := tl.CancelInstallmentPurchase(purchase.ID, date(2026, 1, 25))
+	if err != nil || removed != 1 || locked != 1 {
+		t.Fatalf("CancelInstallmentPurchase = %d, %d, %v; want 1 removed and 1 locked", removed, locked, err)
+	}
+	if left := tl.GetPurchaseInstallments(purchase.ID); len(left) != 2 || !left[1].IsLocked() {
+		t.Errorf("installments left = %+v, want the first and the reconciled one", left)
+	}
+
+	// When every pending installment is reconciled nothing is cancelled
+	other := addPurchase(t, tl, 20000, 2, 10)
+	reconcileInstallment(t, tl, tl.GetPurchaseInstallments(other.ID)[1])
+	removed, locked, err = tl.CancelInstallmentPurchase(other.ID, date(2026, 1, 25))
+	if !errors.Is(err, ErrTransactionLocked) || removed != 0 || locked != 1 {
+		t.Errorf("CancelInstallmentPurchase = %d, %d, %v; want 0, 1, ErrTransactionLocked", removed, locked, err)
+	}
+	if state := tl.GetInstallmentPurchases()[1].State; state != PurchaseActive {
+		t.Errorf("state = %q, want the purchase still active", state)
+	}
+}
+
+func TestPrepayInstallmentPurchase(t *testing.T) {
+	tl := &TransactionList{}
+	tl.EnsureAccounts()
+	purchase := addPurchase(t, tl, 100000, 4, 20)
+
+	tx, err := tl.PrepayInstallmentPurchase(purchase.ID, date(2026, 2, 25), NewMoney(0, "BRL"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The third and fourth installments, 250.00 each, are replaced by a single payment
+	if tx.Value.Amount != -50000 || tx.Description != "Geladeira (antecipação 3-4/4)" || tx.Installment != 3 || tx.PurchaseID != purchase.ID {
+		t.Errorf("prepayment = %+v", tx)
+	}
+	if installments := tl.GetPurchaseInstallments(purchase.ID); len(installments) != 3 {
+		t.Errorf("%d installments after the prepayment, want 2 paid and the prepayment", len(installments))
+	}
+	if tl.GetInstallmentPurchases()[0].State != PurchasePrepaid {
+		t.Errorf("state = %q, want %q", tl.GetInstallmentPurchases()[0].State, PurchasePrepaid)
+	}
+
+	discounted := addPurchase(t, tl, 100000, 4, 20)
+	tx, err = tl.PrepayInstallmentPurchase(discounted.ID, date(2026, 1, 20), NewMoney(70000, "BRL"))
+	if err != nil || tx.Value.Amount != -70000 || tx.Description != "Geladeira (antecipação 2-4/4)" {
+		t.Errorf("discounted prepayment = %+v, %v", tx, err)
+	}
+	if _, err := tl.PrepayInstallmentPurchase(discounted.ID, date(2026, 1, 20), NewMoney(0, "BRL")); err == nil {
+		t.Error("a purchase without pending installments was prepaid")
+	}
+	if _, err := tl.PrepayInstallmentPurchase(discounted.ID, date(2026, 1, 20), NewMoney(-1, "BRL")); err == nil {
+		t.Error("a negative prepayment was accepted")
+	}
+}
+
+func TestInstallmentsKeepThePurchaseCurrency(t *testing.T) {
+	tl := newRateLedger(t)
+	purchase := addPurchase(t, tl, 100000, 4, 20)
+	installments := tl.GetPurchaseInstallments(purchase.ID)
+
+	last := installments[3]
+	last.Value = NewMoney(-5000, "USD")
+	if err := tl.UpdateTransaction(last); !errors.Is(err, ErrPurchaseCurrency) {
+		t.Errorf("moving an installment to USD: err = %v, want ErrPurchaseCurrency", err)
+	}
+
+	// A ledger saved before the check may already hold one; it is converted instead of panicking
+	tl.Transactions[tl.indexOf(last.ID)].Value = NewMoney(-5000, "USD")
+	status := tl.GetPurchaseStatus(date(2026, 2, 25))[0]
+	// 250.00 in March plus US$ 50.00 at 5.5 in April
+	if status.PaidAmount.Amount != 50000 || status.PendingAmount.Amount != 52500 || status.PendingAmount.Currency != "BRL" {
+		t.Errorf("status = paid %v, pending %v", status.PaidAmount, status.PendingAmount)
+	}
+	tx, err := tl.PrepayInstallmentPurchase(purchase.ID, date(2026, 2, 25), NewMoney(0, "BRL"))
+	if err != nil || tx.Value.Amount != -52500 || tx.Value.Currency != "BRL" {
+		t.Errorf("prepayment = %+v, %v", tx, err)
+	}
+}
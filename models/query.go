@@ -0,0 +1,182 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+// SortField is the transaction attribute a query is ordered by
+type SortField string
+
+const (
+	SortByDate        SortField = "date"
+	SortByValue       SortField = "value"
+	SortByDescription SortField = "description"
+	SortByCategory    SortField = "category"
+)
+
+// Query selects transactions. Every criterion that is set must match; zero values match everything.
+type Query struct {
+	// Period bounds the dates; the zero Period covers all time
+	Period Period
+	Types  []TransactionType
+	// Categories match a category or any of its subcategories, including splits
+	Categories []string
+	// Text matches the description or a split description, ignoring case
+	Text string
+	// Terms must all match like Text
+	Terms []string
+	// MinAmount and MaxAmount bound the absolute value in the base currency, inclusive
+	MinAmount *Money
+	MaxAmount *Money
+	AccountID int
+	Tags      []string
+	// MatchAllTags requires every tag instead of any of them
+	MatchAllTags bool
+	SortBy       SortField
+	Descending   bool
+	Limit        int
+	Offset       int
+}
+
+// Query returns the transactions matching q, sorted and paginated. Without SortBy the ledger order is kept.
+func (tl *TransactionList) Query(q Query) []Transaction {
+	var result []Transaction
+	for _, tx := range tl.Transactions {
+		if tl.matches(q, tx) {
+			result = append(result, tx)
+		}
+	}
+
+	if q.SortBy != "" {
+		sort.SliceStable(result, func(i, j int) bool {
+			if q.Descending {
+				return tl.less(q.SortBy, result[j], result[i])
+			}
+			return tl.less(q.SortBy, result[i], result[j])
+		})
+	}
+
+	if q.Offset > 0 {
+		if q.Offset >= len(result) {
+			return nil
+		}
+		result = result[q.Offset:]
+	}
+	if q.Limit > 0 && q.Limit < len(result) {
+		result = result[:q.Limit]
+	}
+	return result
+}
+
+// QuerySummary holds the totals of a set of transactions in the base currency. Transfers are
+// counted but are neither income nor expense.
+type QuerySummary struct {
+	Count   int
+	Income  Money
+	Expense Money
+}
+
+// Balance returns income minus expense
+func (qs QuerySummary) Balance() Money {
+	return qs.Income.Sub(qs.Expense)
+}
+
+// IsFiltered reports whether the query has any criterion that can exclude transactions
+func (q Query) IsFiltered() bool {
+	return !q.Period.IsAll() || len(q.Types) > 0 || len(q.Categories) > 0 ||
+		q.Text != "" || len(q.Terms) > 0 || q.MinAmount != nil || q.MaxAmount != nil ||
+		q.AccountID != 0 || len(q.Tags) > 0 || q.Limit > 0 || q.Offset > 0
+}
+
+// Summarize returns the count, income and expense of transactions in the base currency
+func (tl *TransactionList) Summarize(transactions []Transaction) QuerySummary {
+	summary := QuerySummary{
+		Income:  NewMoney(0, tl.GetBaseCurrency()),
+		Expense: NewMoney(0, tl.GetBaseCurrency()),
+	}
+	for _, tx := range transactions {
+		summary.Count++
+		switch tx.Type {
+		case TransactionTypeIncome:
+			summary.Income = summary.Income.Add(tl.BaseValue(tx))
+		case TransactionTypeExpense:
+			summary.Expense = summary.Expense.Add(tl.BaseValue(tx).Abs())
+		}
+	}
+	return summary
+}
+
+func (tl *TransactionList) matches(q Query, tx Transaction) bool {
+	if !q.Period.Contains(tx.Date) {
+		return false
+	}
+	if len(q.Types) > 0 && !containsType(q.Types, tx.Type) {
+		return false
+	}
+	if len(q.Categories) > 0 && !inAnyCategory(tx, q.Categories) {
+		return false
+	}
+	if q.Text != "" && !matchText(tx, q.Text) {
+		return false
+	}
+	for _, term := range q.Terms {
+		if !matchText(tx, term) {
+			return false
+		}
+	}
+	if q.MinAmount != nil || q.MaxAmount != nil {
+		amount := tl.BaseValue(tx).Abs()
+		if q.MinAmount != nil && amount.Amount < tl.BaseAmount(*q.MinAmount, tx.Date).Amount {
+			return false
+		}
+		if q.MaxAmount != nil && amount.Amount > tl.BaseAmount(*q.MaxAmount, tx.Date).Amount {
+			return false
+		}
+	}
+	if q.AccountID != 0 && tx.AccountID != q.AccountID {
+		return false
+	}
+	return matchTags(tx, q.Tags, q.MatchAllTags)
+}
+
+func (tl *TransactionList) less(field SortField, a, b Transaction) bool {
+	switch field {
+	case SortByValue:
+		return tl.BaseValue(a).Amount < tl.BaseValue(b).Amount
+	case SortByDescription:
+		return strings.ToLower(a.Description) < strings.ToLower(b.Description)
+	case SortByCategory:
+		return a.CategoryLabel() < b.CategoryLabel()
+	default:
+		return a.Date.Before(b.Date)
+	}
+}
+
+func containsType(types []TransactionType, transactionType TransactionType) bool {
+	for _, t := range types {
+		if t == transactionType {
+			return true
+		}
+	}
+	return false
+}
+
+func inAnyCategory(tx Transaction, categories []string) bool {
+	for _, category := range categories {
+		if tx.InCategory(category) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchText(tx Transaction, text string) bool {
+	text = strings.ToLower(strings.TrimSpace(text))
+	for _, part := range tx.Parts() {
+		if strings.Contains(strings.ToLower(part.Description), text) {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(tx.Description), text)
+}
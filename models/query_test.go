@@ -0,0 +1,118 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// queryLedger returns a ledger in BRL with a USD rate of 5.00, a wallet account and five transactions
+func queryLedger(t *testing.T) (*TransactionList, Account) {
+	t.Helper()
+	tl := newRateLedger(t)
+	wallet, err := tl.AddAccount("Carteira", AccountTypeCash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rent := NewTransactionWithDate(TransactionTypeExpense, NewMoney(150000, "BRL"), "Aluguel março", "Casa", date(2026, 3, 5))
+	market := NewTransactionWithDate(TransactionTypeExpense, NewMoney(20000, "BRL"), "Mercado", "", date(2026, 3, 12))
+	market.Splits = []Split{
+		{Value: NewMoney(15000, "BRL"), Category: "Alimentação", Description: "Feira do bairro"},
+		{Value: NewMoney(5000, "BRL"), Category: "Casa > Limpeza"},
+	}
+	salary := NewTransactionWithDate(TransactionTypeIncome, NewMoney(500000, "BRL"), "Salário", "Salário", date(2026, 3, 1))
+	hotel := NewTransactionWithDate(TransactionTypeExpense, NewMoney(10000, "USD"), "Hotel", "Viagem", date(2026, 1, 20))
+	coffee := NewTransactionWithDate(TransactionTypeExpense, NewMoney(800, "BRL"), "Café", "Alimentação", date(2026, 4, 1))
+	coffee.AccountID = wallet.ID
+	for _, tx := range []Transaction{rent, market, salary, hotel, coffee} {
+		if err := tl.AddTransaction(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tl, wallet
+}
+
+// descriptions lists the descriptions of transactions, in order
+func descriptions(transactions []Transaction) []string {
+	var result []string
+	for _, tx := range transactions {
+		result = append(result, tx.Description)
+	}
+	return result
+}
+
+func TestQuery(t *testing.T) {
+	tl, wallet := queryLedger(t)
+	money := func(amount int64, currency string) *Money {
+		m := NewMoney(amount, currency)
+		return &m
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"everything in ledger order", Query{}, []string{"Aluguel março", "Mercado", "Salário", "Hotel", "Café"}},
+		{"period excludes its end", Query{Period: CustomPeriod(date(2026, 3, 1), date(2026, 4, 1))}, []string{"Aluguel março", "Mercado", "Salário"}},
+		{"type", Query{Types: []TransactionType{TransactionTypeIncome}}, []string{"Salário"}},
+		{"category includes subcategories and splits", Query{Categories: []string{"Casa"}}, []string{"Aluguel março", "Mercado"}},
+		{"any of the categories", Query{Categories: []string{"Viagem", "Salário"}}, []string{"Salário", "Hotel"}},
+		{"text in a split description", Query{Text: "FEIRA"}, []string{"Mercado"}},
+		{"every term", Query{Terms: []string{"aluguel", "março"}}, []string{"Aluguel março"}},
+		{"terms that do not all match", Query{Terms: []string{"aluguel", "mercado"}}, nil},
+		{"amounts in the base currency", Query{MinAmount: money(50000, "BRL"), MaxAmount: money(150000, "BRL")}, []string{"Aluguel março", "Hotel"}},
+		{"amount bound in another currency", Query{MinAmount: money(30000, "USD")}, []string{"Salário"}},
+		{"account", Query{AccountID: wallet.ID}, []string{"Café"}},
+		{"sorted by date", Query{SortBy: SortByDate}, []string{"Hotel", "Salário", "Aluguel março", "Mercado", "Café"}},
+		{"sorted by value descending", Query{SortBy: SortByValue, Descending: true}, []string{"Salário", "Café", "Mercado", "Hotel", "Aluguel março"}},
+		{"sorted by description", Query{SortBy: SortByDescription}, []string{"Aluguel março", "Café", "Hotel", "Mercado", "Salário"}},
+		{"page", Query{SortBy: SortByDate, Offset: 1, Limit: 2}, []string{"Salário", "Aluguel março"}},
+		{"offset past the end", Query{Offset: 5}, nil},
+	}
+	for _, tt := range tests {
+		if got := descriptions(tl.Query(tt.query)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Query = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestQueryIsFiltered(t *testing.T) {
+	if (Query{}).IsFiltered() || (Query{SortBy: SortByValue, Descending: true}).IsFiltered() {
+		t.Error("a query that only sorts is not filtered")
+	}
+	for _, q := range []Query{
+		{Period: MonthPeriod(2026, time.March, time.UTC)},
+		{Text: "x"},
+		{Tags: []string{"viagem"}},
+		{Limit: 10},
+	} {
+		if !q.IsFiltered() {
+			t.Errorf("%+v is filtered", q)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tl, _ := queryLedger(t)
+	outgoing, _, err := tl.AddTransfer(tl.DefaultAccountID(), tl.Accounts[1].ID, NewMoney(10000, "BRL"), "", date(2026, 3, 20))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary := tl.Summarize(tl.Query(Query{}))
+	// The hotel is 100.00 USD at 5.00; transfer legs are counted but are neither income nor expense
+	if summary.Count != 7 || summary.Income.Amount != 500000 || summary.Expense.Amount != 150000+20000+50000+800 {
+		t.Errorf("summary = %+v", summary)
+	}
+	if summary.Balance().Amount != 500000-220800 {
+		t.Errorf("balance = %v", summary.Balance())
+	}
+	if empty := tl.Summarize(nil); empty.Count != 0 || !empty.Income.IsZero() || empty.Income.Currency != "BRL" {
+		t.Errorf("summary of nothing = %+v", empty)
+	}
+	if got := tl.Query(Query{Types: []TransactionType{TransactionTypeTransfer}}); len(got) != 2 || got[0].ID != outgoing.ID {
+		t.Errorf("transfer legs = %+v", got)
+	}
+}
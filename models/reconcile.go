@@ -0,0 +1,135 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTransactionLocked is returned when changing the amount, date or account of a reconciled transaction
+var ErrTransactionLocked = errors.New("transaction is reconciled and locked")
+
+// ReconcileStatus tracks a transaction against the bank statement
+type ReconcileStatus string
+
+const (
+	// StatusUncleared is a transaction not yet seen on a statement
+	StatusUncleared ReconcileStatus = ""
+	// StatusCleared is a transaction ticked off during a reconciliation in progress
+	StatusCleared ReconcileStatus = "Compensada"
+	// StatusReconciled is a transaction confirmed by a finished reconciliation; it is locked
+	StatusReconciled ReconcileStatus = "Conciliada"
+)
+
+// Mark returns the short marker shown next to a transaction: "C" when cleared, "R" when reconciled
+func (s ReconcileStatus) Mark() string {
+	switch s {
+	case StatusCleared:
+		return "C"
+	case StatusReconciled:
+		return "R"
+	default:
+		return ""
+	}
+}
+
+// IsLocked reports whether the transaction is reconciled, so its amount, date, type and account
+// cannot change, its attachments cannot be removed and it cannot be deleted
+func (t Transaction) IsLocked() bool {
+	return t.Status == StatusReconciled
+}
+
+// Reconciliation is the state of an account checked against a bank statement
+type Reconciliation struct {
+	AccountID     int
+	StatementDate time.Time
+	EndingBalance Money
+	// Candidates are the transactions up to the statement date that are not reconciled yet
+	Candidates []Transaction
+	// ClearedBalance sums the reconciled and cleared transactions up to the statement date
+	ClearedBalance Money
+}
+
+// Difference returns the statement balance minus the cleared balance; it is zero when they agree
+func (r Reconciliation) Difference() Money {
+	return r.EndingBalance.Sub(r.ClearedBalance)
+}
+
+// SetCleared ticks a transaction off as seen on the statement, or unticks it
+func (tl *TransactionList) SetCleared(id int, cleared bool) error {
+	index := tl.indexOf(id)
+	if index < 0 {
+		return ErrTransactionNotFound
+	}
+	if tl.Transactions[index].IsLocked() {
+		return ErrTransactionLocked
+	}
+	tl.Transactions[index].Status = StatusUncleared
+	if cleared {
+		tl.Transactions[index].Status = StatusCleared
+	}
+	return nil
+}
+
+// GetReconciliation returns the reconciliation of an account against a statement that ends on
+// statementDate with endingBalance, in the base currency like account balances
+func (tl *TransactionList) GetReconciliation(accountID int, statementDate time.Time, endingBalance Money) (Reconciliation, error) {
+	if _, err := tl.GetAccountByID(accountID); err != nil {
+		return Reconciliation{}, err
+	}
+
+	reconciliation := Reconciliation{
+		AccountID:      accountID,
+		StatementDate:  statementDate,
+		EndingBalance:  endingBalance,
+		ClearedBalance: NewMoney(0, tl.GetBaseCurrency()),
+	}
+	through := CustomPeriod(time.Time{}, NewPeriod(PeriodDay, statementDate, 0).End)
+	for _, tx := range tl.Query(Query{AccountID: accountID, Period: through, SortBy: SortByDate}) {
+		if tx.Status != StatusReconciled {
+			reconciliation.Candidates = append(reconciliation.Candidates, tx)
+		}
+		if tx.Status != StatusUncleared {
+			reconciliation.ClearedBalance = reconciliation.ClearedBalance.Add(tl.BaseValue(tx))
+		}
+	}
+	return reconciliation, nil
+}
+
+// FinishReconciliation locks the cleared transactions of an account up to the statement date
+// once the cleared balance matches the statement. It returns how many transactions were reconciled.
+func (tl *TransactionList) FinishReconciliation(accountID int, statementDate time.Time, endingBalance Money) (int, error) {
+	reconciliation, err := tl.GetReconciliation(accountID, statementDate, endingBalance)
+	if err != nil {
+		return 0, err
+	}
+	if difference := reconciliation.Difference(); !difference.IsZero() {
+		return 0, fmt.Errorf("cleared balance differs from the statement by %s", difference)
+	}
+
+	reconciled := 0
+	for _, tx := range reconciliation.Candidates {
+		if tx.Status == StatusCleared {
+			tl.Transactions[tl.indexOf(tx.ID)].Status = StatusReconciled
+			reconciled++
+		}
+	}
+	return reconciled, nil
+}
+
+// checkLocked rejects changes to the amount, date, type or account of a reconciled transaction,
+// or to a transfer whose other leg is reconciled
+func (tl *TransactionList) checkLocked(existing, updated Transaction) error {
+	changed := updated.Value != existing.Value || !updated.Date.Equal(existing.Date) ||
+		updated.Type != existing.Type || updated.AccountID != existing.AccountID
+	if !changed {
+		return nil
+	}
+	if existing.IsLocked() {
+		return ErrTransactionLocked
+	}
+	if linked := tl.indexOf(existing.LinkedID); existing.LinkedID != 0 && linked >= 0 && tl.Transactions[linked].IsLocked() {
+		return ErrTransactionLocked
+	}
+	return nil
+}
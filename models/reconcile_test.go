@@ -0,0 +1,133 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+// bankTransaction adds a transaction to the default account and returns it as stored
+func bankTransaction(t *testing.T, tl *TransactionList, typ TransactionType, amount int64, description string, day int) Transaction {
+	t.Helper()
+	if err := tl.AddTransaction(NewTransactionWithDate(typ, NewMoney(amount, "BRL"), description, "", date(2026, 1, day))); err != nil {
+		t.Fatal(err)
+	}
+	return tl.Transactions[len(tl.Transactions)-1]
+}
+
+func TestReconciliation(t *testing.T) {
+	tl, _, _ := newAccountLedger(t)
+	account := tl.DefaultAccountID()
+	salary := bankTransaction(t, tl, TransactionTypeIncome, 500000, "Salário", 5)
+	rent := bankTransaction(t, tl, TransactionTypeExpense, 150000, "Aluguel", 10)
+	pending := bankTransaction(t, tl, TransactionTypeExpense, 4000, "Cheque", 20)
+	later := bankTransaction(t, tl, TransactionTypeExpense, 9000, "Mercado", 31)
+
+	for _, id := range []int{salary.ID, rent.ID} {
+		if err := tl.SetCleared(id, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reconciliation, err := tl.GetReconciliation(account, date(2026, 1, 20), NewMoney(346000, "BRL"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The statement day is included; the transaction after it is not a candidate
+	if len(reconciliation.Candidates) != 3 || reconciliation.ClearedBalance.Amount != 350000 || reconciliation.Difference().Amount != -4000 {
+		t.Fatalf("reconciliation = %d candidates, cleared %v, difference %v", len(reconciliation.Candidates), reconciliation.ClearedBalance, reconciliation.Difference())
+	}
+	if _, err := tl.FinishReconciliation(account, date(2026, 1, 20), NewMoney(346000, "BRL")); err == nil {
+		t.Fatal("a reconciliation with a difference was finished")
+	}
+
+	if err := tl.SetCleared(pending.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	reconciled, err := tl.FinishReconciliation(account, date(2026, 1, 20), NewMoney(346000, "BRL"))
+	if err != nil || reconciled != 3 {
+		t.Fatalf("FinishReconciliation = %d, %v; want 3 reconciled", reconciled, err)
+	}
+	for _, id := range []int{salary.ID, rent.ID, pending.ID} {
+		if tx, _ := tl.GetTransactionByID(id); tx.Status != StatusReconciled || tx.Status.Mark() != "R" {
+			t.Errorf("transaction %q has status %q after the reconciliation", tx.Description, tx.Status)
+		}
+	}
+	if tx, _ := tl.GetTransactionByID(later.ID); tx.Status != StatusUncleared {
+		t.Errorf("the transaction after the statement date was marked %q", tx.Status)
+	}
+
+	// The next statement starts from the reconciled balance
+	next, err := tl.GetReconciliation(account, date(2026, 2, 20), NewMoney(337000, "BRL"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(next.Candidates) != 1 || next.Candidates[0].ID != later.ID || next.ClearedBalance.Amount != 346000 {
+		t.Errorf("next reconciliation = %d candidates, cleared %v", len(next.Candidates), next.ClearedBalance)
+	}
+	if _, err := tl.GetReconciliation(99, date(2026, 1, 20), NewMoney(0, "BRL")); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("reconciling a missing account error = %v, want ErrAccountNotFound", err)
+	}
+}
+
+func TestReconciledTransactionsAreLocked(t *testing.T) {
+	tl, checking, savings := newAccountLedger(t)
+	rent := bankTransaction(t, tl, TransactionTypeExpense, 150000, "Aluguel", 10)
+	outgoing, incoming, err := tl.AddTransfer(checking.ID, savings.ID, NewMoney(20000, "BRL"), "Reserva", date(2026, 1, 12))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []int{rent.ID, outgoing.ID} {
+		if err := tl.SetCleared(id, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := tl.FinishReconciliation(checking.ID, date(2026, 1, 31), NewMoney(-170000, "BRL")); err != nil {
+		t.Fatal(err)
+	}
+	rent, _ = tl.GetTransactionByID(rent.ID)
+
+	changes := []struct {
+		name   string
+		change func(*Transaction)
+	}{
+		{"amount", func(tx *Transaction) { tx.Value = NewMoney(-1, "BRL") }},
+		{"date", func(tx *Transaction) { tx.Date = date(2026, 1, 11) }},
+		{"type", func(tx *Transaction) { tx.Type = TransactionTypeIncome }},
+		{"account", func(tx *Transaction) { tx.AccountID = savings.ID }},
+	}
+	for _, tt := range changes {
+		changed := rent
+		tt.change(&changed)
+		if err := tl.UpdateTransaction(changed); !errors.Is(err, ErrTransactionLocked) {
+			t.Errorf("changing the %s of a reconciled transaction error = %v, want ErrTransactionLocked", tt.name, err)
+		}
+	}
+
+	// The description and category can still change, and the status is kept
+	described := rent
+	described.Description = "Aluguel janeiro"
+	described.Category = "Casa"
+	described.Status = StatusUncleared
+	if err := tl.UpdateTransaction(described); err != nil {
+		t.Fatal(err)
+	}
+	if tx, _ := tl.GetTransactionByID(rent.ID); tx.Description != "Aluguel janeiro" || tx.Status != StatusReconciled {
+		t.Errorf("after editing the description = %+v", tx)
+	}
+
+	if err := tl.DeleteTransaction(rent.ID); !errors.Is(err, ErrTransactionLocked) {
+		t.Errorf("deleting a reconciled transaction error = %v, want ErrTransactionLocked", err)
+	}
+	if err := tl.SetCleared(rent.ID, false); !errors.Is(err, ErrTransactionLocked) {
+		t.Errorf("unclearing a reconciled transaction error = %v, want ErrTransactionLocked", err)
+	}
+
+	// The other leg of a reconciled transfer is locked too
+	moved := incoming
+	moved.Value = NewMoney(1, "BRL")
+	if err := tl.UpdateTransaction(moved); !errors.Is(err, ErrTransactionLocked) {
+		t.Errorf("changing the other leg of a reconciled transfer error = %v, want ErrTransactionLocked", err)
+	}
+	if err := tl.DeleteTransaction(incoming.ID); !errors.Is(err, ErrTransactionLocked) {
+		t.Errorf("deleting the other leg of a reconciled transfer error = %v, want ErrTransactionLocked", err)
+	}
+}
@@ -0,0 +1,309 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrRecurrenceRuleNotFound is returned when no recurrence rule has the requested ID
+var ErrRecurrenceRuleNotFound = errors.New("recurrence rule not found")
+
+// Frequency is the unit of time between occurrences of a recurrence rule
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "Diária"
+	FrequencyWeekly  Frequency = "Semanal"
+	FrequencyMonthly Frequency = "Mensal"
+	FrequencyYearly  Frequency = "Anual"
+)
+
+// Frequencies returns the selectable frequencies in display order
+func Frequencies() []Frequency {
+	return []Frequency{FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly}
+}
+
+// FrequencyNames returns the frequencies as strings, for select widgets
+func FrequencyNames() []string {
+	var names []string
+	for _, f := range Frequencies() {
+		names = append(names, string(f))
+	}
+	return names
+}
+
+// RecurrenceException skips or changes a single occurrence of a rule
+type RecurrenceException struct {
+	Date        time.Time `json:"date"`
+	Skip        bool      `json:"skip"`
+	Value       *Money    `json:"value,omitempty"`
+	Description string    `json:"description,omitempty"`
+}
+
+// RecurrenceRule describes a transaction that repeats, such as rent, salary or a subscription
+type RecurrenceRule struct {
+	ID          int                   `json:"id"`
+	Type        TransactionType       `json:"type"`
+	Value       Money                 `json:"value"`
+	Description string                `json:"description"`
+	Category    string                `json:"category"`
+	AccountID   int                   `json:"account_id"`
+	Frequency   Frequency             `json:"frequency"`
+	Interval    int                   `json:"interval"`
+	DayOfMonth  int                   `json:"day_of_month,omitempty"`
+	StartDate   time.Time             `json:"start_date"`
+	EndDate     *time.Time            `json:"end_date,omitempty"`
+	Occurrences int                   `json:"occurrences,omitempty"`
+	Exceptions  []RecurrenceException `json:"exceptions,omitempty"`
+
+	// GeneratedThrough is the date of the last occurrence already turned into a transaction
+	GeneratedThrough time.Time `json:"generated_through"`
+}
+
+// Validate checks that the rule can produce occurrences
+func (r RecurrenceRule) Validate() error {
+	switch r.Frequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+	default:
+		return fmt.Errorf("invalid frequency %q", r.Frequency)
+	}
+	if r.Interval < 1 {
+		return fmt.Errorf("interval must be at least 1")
+	}
+	if r.DayOfMonth < 0 || r.DayOfMonth > 31 {
+		return fmt.Errorf("day of month must be between 1 and 31")
+	}
+	if r.StartDate.IsZero() {
+		return fmt.Errorf("start date is required")
+	}
+	if r.EndDate != nil && r.EndDate.Before(r.StartDate) {
+		return fmt.Errorf("end date must not be before start date")
+	}
+	if r.Occurrences < 0 {
+		return fmt.Errorf("number of occurrences must not be negative")
+	}
+	if r.Value.IsZero() {
+		return fmt.Errorf("value must not be zero")
+	}
+	return nil
+}
+
+// OccurrencesUntil returns the scheduled occurrence dates up to and including until,
+// honoring the end date and the maximum number of occurrences
+func (r RecurrenceRule) OccurrencesUntil(until time.Time) []time.Time {
+	var dates []time.Time
+	for n := 0; r.Occurrences == 0 || n < r.Occurrences; n++ {
+		date := r.occurrence(n)
+		if date.After(until) || (r.EndDate != nil && date.After(*r.EndDate)) {
+			break
+		}
+		dates = append(dates, date)
+	}
+	return dates
+}
+
+// occurrence returns the date of the n-th occurrence, counting from zero
+func (r RecurrenceRule) occurrence(n int) time.Time {
+	start := r.StartDate
+	step := n * r.Interval
+	switch r.Frequency {
+	case FrequencyDaily:
+		return start.AddDate(0, 0, step)
+	case FrequencyWeekly:
+		return start.AddDate(0, 0, 7*step)
+	case FrequencyYearly:
+		return r.onDayOfMonth(start.Year()+r.firstPeriod()+step, start.Month())
+	default:
+		month := int(start.Month()) - 1 + r.firstPeriod() + step
+		return r.onDayOfMonth(start.Year()+month/12, time.Month(month%12+1))
+	}
+}
+
+// firstPeriod is 1 when the rule's day in the start month (or year) falls before the start date,
+// so the first occurrence moves to the next month (or year), and 0 otherwise
+func (r RecurrenceRule) firstPeriod() int {
+	if r.onDayOfMonth(r.StartDate.Year(), r.StartDate.Month()).Day() < r.StartDate.Day() {
+		return 1
+	}
+	return 0
+}
+
+// onDayOfMonth returns the rule's day in the given month, clamped to the month's last day
+func (r RecurrenceRule) onDayOfMonth(year int, month time.Month) time.Time {
+	day := r.DayOfMonth
+	if day == 0 {
+		day = r.StartDate.Day()
+	}
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, r.StartDate.Location()).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	start := r.StartDate
+	return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+}
+
+// exception returns the exception registered for an occurrence date, if any
+func (r RecurrenceRule) exception(date time.Time) (RecurrenceException, bool) {
+	for _, e := range r.Exceptions {
+		if sameDay(e.Date, date) {
+			return e, true
+		}
+	}
+	return RecurrenceException{}, false
+}
+
+// AddRecurrenceRule validates and stores a new recurrence rule
+func (tl *TransactionList) AddRecurrenceRule(rule RecurrenceRule) (RecurrenceRule, error) {
+	if !rule.Type.IsValid() {
+		rule.Type = TransactionTypeForValue(rule.Value)
+	}
+	rule.Value = rule.Type.SignedValue(rule.Value)
+	rule.Description = strings.TrimSpace(rule.Description)
+	if rule.Interval == 0 {
+		rule.Interval = 1
+	}
+	if err := rule.Validate(); err != nil {
+		return RecurrenceRule{}, err
+	}
+	if rule.AccountID == 0 {
+		rule.AccountID = tl.DefaultAccountID()
+	}
+
+	rule.ID = 1
+	for _, existing := range tl.RecurrenceRules {
+		if existing.ID >= rule.ID {
+			rule.ID = existing.ID + 1
+		}
+	}
+	tl.RecurrenceRules = append(tl.RecurrenceRules, rule)
+	return rule, nil
+}
+
+// GetRecurrenceRules returns all recurrence rules
+func (tl *TransactionList) GetRecurrenceRules() []RecurrenceRule {
+	return tl.RecurrenceRules
+}
+
+// DeleteRecurrenceRule removes a rule; transactions it already generated are kept
+func (tl *TransactionList) DeleteRecurrenceRule(id int) error {
+	for i, rule := range tl.RecurrenceRules {
+		if rule.ID == id {
+			tl.RecurrenceRules = append(tl.RecurrenceRules[:i], tl.RecurrenceRules[i+1:]...)
+			return nil
+		}
+	}
+	return ErrRecurrenceRuleNotFound
+}
+
+// SetRecurrenceException skips or changes a single occurrence of a rule,
+// replacing any exception already registered for that date
+func (tl *TransactionList) SetRecurrenceException(ruleID int, exception RecurrenceException) error {
+	rule := tl.recurrenceRule(ruleID)
+	if rule == nil {
+		return ErrRecurrenceRuleNotFound
+	}
+	if exception.Value != nil {
+		value := rule.Type.SignedValue(*exception.Value)
+		exception.Value = &value
+	}
+
+	for i, existing := range rule.Exceptions {
+		if sameDay(existing.Date, exception.Date) {
+			rule.Exceptions[i] = exception
+			return nil
+		}
+	}
+	rule.Exceptions = append(rule.Exceptions, exception)
+	return nil
+}
+
+// MaterializeRecurring creates the transactions of every occurrence due up to now that was
+// not generated before. Each rule remembers the last generated occurrence, so running it
+// again never creates a transaction twice. It returns the created transactions.
+func (tl *TransactionList) MaterializeRecurring(now time.Time) []Transaction {
+	var created []Transaction
+	for i := range tl.RecurrenceRules {
+		rule := &tl.RecurrenceRules[i]
+		for _, date := range rule.OccurrencesUntil(now) {
+			if !date.After(rule.GeneratedThrough) {
+				continue
+			}
+			rule.GeneratedThrough = date
+
+			tx, ok := rule.transaction(date)
+			if !ok {
+				continue
+			}
+			if err := tl.AddTransaction(tx); err != nil {
+				continue
+			}
+			created = append(created, tl.Transactions[len(tl.Transactions)-1])
+		}
+	}
+	return created
+}
+
+// UpcomingRecurring returns the transactions that the rules will generate after from and up to
+// until, with exceptions applied. They are not stored; forecasts use them as scheduled items.
+func (tl *TransactionList) UpcomingRecurring(from, until time.Time) []Transaction {
+	var upcoming []Transaction
+	for _, rule := range tl.RecurrenceRules {
+		for _, date := range rule.OccurrencesUntil(until) {
+			if !date.After(rule.GeneratedThrough) || !date.After(from) {
+				continue
+			}
+			if tx, ok := rule.transaction(date); ok {
+				upcoming = append(upcoming, tx)
+			}
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].Date.Before(upcoming[j].Date)
+	})
+	return upcoming
+}
+
+// transaction builds the transaction of an occurrence, or reports false when it is skipped.
+// It has no ID yet; AddTransaction assigns one when the occurrence is materialized.
+func (r RecurrenceRule) transaction(date time.Time) (Transaction, bool) {
+	value, description := r.Value, r.Description
+	if exception, ok := r.exception(date); ok {
+		if exception.Skip {
+			return Transaction{}, false
+		}
+		if exception.Value != nil {
+			value = *exception.Value
+		}
+		if exception.Description != "" {
+			description = exception.Description
+		}
+	}
+
+	return Transaction{
+		Type:         r.Type,
+		Value:        r.Type.SignedValue(value),
+		Description:  description,
+		Category:     r.Category,
+		Date:         date,
+		AccountID:    r.AccountID,
+		RecurrenceID: r.ID,
+	}, true
+}
+
+func (tl *TransactionList) recurrenceRule(id int) *RecurrenceRule {
+	for i := range tl.RecurrenceRules {
+		if tl.RecurrenceRules[i].ID == id {
+			return &tl.RecurrenceRules[i]
+		}
+	}
+	return nil
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,174 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestOccurrencesUntil(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  RecurrenceRule
+		until time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "monthly on the start day",
+			rule:  RecurrenceRule{Frequency: FrequencyMonthly, Interval: 1, StartDate: date(2026, 1, 20)},
+			until: date(2026, 3, 31),
+			want:  []time.Time{date(2026, 1, 20), date(2026, 2, 20), date(2026, 3, 20)},
+		},
+		{
+			name:  "monthly day before the start day moves to the next month",
+			rule:  RecurrenceRule{Frequency: FrequencyMonthly, Interval: 1, DayOfMonth: 5, StartDate: date(2026, 1, 20)},
+			until: date(2026, 3, 31),
+			want:  []time.Time{date(2026, 2, 5), date(2026, 3, 5)},
+		},
+		{
+			name:  "monthly day after the start day stays in the start month",
+			rule:  RecurrenceRule{Frequency: FrequencyMonthly, Interval: 1, DayOfMonth: 25, StartDate: date(2026, 1, 20)},
+			until: date(2026, 2, 28),
+			want:  []time.Time{date(2026, 1, 25), date(2026, 2, 25)},
+		},
+		{
+			name:  "quarterly day before the start day counts the interval from the next month",
+			rule:  RecurrenceRule{Frequency: FrequencyMonthly, Interval: 3, DayOfMonth: 5, StartDate: date(2026, 11, 20)},
+			until: date(2027, 6, 30),
+			want:  []time.Time{date(2026, 12, 5), date(2027, 3, 5), date(2027, 6, 5)},
+		},
+		{
+			name:  "day 31 is clamped to the end of short months",
+			rule:  RecurrenceRule{Frequency: FrequencyMonthly, Interval: 1, DayOfMonth: 31, StartDate: date(2026, 1, 31)},
+			until: date(2026, 4, 30),
+			want:  []time.Time{date(2026, 1, 31), date(2026, 2, 28), date(2026, 3, 31), date(2026, 4, 30)},
+		},
+		{
+			name:  "clamped day before the start day moves to the next month",
+			rule:  RecurrenceRule{Frequency: FrequencyMonthly, Interval: 1, DayOfMonth: 30, StartDate: date(2027, 2, 28)},
+			until: date(2027, 4, 30),
+			want:  []time.Time{date(2027, 2, 28), date(2027, 3, 30), date(2027, 4, 30)},
+		},
+		{
+			name:  "yearly day before the start day moves to the next year",
+			rule:  RecurrenceRule{Frequency: FrequencyYearly, Interval: 1, DayOfMonth: 5, StartDate: date(2026, 3, 20)},
+			until: date(2028, 12, 31),
+			want:  []time.Time{date(2027, 3, 5), date(2028, 3, 5)},
+		},
+		{
+			name:  "weekly with an occurrence limit",
+			rule:  RecurrenceRule{Frequency: FrequencyWeekly, Interval: 2, Occurrences: 2, StartDate: date(2026, 1, 1)},
+			until: date(2026, 12, 31),
+			want:  []time.Time{date(2026, 1, 1), date(2026, 1, 15)},
+		},
+		{
+			name:  "daily until the end date",
+			rule:  RecurrenceRule{Frequency: FrequencyDaily, Interval: 1, StartDate: date(2026, 1, 1), EndDate: ptrTime(date(2026, 1, 3))},
+			until: date(2026, 12, 31),
+			want:  []time.Time{date(2026, 1, 1), date(2026, 1, 2), date(2026, 1, 3)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rule.OccurrencesUntil(tt.until)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Fatalf("occurrence %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOccurrencesNeverBeforeStart(t *testing.T) {
+	// The start date keeps its time of day, as rules created from time.Now() do
+	start := time.Date(2026, 1, 20, 14, 30, 15, 123, time.UTC)
+	for day := 0; day <= 31; day++ {
+		rule := RecurrenceRule{Frequency: FrequencyMonthly, Interval: 1, DayOfMonth: day, StartDate: start}
+		dates := rule.OccurrencesUntil(date(2026, 3, 1))
+		if len(dates) == 0 {
+			t.Fatalf("day %d: no occurrences", day)
+		}
+		if first := dates[0]; first.Before(start) && !sameDay(first, start) {
+			t.Errorf("day %d: first occurrence %v is before the start %v", day, first, start)
+		}
+		if day == 0 && !sameDay(dates[0], start) {
+			t.Errorf("without a day of month the first occurrence is %v, want the start day", dates[0])
+		}
+	}
+}
+
+func TestMaterializeRecurringDoesNotPrecedeStart(t *testing.T) {
+	tl := &TransactionList{}
+	tl.EnsureAccounts()
+	rule, err := tl.AddRecurrenceRule(RecurrenceRule{
+		Type:        TransactionTypeExpense,
+		Value:       NewMoney(10000, "BRL"),
+		Description: "Aluguel",
+		Frequency:   FrequencyMonthly,
+		DayOfMonth:  5,
+		StartDate:   date(2026, 1, 20),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created := tl.MaterializeRecurring(date(2026, 3, 10))
+	if len(created) != 2 {
+		t.Fatalf("created %d transactions, want 2", len(created))
+	}
+	for _, tx := range created {
+		if tx.Date.Before(rule.StartDate) {
+			t.Errorf("transaction dated %v before the rule starts", tx.Date)
+		}
+		if tx.Value.Amount != -10000 || tx.RecurrenceID != rule.ID {
+			t.Errorf("unexpected transaction %+v", tx)
+		}
+	}
+	if again := tl.MaterializeRecurring(date(2026, 3, 10)); len(again) != 0 {
+		t.Errorf("running again created %d transactions", len(again))
+	}
+}
+
+func TestUpcomingRecurringDoesNotUseIDs(t *testing.T) {
+	tl := &TransactionList{}
+	tl.EnsureAccounts()
+	if _, err := tl.AddRecurrenceRule(RecurrenceRule{
+		Type:        TransactionTypeExpense,
+		Value:       NewMoney(10000, "BRL"),
+		Description: "Aluguel",
+		Frequency:   FrequencyMonthly,
+		DayOfMonth:  5,
+		StartDate:   date(2026, 1, 1),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	before := generateID()
+	for i := 0; i < 3; i++ {
+		for _, tx := range tl.UpcomingRecurring(date(2026, 1, 1), date(2026, 12, 31)) {
+			if tx.ID != 0 {
+				t.Fatalf("projected occurrence has ID %d, want none", tx.ID)
+			}
+		}
+	}
+	if next := generateID(); next != before+1 {
+		t.Errorf("forecasting used up %d IDs", next-before-1)
+	}
+
+	// Materialized occurrences are stored with an ID
+	created := tl.MaterializeRecurring(date(2026, 2, 10))
+	if len(created) != 2 || created[0].ID == 0 || created[0].ID != tl.Transactions[0].ID {
+		t.Errorf("materialized transactions = %+v", created)
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,234 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrRuleNotFound is returned when no categorization rule has the requested ID
+var ErrRuleNotFound = errors.New("rule not found")
+
+// CategoryRule sets the category of transactions that match every condition it has. Rules are
+// tried in order and the first match wins.
+type CategoryRule struct {
+	ID int `json:"id"`
+	// Contains matches descriptions containing the text, ignoring case and accents
+	Contains string `json:"contains,omitempty"`
+	// Pattern is a regular expression matched against the description, ignoring case
+	Pattern string          `json:"pattern,omitempty"`
+	Type    TransactionType `json:"type,omitempty"`
+	// MinAmount and MaxAmount bound the absolute value in the base currency, inclusive
+	MinAmount *Money `json:"min_amount,omitempty"`
+	MaxAmount *Money `json:"max_amount,omitempty"`
+	AccountID int    `json:"account_id,omitempty"`
+	Category  string `json:"category"`
+}
+
+// RuleChange is a category a rule run would set, or has set, on an existing transaction
+type RuleChange struct {
+	Transaction Transaction
+	From        string
+	To          string
+	RuleID      int
+}
+
+// Validate checks that the rule has a category, at least one condition and a valid pattern
+func (r CategoryRule) Validate() error {
+	if NormalizeCategory(r.Category) == "" {
+		return fmt.Errorf("rule category is required")
+	}
+	if strings.TrimSpace(r.Contains) == "" && strings.TrimSpace(r.Pattern) == "" && r.Type == "" &&
+		r.MinAmount == nil && r.MaxAmount == nil && r.AccountID == 0 {
+		return fmt.Errorf("rule needs at least one condition")
+	}
+	if r.Type != "" && r.Type != TransactionTypeIncome && r.Type != TransactionTypeExpense {
+		return fmt.Errorf("rule type must be income or expense")
+	}
+	if r.MinAmount != nil && r.MaxAmount != nil && r.MinAmount.Amount > r.MaxAmount.Amount {
+		return fmt.Errorf("rule minimum amount is above the maximum")
+	}
+	if _, err := r.compile(); err != nil {
+		return fmt.Errorf("invalid rule pattern: %w", err)
+	}
+	return nil
+}
+
+// DescribeRule describes the conditions and the category of a rule
+func (tl *TransactionList) DescribeRule(r CategoryRule) string {
+	var conditions []string
+	if r.Contains != "" {
+		conditions = append(conditions, fmt.Sprintf("descrição contém %q", r.Contains))
+	}
+	if r.Pattern != "" {
+		conditions = append(conditions, fmt.Sprintf("descrição casa /%s/", r.Pattern))
+	}
+	if r.Type != "" {
+		conditions = append(conditions, fmt.Sprintf("tipo %s", r.Type))
+	}
+	if r.MinAmount != nil {
+		conditions = append(conditions, fmt.Sprintf("valor >= %s", *r.MinAmount))
+	}
+	if r.MaxAmount != nil {
+		conditions = append(conditions, fmt.Sprintf("valor <= %s", *r.MaxAmount))
+	}
+	if r.AccountID != 0 {
+		name := fmt.Sprintf("%d", r.AccountID)
+		if account, err := tl.GetAccountByID(r.AccountID); err == nil {
+			name = account.Name
+		}
+		conditions = append(conditions, fmt.Sprintf("conta %s", name))
+	}
+	return fmt.Sprintf("%s → %s", strings.Join(conditions, " e "), r.Category)
+}
+
+func (r CategoryRule) compile() (*regexp.Regexp, error) {
+	if r.Pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile("(?i)" + r.Pattern)
+}
+
+// AddRule validates a categorization rule and appends it after the existing ones
+func (tl *TransactionList) AddRule(rule CategoryRule) (CategoryRule, error) {
+	rule.Contains = strings.TrimSpace(rule.Contains)
+	rule.Pattern = strings.TrimSpace(rule.Pattern)
+	rule.Category = NormalizeCategory(rule.Category)
+	if err := rule.Validate(); err != nil {
+		return CategoryRule{}, err
+	}
+	if rule.AccountID != 0 {
+		if _, err := tl.GetAccountByID(rule.AccountID); err != nil {
+			return CategoryRule{}, err
+		}
+	}
+
+	rule.ID = 1
+	for _, existing := range tl.Rules {
+		if existing.ID >= rule.ID {
+			rule.ID = existing.ID + 1
+		}
+	}
+	tl.Rules = append(tl.Rules, rule)
+	return rule, nil
+}
+
+// DeleteRule removes a categorization rule; categories it already set are kept
+func (tl *TransactionList) DeleteRule(id int) error {
+	for i, rule := range tl.Rules {
+		if rule.ID == id {
+			tl.Rules = append(tl.Rules[:i], tl.Rules[i+1:]...)
+			return nil
+		}
+	}
+	return ErrRuleNotFound
+}
+
+// MoveRule moves a rule one position earlier (up) or later in the order rules are tried
+func (tl *TransactionList) MoveRule(id int, up bool) error {
+	for i, rule := range tl.Rules {
+		if rule.ID != id {
+			continue
+		}
+		j := i + 1
+		if up {
+			j = i - 1
+		}
+		if j >= 0 && j < len(tl.Rules) {
+			tl.Rules[i], tl.Rules[j] = tl.Rules[j], tl.Rules[i]
+		}
+		return nil
+	}
+	return ErrRuleNotFound
+}
+
+// GetRules returns the categorization rules in the order they are tried
+func (tl *TransactionList) GetRules() []CategoryRule {
+	return append([]CategoryRule(nil), tl.Rules...)
+}
+
+// MatchRule returns the first rule matching tx. Transfers and split transactions never match,
+// since their categories are not a single choice.
+func (tl *TransactionList) MatchRule(tx Transaction) (CategoryRule, bool) {
+	if tx.Type == TransactionTypeTransfer || tx.IsSplit() {
+		return CategoryRule{}, false
+	}
+	for _, rule := range tl.Rules {
+		if tl.ruleMatches(rule, tx) {
+			return rule, true
+		}
+	}
+	return CategoryRule{}, false
+}
+
+// Categorize fills the category of tx from the first matching rule when it has none
+func (tl *TransactionList) Categorize(tx Transaction) Transaction {
+	if NormalizeCategory(tx.Category) != "" {
+		return tx
+	}
+	if rule, found := tl.MatchRule(tx); found {
+		tx.Category = rule.Category
+	}
+	return tx
+}
+
+// PreviewRules returns the category changes running the rules over the ledger would make.
+// Without overwrite only uncategorized transactions change.
+func (tl *TransactionList) PreviewRules(overwrite bool) []RuleChange {
+	var changes []RuleChange
+	for _, tx := range tl.Transactions {
+		if tx.Category != "" && !overwrite {
+			continue
+		}
+		rule, found := tl.MatchRule(tx)
+		if !found || rule.Category == tx.Category {
+			continue
+		}
+		changes = append(changes, RuleChange{Transaction: tx, From: tx.Category, To: rule.Category, RuleID: rule.ID})
+	}
+	return changes
+}
+
+// ApplyRules runs the rules over the ledger and returns the changes made. Only the category
+// changes, so reconciled transactions are updated too.
+func (tl *TransactionList) ApplyRules(overwrite bool) []RuleChange {
+	changes := tl.PreviewRules(overwrite)
+	for _, change := range changes {
+		if index := tl.indexOf(change.Transaction.ID); index >= 0 {
+			tl.Transactions[index].Category = change.To
+		}
+	}
+	return changes
+}
+
+func (tl *TransactionList) ruleMatches(rule CategoryRule, tx Transaction) bool {
+	if rule.AccountID != 0 && tx.AccountID != rule.AccountID {
+		return false
+	}
+	if rule.Type != "" && TransactionTypeForValue(tx.Value) != rule.Type {
+		return false
+	}
+	if rule.Contains != "" {
+		description := accentReplacer.Replace(strings.ToLower(tx.Description))
+		if !strings.Contains(description, accentReplacer.Replace(strings.ToLower(rule.Contains))) {
+			return false
+		}
+	}
+	if rule.Pattern != "" {
+		pattern, err := rule.compile()
+		if err != nil || !pattern.MatchString(tx.Description) {
+			return false
+		}
+	}
+	if rule.MinAmount != nil || rule.MaxAmount != nil {
+		amount := tl.BaseValue(tx).Abs()
+		if rule.MinAmount != nil && amount.Amount < tl.BaseAmount(*rule.MinAmount, tx.Date).Amount {
+			return false
+		}
+		if rule.MaxAmount != nil && amount.Amount > tl.BaseAmount(*rule.MaxAmount, tx.Date).Amount {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRuleValidate(t *testing.T) {
+	limit := func(amount int64) *Money {
+		money := NewMoney(amount, "BRL")
+		return &money
+	}
+	tests := []struct {
+		rule CategoryRule
+		want string
+	}{
+		{CategoryRule{Contains: "uber", Category: "Transporte"}, ""},
+		{CategoryRule{Type: TransactionTypeIncome, Category: "Salário"}, ""},
+		{CategoryRule{Contains: "uber"}, "category is required"},
+		{CategoryRule{Category: "Transporte"}, "at least one condition"},
+		{CategoryRule{Type: TransactionTypeTransfer, Category: "Transporte"}, "income or expense"},
+		{CategoryRule{MinAmount: limit(200), MaxAmount: limit(100), Category: "Transporte"}, "minimum amount is above the maximum"},
+		{CategoryRule{Pattern: "uber(", Category: "Transporte"}, "invalid rule pattern"},
+	}
+	for _, tt := range tests {
+		err := tt.rule.Validate()
+		if tt.want == "" && err != nil {
+			t.Errorf("Validate(%+v) = %v, want nil", tt.rule, err)
+		}
+		if tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+			t.Errorf("Validate(%+v) = %v, want an error with %q", tt.rule, err, tt.want)
+		}
+	}
+}
+
+// addRules stores rules in order and fails the test on error
+func addRules(t *testing.T, tl *TransactionList, rules ...CategoryRule) []CategoryRule {
+	t.Helper()
+	var added []CategoryRule
+	for _, rule := range rules {
+		stored, err := tl.AddRule(rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		added = append(added, stored)
+	}
+	return added
+}
+
+func TestMatchRule(t *testing.T) {
+	tl := newRateLedger(t)
+	wallet, err := tl.AddAccount("Carteira", AccountTypeCash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fifty := NewMoney(5000, "BRL")
+	addRules(t, tl,
+		CategoryRule{Contains: "  Farmacia ", Category: "Saúde>Farmácia"},
+		CategoryRule{Pattern: `^uber\b`, MinAmount: &fifty, Category: "Transporte > Viagens"},
+		CategoryRule{Pattern: `^uber\b`, Category: "Transporte"},
+		CategoryRule{Type: TransactionTypeIncome, Category: "Receitas"},
+		CategoryRule{AccountID: wallet.ID, Type: TransactionTypeExpense, Category: "Dinheiro"},
+	)
+
+	tests := []struct {
+		name        string
+		typ         TransactionType
+		amount      int64
+		currency    string
+		description string
+		accountID   int
+		want        string
+	}{
+		{"contains ignores case and accents", TransactionTypeExpense, 3000, "BRL", "FARMÁCIA São João", 0, "Saúde > Farmácia"},
+		{"first match wins", TransactionTypeExpense, 8000, "BRL", "Uber trip", 0, "Transporte > Viagens"},
+		{"below the minimum", TransactionTypeExpense, 1000, "BRL", "uber trip", 0, "Transporte"},
+		{"minimum in the base currency", TransactionTypeExpense, 2000, "USD", "Uber trip", 0, "Transporte > Viagens"},
+		{"pattern anchored at the start", TransactionTypeExpense, 1000, "BRL", "Estorno Uber", 0, ""},
+		{"type", TransactionTypeIncome, 1000, "BRL", "Pix recebido", 0, "Receitas"},
+		{"account", TransactionTypeExpense, 1000, "BRL", "Feira", wallet.ID, "Dinheiro"},
+		{"no rule", TransactionTypeExpense, 1000, "BRL", "Feira", 0, ""},
+	}
+	for _, tt := range tests {
+		tx := NewTransactionWithDate(tt.typ, NewMoney(tt.amount, tt.currency), tt.description, "", date(2026, 1, 10))
+		tx.AccountID = tt.accountID
+		if got := tl.Categorize(tx).Category; got != tt.want {
+			t.Errorf("%s: category %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	categorized := NewTransactionWithDate(TransactionTypeExpense, NewMoney(1000, "BRL"), "Farmácia", "Presentes", date(2026, 1, 10))
+	if got := tl.Categorize(categorized).Category; got != "Presentes" {
+		t.Errorf("Categorize replaced the category with %q", got)
+	}
+	split := NewTransactionWithDate(TransactionTypeExpense, NewMoney(1000, "BRL"), "Farmácia", "", date(2026, 1, 10))
+	split.Splits = []Split{{Value: NewMoney(-1000, "BRL"), Category: "Casa"}}
+	if _, found := tl.MatchRule(split); found {
+		t.Error("a rule matched a split transaction")
+	}
+}
+
+func TestRuleOrder(t *testing.T) {
+	tl := &TransactionList{}
+	rules := addRules(t, tl,
+		CategoryRule{Contains: "a", Category: "A"},
+		CategoryRule{Contains: "b", Category: "B"},
+		CategoryRule{Contains: "c", Category: "C"},
+	)
+	if err := tl.MoveRule(rules[2].ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := tl.MoveRule(rules[0].ID, true); err != nil {
+		t.Fatal(err)
+	}
+	var order []string
+	for _, rule := range tl.GetRules() {
+		order = append(order, rule.Category)
+	}
+	if strings.Join(order, "") != "ACB" {
+		t.Errorf("order = %q, want A, C, B", order)
+	}
+
+	if err := tl.DeleteRule(rules[1].ID); err != nil {
+		t.Fatal(err)
+	}
+	if added := addRules(t, tl, CategoryRule{Contains: "d", Category: "D"}); added[0].ID != 4 {
+		t.Errorf("new rule ID = %d, want 4", added[0].ID)
+	}
+	if err := tl.DeleteRule(rules[1].ID); !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("deleting twice error = %v, want ErrRuleNotFound", err)
+	}
+	if err := tl.MoveRule(99, false); !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("moving a missing rule error = %v, want ErrRuleNotFound", err)
+	}
+}
+
+func TestApplyRules(t *testing.T) {
+	tl, _, _ := newAccountLedger(t)
+	addRules(t, tl, CategoryRule{Contains: "uber", Category: "Transporte"})
+	uncategorized := bankTransaction(t, tl, TransactionTypeExpense, 1000, "Uber", 5)
+	categorized := bankTransaction(t, tl, TransactionTypeExpense, 1000, "Uber", 6)
+	categorized.Category = "Lazer"
+	if err := tl.UpdateTransaction(categorized); err != nil {
+		t.Fatal(err)
+	}
+	bankTransaction(t, tl, TransactionTypeExpense, 1000, "Padaria", 7)
+
+	if changes := tl.PreviewRules(false); len(changes) != 1 || changes[0].Transaction.ID != uncategorized.ID || changes[0].To != "Transporte" {
+		t.Errorf("preview without overwrite = %+v", changes)
+	}
+	if tx, _ := tl.GetTransactionByID(uncategorized.ID); tx.Category != "" {
+		t.Error("the preview changed a category")
+	}
+
+	// Reconciled transactions are locked, but their category still changes
+	if err := tl.SetCleared(categorized.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tl.FinishReconciliation(tl.DefaultAccountID(), date(2026, 1, 6), NewMoney(-1000, "BRL")); err != nil {
+		t.Fatal(err)
+	}
+	changes := tl.ApplyRules(true)
+	if len(changes) != 2 || changes[1].From != "Lazer" {
+		t.Fatalf("changes with overwrite = %+v", changes)
+	}
+	for _, id := range []int{uncategorized.ID, categorized.ID} {
+		if tx, _ := tl.GetTransactionByID(id); tx.Category != "Transporte" {
+			t.Errorf("transaction %d has category %q after applying the rules", id, tx.Category)
+		}
+	}
+	if again := tl.ApplyRules(true); len(again) != 0 {
+		t.Errorf("applying the rules again made %d changes", len(again))
+	}
+}
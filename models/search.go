@@ -0,0 +1,249 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SearchSyntaxError reports an invalid search expression and where it happened. Its message is
+// shown below the search box, so it is written in Portuguese like the rest of the UI.
+type SearchSyntaxError struct {
+	// Position is the 1-based character position of the offending term
+	Position int
+	Message  string
+}
+
+func (e *SearchSyntaxError) Error() string {
+	return fmt.Sprintf("posição %d: %s", e.Position, e.Message)
+}
+
+// searchToken is a term of a search expression: key, operator and value, or a bare value for text
+type searchToken struct {
+	position int
+	key      string
+	operator string
+	value    string
+}
+
+// searchOperators are tried longest first so that ">=" is not read as ">"
+var searchOperators = []string{">=", "<=", ":", "=", ">", "<"}
+
+// searchKeys are the filter names; any other "name:value" term is plain text
+var searchKeys = map[string]bool{
+	"cat": true, "categoria": true, "tipo": true, "conta": true, "tag": true, "valor": true, "data": true,
+}
+
+// ParseSearch turns a search expression into a query. Terms are combined with AND:
+//
+//	cat:Alimentação   category or subcategory (several cat: terms match any of them)
+//	tipo:despesa      transaction type
+//	conta:Carteira    account name
+//	tag:viagem        tag (several tag: terms must all match)
+//	valor>100         absolute value in the base currency; also >=, <, <=, : and =
+//	data:2025-03      day, month or year; also >, >=, < and <=
+//	ifood "pão de queijo"  text in the description
+//
+// Values with spaces are quoted, e.g. cat:"Casa > Reparos". Terms that do not start with one of
+// these filter names, such as http://x or 12:30, are text.
+func (tl *TransactionList) ParseSearch(input string) (Query, error) {
+	tokens, err := tokenizeSearch(input)
+	if err != nil {
+		return Query{}, err
+	}
+
+	var query Query
+	for _, token := range tokens {
+		if token.key == "" {
+			query.Terms = append(query.Terms, token.value)
+			continue
+		}
+		if token.value == "" {
+			return Query{}, &SearchSyntaxError{token.position, fmt.Sprintf("falta o valor depois de %s%s", token.key, token.operator)}
+		}
+		if err := tl.applySearchToken(&query, token); err != nil {
+			return Query{}, err
+		}
+	}
+	return query, nil
+}
+
+func (tl *TransactionList) applySearchToken(query *Query, token searchToken) error {
+	syntaxError := func(format string, args ...interface{}) error {
+		return &SearchSyntaxError{token.position, fmt.Sprintf(format, args...)}
+	}
+	requireEquality := func() error {
+		if token.operator != ":" && token.operator != "=" {
+			return syntaxError("%s não aceita %s", token.key, token.operator)
+		}
+		return nil
+	}
+
+	switch strings.ToLower(token.key) {
+	case "cat", "categoria":
+		if err := requireEquality(); err != nil {
+			return err
+		}
+		query.Categories = append(query.Categories, token.value)
+
+	case "tipo":
+		if err := requireEquality(); err != nil {
+			return err
+		}
+		transactionType, err := ParseTransactionType(token.value)
+		if err != nil {
+			return syntaxError("tipo desconhecido %q", token.value)
+		}
+		query.Types = append(query.Types, transactionType)
+
+	case "conta":
+		if err := requireEquality(); err != nil {
+			return err
+		}
+		account, err := tl.GetAccountByName(token.value)
+		if err != nil {
+			return syntaxError("conta desconhecida %q", token.value)
+		}
+		query.AccountID = account.ID
+
+	case "tag":
+		if err := requireEquality(); err != nil {
+			return err
+		}
+		query.Tags = append(query.Tags, token.value)
+		query.MatchAllTags = true
+
+	case "valor":
+		value, err := ParseMoney(token.value, tl.GetBaseCurrency())
+		if err != nil {
+			return syntaxError("valor inválido %q", token.value)
+		}
+		value = value.Abs()
+		oneCent := NewMoney(1, value.Currency)
+		switch token.operator {
+		case ">":
+			min := value.Add(oneCent)
+			query.MinAmount = &min
+		case ">=":
+			query.MinAmount = &value
+		case "<":
+			max := value.Sub(oneCent)
+			query.MaxAmount = &max
+		case "<=":
+			query.MaxAmount = &value
+		default:
+			query.MinAmount, query.MaxAmount = &value, &value
+		}
+
+	case "data":
+		period, err := parseSearchDate(token.value)
+		if err != nil {
+			return syntaxError("data inválida %q, use AAAA, AAAA-MM ou AAAA-MM-DD", token.value)
+		}
+		switch token.operator {
+		case ">":
+			period = CustomPeriod(period.End, time.Time{})
+		case ">=":
+			period = CustomPeriod(period.Start, time.Time{})
+		case "<":
+			period = CustomPeriod(time.Time{}, period.Start)
+		case "<=":
+			period = CustomPeriod(time.Time{}, period.End)
+		}
+		// Several date terms narrow the range
+		query.Period = query.Period.Intersect(period)
+
+	default:
+		return syntaxError("filtro desconhecido %q", token.key)
+	}
+	return nil
+}
+
+// parseSearchDate returns the local day, month or year written as YYYY-MM-DD, YYYY-MM or YYYY
+func parseSearchDate(value string) (Period, error) {
+	layouts := []struct {
+		layout string
+		kind   PeriodKind
+	}{
+		{"2006-01-02", PeriodDay},
+		{"2006-01", PeriodMonth},
+		{"2006", PeriodYear},
+	}
+	for _, l := range layouts {
+		if len(value) != len(l.layout) {
+			continue
+		}
+		date, err := time.ParseInLocation(l.layout, value, time.Local)
+		if err == nil {
+			return NewPeriod(l.kind, date, 0), nil
+		}
+	}
+	return Period{}, fmt.Errorf("invalid date %q", value)
+}
+
+// tokenizeSearch splits an expression on whitespace, honouring double quotes both around
+// whole terms and around values, and separates each term into key, operator and value
+func tokenizeSearch(input string) ([]searchToken, error) {
+	runes := []rune(input)
+	var tokens []searchToken
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		position := i + 1
+		var raw strings.Builder
+		quoted := false
+		for i < len(runes) && !unicode.IsSpace(runes[i]) {
+			if runes[i] != '"' {
+				raw.WriteRune(runes[i])
+				i++
+				continue
+			}
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, &SearchSyntaxError{i + 1, "aspas sem fechamento"}
+			}
+			raw.WriteString(string(runes[i+1 : end]))
+			quoted = true
+			i = end + 1
+		}
+
+		token := searchToken{position: position, value: raw.String()}
+		if !(quoted && strings.HasPrefix(string(runes[position-1:]), `"`)) {
+			token = splitSearchTerm(position, raw.String())
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+// splitSearchTerm separates "key<op>value"; terms without a known operator are plain text
+func splitSearchTerm(position int, term string) searchToken {
+	index, operator := -1, ""
+	for _, op := range searchOperators {
+		if i := strings.Index(term, op); i > 0 && (index < 0 || i < index || (i == index && len(op) > len(operator))) {
+			index, operator = i, op
+		}
+	}
+	if index < 0 || !isSearchKey(term[:index]) {
+		return searchToken{position: position, value: term}
+	}
+	return searchToken{
+		position: position,
+		key:      term[:index],
+		operator: operator,
+		value:    term[index+len(operator):],
+	}
+}
+
+// isSearchKey reports whether s is a filter name, so that text such as "12:30" or "http://x" stays text
+func isSearchKey(s string) bool {
+	return searchKeys[strings.ToLower(s)]
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newSearchLedger returns a ledger in BRL with the default account and a "Carteira" account
+func newSearchLedger(t *testing.T) (*TransactionList, Account) {
+	t.Helper()
+	tl := &TransactionList{BaseCurrency: "BRL"}
+	tl.EnsureAccounts()
+	wallet, err := tl.AddAccount("Carteira", AccountTypeCash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tl, wallet
+}
+
+func TestParseSearch(t *testing.T) {
+	tl, wallet := newSearchLedger(t)
+	brl := func(amount int64) *Money {
+		money := NewMoney(amount, "BRL")
+		return &money
+	}
+	local := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		in   string
+		want Query
+	}{
+		{"", Query{}},
+		{" \t ", Query{}},
+		{`ifood "pão de queijo"`, Query{Terms: []string{"ifood", "pão de queijo"}}},
+		{`cat:Alimentação categoria:"Casa > Reparos"`, Query{Categories: []string{"Alimentação", "Casa > Reparos"}}},
+		{"tipo:despesa TIPO=Receita", Query{Types: []TransactionType{TransactionTypeExpense, TransactionTypeIncome}}},
+		{"conta:carteira", Query{AccountID: wallet.ID}},
+		{"tag:viagem tag=trabalho", Query{Tags: []string{"viagem", "trabalho"}, MatchAllTags: true}},
+		{"valor>100", Query{MinAmount: brl(10001)}},
+		{"valor>=100", Query{MinAmount: brl(10000)}},
+		{"valor<50,5", Query{MaxAmount: brl(5049)}},
+		{"valor<=50", Query{MaxAmount: brl(5000)}},
+		{"valor:-20", Query{MinAmount: brl(2000), MaxAmount: brl(2000)}},
+		{"valor>=10 valor<=20", Query{MinAmount: brl(1000), MaxAmount: brl(2000)}},
+		{"data:2025-03", Query{Period: NewPeriod(PeriodMonth, local(2025, 3, 1), 0)}},
+		{"data=2025-03-10", Query{Period: NewPeriod(PeriodDay, local(2025, 3, 10), 0)}},
+		{"data:2025", Query{Period: NewPeriod(PeriodYear, local(2025, 1, 1), 0)}},
+		{"data>2025", Query{Period: CustomPeriod(local(2026, 1, 1), time.Time{})}},
+		{"data<=2025-03", Query{Period: CustomPeriod(time.Time{}, local(2025, 4, 1))}},
+		{"data>=2025-03-10 data<2025-04", Query{Period: CustomPeriod(local(2025, 3, 10), local(2025, 4, 1))}},
+		// Text that merely contains an operator stays text
+		{"12:30 9>5", Query{Terms: []string{"12:30", "9>5"}}},
+		{"http://exemplo.com mercado foo:bar a>b:c", Query{Terms: []string{"http://exemplo.com", "mercado", "foo:bar", "a>b:c"}}},
+		{"CAT:Lazer", Query{Categories: []string{"Lazer"}}},
+		{`"cat:Alimentação"`, Query{Terms: []string{"cat:Alimentação"}}},
+		{`cat:Lazer mercado valor>10`, Query{Categories: []string{"Lazer"}, Terms: []string{"mercado"}, MinAmount: brl(1001)}},
+	}
+	for _, tt := range tests {
+		got, err := tl.ParseSearch(tt.in)
+		if err != nil {
+			t.Errorf("ParseSearch(%q) error: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSearch(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseSearchErrors(t *testing.T) {
+	tl, _ := newSearchLedger(t)
+	tests := []struct {
+		in       string
+		position int
+		message  string
+	}{
+		{`"pão de queijo`, 1, "aspas sem fechamento"},
+		{`texto cat:"Casa`, 11, "aspas sem fechamento"},
+		{"ifood cat:", 7, "falta o valor depois de cat:"},
+		{"valor>", 1, "falta o valor depois de valor>"},
+		{"valor>abc", 1, `valor inválido "abc"`},
+		{"valor:1.234", 1, `valor inválido "1.234"`},
+		{"data:2025-13", 1, "data inválida"},
+		{"data:25-03", 1, "data inválida"},
+		{"data:março", 1, "data inválida"},
+		{"tipo:foo", 1, `tipo desconhecido "foo"`},
+		{"conta:Inexistente", 1, `conta desconhecida "Inexistente"`},
+		{"cat>Casa", 1, "cat não aceita >"},
+		{"mercado tag<=viagem", 9, "tag não aceita <="},
+	}
+	for _, tt := range tests {
+		query, err := tl.ParseSearch(tt.in)
+		var syntaxErr *SearchSyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("ParseSearch(%q) = %+v, %v; want a syntax error", tt.in, query, err)
+			continue
+		}
+		if syntaxErr.Position != tt.position || !strings.Contains(syntaxErr.Message, tt.message) {
+			t.Errorf("ParseSearch(%q) error = %q, want position %d and %q", tt.in, err, tt.position, tt.message)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Split is a portion of a transaction with its own amount, category and description
+type Split struct {
+	Value       Money  `json:"value"`
+	Category    string `json:"category"`
+	Description string `json:"description,omitempty"`
+}
+
+// IsSplit reports whether the transaction is divided into splits
+func (t Transaction) IsSplit() bool {
+	return len(t.Splits) > 0
+}
+
+// ValidateSplits checks that the splits add up to the transaction value in the same currency
+func (t Transaction) ValidateSplits() error {
+	if !t.IsSplit() {
+		return nil
+	}
+
+	total := NewMoney(0, t.Value.Currency)
+	for i, split := range t.Splits {
+		if split.Value.Currency != t.Value.Currency {
+			return fmt.Errorf("split %d is in %s but the transaction is in %s", i+1, split.Value.Currency, t.Value.Currency)
+		}
+		total = total.Add(split.Value)
+	}
+	if total.Amount != t.Value.Amount {
+		return fmt.Errorf("splits add up to %s but the transaction value is %s", total, t.Value)
+	}
+	return nil
+}
+
+// SetSplits replaces the splits after applying the transaction's sign convention and validating their sum
+func (t *Transaction) SetSplits(splits []Split) error {
+	candidate := *t
+	candidate.Splits = normalizeSplits(t.Type, splits)
+	if err := candidate.ValidateSplits(); err != nil {
+		return err
+	}
+	t.Splits = candidate.Splits
+	return nil
+}
+
+// Parts returns the splits of the transaction, or a single part covering the whole
+// transaction when it is not split. Reports use it to attribute amounts to categories.
+func (t Transaction) Parts() []Split {
+	if !t.IsSplit() {
+		return []Split{{Value: t.Value, Category: t.Category, Description: t.Description}}
+	}
+
+	parts := make([]Split, len(t.Splits))
+	for i, split := range t.Splits {
+		parts[i] = split
+		if strings.TrimSpace(split.Description) == "" {
+			parts[i].Description = t.Description
+		}
+	}
+	return parts
+}
+
+// InCategory reports whether the transaction, or any of its splits, belongs to a category subtree
+func (t Transaction) InCategory(category string) bool {
+	for _, part := range t.Parts() {
+		if IsInCategory(part.Category, category) {
+			return true
+		}
+	}
+	return false
+}
+
+// CategoryLabel describes the category for display; split transactions list their categories
+func (t Transaction) CategoryLabel() string {
+	if !t.IsSplit() {
+		return t.Category
+	}
+	var categories []string
+	for _, split := range t.Splits {
+		categories = append(categories, split.Category)
+	}
+	return fmt.Sprintf("Dividida: %s", strings.Join(categories, ", "))
+}
+
+func normalizeSplits(transactionType TransactionType, splits []Split) []Split {
+	var normalized []Split
+	for _, split := range splits {
+		split.Value = transactionType.SignedValue(split.Value)
+		split.Category = NormalizeCategory(split.Category)
+		split.Description = strings.TrimSpace(split.Description)
+		normalized = append(normalized, split)
+	}
+	return normalized
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+// groceries returns a 100.00 expense split between food and cleaning supplies
+func groceries() Transaction {
+	tx := NewTransactionWithDate(TransactionTypeExpense, NewMoney(10000, "BRL"), "Mercado", "", date(2026, 1, 10))
+	tx.Splits = []Split{
+		{Value: NewMoney(7000, "BRL"), Category: "Alimentação"},
+		{Value: NewMoney(3000, "BRL"), Category: "Casa>Limpeza", Description: " Detergente "},
+	}
+	return tx
+}
+
+func TestValidateSplits(t *testing.T) {
+	tests := []struct {
+		name   string
+		splits []Split
+		want   string
+	}{
+		{"not split", nil, ""},
+		{"adds up", []Split{{Value: NewMoney(-7000, "BRL")}, {Value: NewMoney(-3000, "BRL")}}, ""},
+		{"short", []Split{{Value: NewMoney(-7000, "BRL")}, {Value: NewMoney(-2999, "BRL")}}, "splits add up to"},
+		{"wrong sign", []Split{{Value: NewMoney(7000, "BRL")}, {Value: NewMoney(3000, "BRL")}}, "splits add up to"},
+		{"other currency", []Split{{Value: NewMoney(-7000, "BRL")}, {Value: NewMoney(-3000, "USD")}}, "split 2 is in USD"},
+	}
+	for _, tt := range tests {
+		tx := Transaction{Type: TransactionTypeExpense, Value: NewMoney(-10000, "BRL"), Splits: tt.splits}
+		err := tx.ValidateSplits()
+		if tt.want == "" && err != nil {
+			t.Errorf("%s: ValidateSplits() = %v, want nil", tt.name, err)
+		}
+		if tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+			t.Errorf("%s: ValidateSplits() = %v, want an error with %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestSetSplitsAppliesTheSign(t *testing.T) {
+	tx := groceries()
+	splits := tx.Splits
+	tx.Splits = nil
+	if err := tx.SetSplits(splits); err != nil {
+		t.Fatal(err)
+	}
+	if tx.Splits[0].Value.Amount != -7000 || tx.Splits[1].Value.Amount != -3000 || tx.Splits[1].Category != "Casa > Limpeza" {
+		t.Errorf("splits = %+v", tx.Splits)
+	}
+	if err := tx.SetSplits([]Split{{Value: NewMoney(1, "BRL")}}); err == nil {
+		t.Error("splits that do not add up were set")
+	}
+	if len(tx.Splits) != 2 {
+		t.Errorf("a rejected SetSplits changed the splits to %+v", tx.Splits)
+	}
+}
+
+func TestAddTransactionValidatesSplits(t *testing.T) {
+	tl := &TransactionList{}
+	tl.EnsureAccounts()
+	if err := tl.AddTransaction(groceries()); err != nil {
+		t.Fatal(err)
+	}
+	added := tl.Transactions[0]
+	if added.Splits[0].Value.Amount != -7000 || added.Splits[1].Description != "Detergente" {
+		t.Errorf("stored splits = %+v", added.Splits)
+	}
+
+	short := groceries()
+	short.Splits[1].Value = NewMoney(2000, "BRL")
+	if err := tl.AddTransaction(short); err == nil {
+		t.Error("a transaction whose splits do not add up was added")
+	}
+	mixed := groceries()
+	mixed.Splits[1].Value = NewMoney(3000, "USD")
+	if err := tl.AddTransaction(mixed); err == nil {
+		t.Error("a transaction with a split in another currency was added")
+	}
+	if len(tl.Transactions) != 1 {
+		t.Errorf("%d transactions stored, want only the valid one", len(tl.Transactions))
+	}
+}
+
+func TestParts(t *testing.T) {
+	plain := NewTransactionWithDate(TransactionTypeExpense, NewMoney(500, "BRL"), "Café", "Alimentação", date(2026, 1, 10))
+	if parts := plain.Parts(); len(parts) != 1 || parts[0].Value != plain.Value || parts[0].Category != "Alimentação" {
+		t.Errorf("Parts() of a plain transaction = %+v", parts)
+	}
+
+	tx := groceries()
+	parts := tx.Parts()
+	// A split without a description takes the transaction's
+	if len(parts) != 2 || parts[0].Description != "Mercado" || parts[1].Description != " Detergente " {
+		t.Errorf("Parts() = %+v", parts)
+	}
+	if !tx.InCategory("Casa") || tx.InCategory("Lazer") {
+		t.Error("InCategory does not look at the splits")
+	}
+	if label := tx.CategoryLabel(); label != "Dividida: Alimentação, Casa>Limpeza" {
+		t.Errorf("CategoryLabel() = %q", label)
+	}
+}
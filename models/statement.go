@@ -0,0 +1,157 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Statement is a credit card bill: the card transactions from one closing date up to the next.
+// Purchases made on the closing day already belong to the next statement.
+type Statement struct {
+	AccountID int
+	// Period runs from the previous closing day (included) to the closing day (excluded)
+	Period  Period
+	Closing time.Time
+	Due     time.Time
+	// Transactions are the purchases, refunds and other charges of the statement, without payments
+	Transactions []Transaction
+	// Total is the amount billed, positive when the card owes money
+	Total Money
+	// Paid sums the payments into the card from the closing day up to the next closing
+	Paid Money
+}
+
+// Remaining returns what is still to be paid of the statement
+func (s Statement) Remaining() Money {
+	return s.Total.Sub(s.Paid)
+}
+
+// IsClosed reports whether the statement no longer accepts purchases at now
+func (s Statement) IsClosed(now time.Time) bool {
+	return !now.Before(s.Closing)
+}
+
+// IsPaid reports whether the payments cover the amount billed
+func (s Statement) IsPaid() bool {
+	return s.Remaining().Amount <= 0
+}
+
+// Label names the statement by the month it is due, e.g. "Fatura novembro/2026"
+func (s Statement) Label() string {
+	return fmt.Sprintf("Fatura %s/%d", monthNames[s.Due.Month()-1], s.Due.Year())
+}
+
+// StatementFor returns the statement of a credit card that a date belongs to
+func (tl *TransactionList) StatementFor(accountID int, date time.Time) (Statement, error) {
+	card, err := tl.creditCard(accountID)
+	if err != nil {
+		return Statement{}, err
+	}
+
+	day := NewPeriod(PeriodDay, date, 0).Start
+	closing := dayInMonth(day.Year(), day.Month(), card.ClosingDay, day.Location())
+	if !day.Before(closing) {
+		closing = dayInMonth(day.Year(), day.Month()+1, card.ClosingDay, day.Location())
+	}
+	return tl.statement(card, closing), nil
+}
+
+// GetStatements returns the statements of a credit card in chronological order, from the one
+// with the first transaction through the one open at now, or the last one with transactions
+// when installments are billed later
+func (tl *TransactionList) GetStatements(accountID int, now time.Time) ([]Statement, error) {
+	if _, err := tl.creditCard(accountID); err != nil {
+		return nil, err
+	}
+
+	transactions := tl.Query(Query{AccountID: accountID, SortBy: SortByDate})
+	first, last := now, now
+	if len(transactions) > 0 {
+		if transactions[0].Date.Before(first) {
+			first = transactions[0].Date
+		}
+		if transactions[len(transactions)-1].Date.After(last) {
+			last = transactions[len(transactions)-1].Date
+		}
+	}
+
+	current, _ := tl.StatementFor(accountID, first)
+	end, _ := tl.StatementFor(accountID, last)
+	statements := []Statement{current}
+	for current.Closing.Before(end.Closing) {
+		current, _ = tl.StatementFor(accountID, current.Closing)
+		statements = append(statements, current)
+	}
+	return statements, nil
+}
+
+// PayStatement records the payment of a credit card statement as a transfer from another account.
+// A zero amount pays what is still due on the statement.
+func (tl *TransactionList) PayStatement(cardID, fromAccountID int, statement Statement, amount Money, date time.Time) (Transaction, Transaction, error) {
+	card, err := tl.creditCard(cardID)
+	if err != nil {
+		return Transaction{}, Transaction{}, err
+	}
+	if amount.IsZero() {
+		amount = statement.Remaining()
+	}
+	if amount.IsNegative() || amount.IsZero() {
+		return Transaction{}, Transaction{}, fmt.Errorf("statement has nothing to pay")
+	}
+	description := fmt.Sprintf("Pagamento %s - %s", statement.Label(), card.Name)
+	return tl.AddTransfer(fromAccountID, cardID, amount, description, date)
+}
+
+// statement collects the transactions of the statement closing on the given day
+func (tl *TransactionList) statement(card Account, closing time.Time) Statement {
+	previous := dayInMonth(closing.Year(), closing.Month()-1, card.ClosingDay, closing.Location())
+	next := dayInMonth(closing.Year(), closing.Month()+1, card.ClosingDay, closing.Location())
+	due := dayInMonth(closing.Year(), closing.Month(), card.DueDay, closing.Location())
+	if card.DueDay <= card.ClosingDay {
+		due = dayInMonth(closing.Year(), closing.Month()+1, card.DueDay, closing.Location())
+	}
+
+	base := tl.GetBaseCurrency()
+	statement := Statement{
+		AccountID: card.ID,
+		Period:    CustomPeriod(previous, closing),
+		Closing:   closing,
+		Due:       due,
+		Total:     NewMoney(0, base),
+		Paid:      NewMoney(0, base),
+	}
+	for _, tx := range tl.Query(Query{AccountID: card.ID, Period: statement.Period, SortBy: SortByDate}) {
+		if tx.Type == TransactionTypeTransfer && tx.Value.Amount > 0 {
+			continue
+		}
+		statement.Transactions = append(statement.Transactions, tx)
+		statement.Total = statement.Total.Sub(tl.BaseValue(tx))
+	}
+	for _, tx := range tl.Query(Query{AccountID: card.ID, Period: CustomPeriod(closing, next)}) {
+		if tx.Type == TransactionTypeTransfer && tx.Value.Amount > 0 {
+			statement.Paid = statement.Paid.Add(tl.BaseValue(tx))
+		}
+	}
+	return statement
+}
+
+// creditCard returns the account if it is a credit card with statement days
+func (tl *TransactionList) creditCard(accountID int) (Account, error) {
+	account, err := tl.GetAccountByID(accountID)
+	if err != nil {
+		return Account{}, err
+	}
+	if !account.IsCreditCard() {
+		return Account{}, fmt.Errorf("account %q is not a credit card with closing and due days", account.Name)
+	}
+	return account, nil
+}
+
+// dayInMonth returns the given day of a month at midnight, or the month's last day when it is shorter
+func dayInMonth(year int, month time.Month, day int, loc *time.Location) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newCardLedger returns a ledger with a checking account and a card that closes on the 25th and is due on the 5th
+func newCardLedger(t *testing.T) (*TransactionList, Account, Account) {
+	t.Helper()
+	tl := &TransactionList{BaseCurrency: "BRL"}
+	tl.EnsureAccounts()
+	checking, err := tl.GetAccountByID(tl.DefaultAccountID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	card, err := tl.AddCreditCard("Visa", 25, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tl, checking, card
+}
+
+// charge adds a card transaction of the given type and amount
+func charge(t *testing.T, tl *TransactionList, card Account, typ TransactionType, amount int64, description string, on time.Time) {
+	t.Helper()
+	tx := NewTransactionWithDate(typ, NewMoney(amount, "BRL"), description, "", on)
+	tx.AccountID = card.ID
+	if err := tl.AddTransaction(tx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStatementFor(t *testing.T) {
+	tl, checking, card := newCardLedger(t)
+	lateCard, err := tl.AddCreditCard("Master", 31, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                string
+		card                Account
+		date                time.Time
+		start, closing, due time.Time
+		label               string
+	}{
+		{"before closing", card, date(2026, 1, 10), date(2025, 12, 25), date(2026, 1, 25), date(2026, 2, 5), "Fatura fevereiro/2026"},
+		{"closing day belongs to the next", card, date(2026, 1, 25), date(2026, 1, 25), date(2026, 2, 25), date(2026, 3, 5), "Fatura março/2026"},
+		{"across the year", card, date(2026, 12, 30), date(2026, 12, 25), date(2027, 1, 25), date(2027, 2, 5), "Fatura fevereiro/2027"},
+		{"closing clamped to february", lateCard, date(2026, 2, 15), date(2026, 1, 31), date(2026, 2, 28), date(2026, 3, 10), "Fatura março/2026"},
+		{"clamped closing day belongs to the next", lateCard, date(2026, 2, 28), date(2026, 2, 28), date(2026, 3, 31), date(2026, 4, 10), "Fatura abril/2026"},
+	}
+	for _, tt := range tests {
+		statement, err := tl.StatementFor(tt.card.ID, tt.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !statement.Period.Start.Equal(tt.start) || !statement.Closing.Equal(tt.closing) || !statement.Period.End.Equal(tt.closing) || !statement.Due.Equal(tt.due) {
+			t.Errorf("%s: statement from %v closing %v due %v", tt.name, statement.Period.Start, statement.Closing, statement.Due)
+		}
+		if label := statement.Label(); label != tt.label {
+			t.Errorf("%s: Label() = %q, want %q", tt.name, label, tt.label)
+		}
+	}
+
+	if _, err := tl.StatementFor(checking.ID, date(2026, 1, 10)); err == nil {
+		t.Error("a checking account has statements")
+	}
+}
+
+func TestStatementTotalsAndPayment(t *testing.T) {
+	tl, checking, card := newCardLedger(t)
+	charge(t, tl, card, TransactionTypeExpense, 30000, "Mercado", date(2026, 1, 10))
+	charge(t, tl, card, TransactionTypeIncome, 5000, "Estorno", date(2026, 1, 12))
+	charge(t, tl, card, TransactionTypeExpense, 12000, "Farmácia", date(2026, 1, 25))
+
+	january, err := tl.StatementFor(card.ID, date(2026, 1, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(january.Transactions) != 2 || january.Total.Amount != 25000 || january.IsPaid() {
+		t.Fatalf("January statement = %d transactions, total %v", len(january.Transactions), january.Total)
+	}
+	if january.IsClosed(date(2026, 1, 24)) || !january.IsClosed(date(2026, 1, 25)) {
+		t.Error("the statement must close on the closing day")
+	}
+
+	// A partial payment and then the rest, paid with a zero amount
+	if _, _, err := tl.PayStatement(card.ID, checking.ID, january, NewMoney(10000, "BRL"), date(2026, 1, 30)); err != nil {
+		t.Fatal(err)
+	}
+	january, _ = tl.StatementFor(card.ID, date(2026, 1, 10))
+	if january.Paid.Amount != 10000 || january.Remaining().Amount != 15000 {
+		t.Errorf("after a partial payment paid %v, remaining %v", january.Paid, january.Remaining())
+	}
+	_, incoming, err := tl.PayStatement(card.ID, checking.ID, january, NewMoney(0, "BRL"), date(2026, 2, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if incoming.Value.Amount != 15000 || incoming.Description != "Pagamento Fatura fevereiro/2026 - Visa" {
+		t.Errorf("payment = %+v", incoming)
+	}
+	january, _ = tl.StatementFor(card.ID, date(2026, 1, 10))
+	if !january.IsPaid() {
+		t.Errorf("January statement remaining %v after paying it", january.Remaining())
+	}
+	if _, _, err := tl.PayStatement(card.ID, checking.ID, january, NewMoney(0, "BRL"), date(2026, 2, 5)); err == nil {
+		t.Error("a paid statement was paid again")
+	}
+
+	// Payments are not charges of the next statement
+	february, _ := tl.StatementFor(card.ID, date(2026, 2, 1))
+	if len(february.Transactions) != 1 || february.Total.Amount != 12000 || !february.Paid.IsZero() {
+		t.Errorf("February statement = %d transactions, total %v, paid %v", len(february.Transactions), february.Total, february.Paid)
+	}
+}
+
+func TestGetStatements(t *testing.T) {
+	tl, _, card := newCardLedger(t)
+	charge(t, tl, card, TransactionTypeExpense, 1000, "Antiga", date(2025, 11, 3))
+	charge(t, tl, card, TransactionTypeExpense, 1000, "Parcela futura", date(2026, 3, 1))
+
+	statements, err := tl.GetStatements(card.ID, date(2026, 1, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var closings []string
+	for _, statement := range statements {
+		closings = append(closings, statement.Closing.Format("2006-01-02"))
+	}
+	// From the first transaction through the installment billed after now
+	want := []string{"2025-11-25", "2025-12-25", "2026-01-25", "2026-02-25", "2026-03-25"}
+	if !reflect.DeepEqual(closings, want) {
+		t.Errorf("closings = %q, want %q", closings, want)
+	}
+	if later, _ := tl.GetStatements(card.ID, date(2030, 1, 1)); len(later) != 51 {
+		t.Errorf("%d statements until 2030, want one per month from November 2025", len(later))
+	}
+}
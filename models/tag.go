@@ -0,0 +1,121 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// TagTotal holds the income and expense of all transactions carrying a tag
+type TagTotal struct {
+	Tag     string
+	Count   int
+	Income  Money
+	Expense Money
+}
+
+// Balance returns income minus expense of the tag
+func (tt TagTotal) Balance() Money {
+	return tt.Income.Sub(tt.Expense)
+}
+
+// ParseTags splits a comma- or semicolon-separated list of tags
+func ParseTags(s string) []string {
+	return NormalizeTags(strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';'
+	}))
+}
+
+// FormatTags joins tags for display and export
+func FormatTags(tags []string) string {
+	return strings.Join(tags, ", ")
+}
+
+// NormalizeTags trims, lowercases, deduplicates and sorts tags
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// HasTag reports whether the transaction carries a tag
+func (t Transaction) HasTag(tag string) bool {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	for _, existing := range t.Tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTags returns all unique tags used by transactions, sorted
+func (tl *TransactionList) GetTags() []string {
+	var all []string
+	for _, tx := range tl.Transactions {
+		all = append(all, tx.Tags...)
+	}
+	return NormalizeTags(all)
+}
+
+// BuildTagTotals returns income and expense per tag of transactions, sorted by tag,
+// converting amounts with value. Transfers are not counted.
+func BuildTagTotals(transactions []Transaction, value func(Money, time.Time) Money, currency string) []TagTotal {
+	totals := make(map[string]*TagTotal)
+	var tags []string
+	for _, tx := range transactions {
+		if tx.Type == TransactionTypeTransfer {
+			continue
+		}
+		for _, tag := range tx.Tags {
+			total, ok := totals[tag]
+			if !ok {
+				total = &TagTotal{
+					Tag:     tag,
+					Income:  NewMoney(0, currency),
+					Expense: NewMoney(0, currency),
+				}
+				totals[tag] = total
+				tags = append(tags, tag)
+			}
+			total.Count++
+			if tx.Type == TransactionTypeIncome {
+				total.Income = total.Income.Add(value(tx.Value, tx.Date))
+			} else {
+				total.Expense = total.Expense.Add(value(tx.Value, tx.Date).Abs())
+			}
+		}
+	}
+
+	sort.Strings(tags)
+	result := make([]TagTotal, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, *totals[tag])
+	}
+	return result
+}
+
+func matchTags(tx Transaction, tags []string, matchAll bool) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		has := tx.HasTag(tag)
+		if matchAll && !has {
+			return false
+		}
+		if !matchAll && has {
+			return true
+		}
+	}
+	return matchAll
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{" , ;", nil},
+		{"viagem", []string{"viagem"}},
+		{"Viagem, trabalho; VIAGEM ,  férias ", []string{"férias", "trabalho", "viagem"}},
+	}
+	for _, tt := range tests {
+		if got := ParseTags(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseTags(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	if got := FormatTags([]string{"férias", "viagem"}); got != "férias, viagem" {
+		t.Errorf("FormatTags = %q", got)
+	}
+}
+
+// taggedLedger returns transactions tagged with combinations of "viagem" and "trabalho"
+func taggedLedger(t *testing.T) *TransactionList {
+	t.Helper()
+	tl := &TransactionList{BaseCurrency: "BRL"}
+	tl.EnsureAccounts()
+	for _, tx := range []struct {
+		typ    TransactionType
+		amount int64
+		tags   []string
+	}{
+		{TransactionTypeExpense, 50000, []string{"Viagem"}},
+		{TransactionTypeExpense, 20000, []string{"viagem", "trabalho"}},
+		{TransactionTypeIncome, 30000, []string{"trabalho"}},
+		{TransactionTypeExpense, 1000, nil},
+		{TransactionTypeTransfer, -9000, []string{"viagem"}},
+	} {
+		transaction := NewTransactionWithDate(tx.typ, NewMoney(tx.amount, "BRL"), "", "", date(2026, 3, 10))
+		transaction.Tags = tx.tags
+		if err := tl.AddTransaction(transaction); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tl
+}
+
+func TestFilterByTags(t *testing.T) {
+	tl := taggedLedger(t)
+	if got := tl.GetTags(); !reflect.DeepEqual(got, []string{"trabalho", "viagem"}) {
+		t.Errorf("GetTags() = %q", got)
+	}
+	if !tl.Transactions[0].HasTag(" VIAGEM ") || tl.Transactions[0].HasTag("trabalho") {
+		t.Error("HasTag must ignore case and spaces and only match the tags of the transaction")
+	}
+
+	tests := []struct {
+		tags     []string
+		matchAll bool
+		want     int
+	}{
+		{nil, false, 5},
+		{[]string{"viagem"}, false, 3},
+		{[]string{"viagem", "trabalho"}, false, 4},
+		{[]string{"viagem", "trabalho"}, true, 1},
+		{[]string{"casa"}, false, 0},
+	}
+	for _, tt := range tests {
+		if got := tl.Query(Query{Tags: tt.tags, MatchAllTags: tt.matchAll}); len(got) != tt.want {
+			t.Errorf("tags %q (all %v) matched %d transactions, want %d", tt.tags, tt.matchAll, len(got), tt.want)
+		}
+	}
+}
+
+func TestBuildTagTotals(t *testing.T) {
+	tl := taggedLedger(t)
+	identity := func(value Money, _ time.Time) Money { return value }
+	totals := BuildTagTotals(tl.Transactions, identity, "BRL")
+	if len(totals) != 2 {
+		t.Fatalf("totals = %+v, want trabalho and viagem", totals)
+	}
+
+	work, travel := totals[0], totals[1]
+	if work.Tag != "trabalho" || work.Count != 2 || work.Income.Amount != 30000 || work.Expense.Amount != 20000 || work.Balance().Amount != 10000 {
+		t.Errorf("trabalho = %+v", work)
+	}
+	// The transfer tagged viagem is not counted
+	if travel.Tag != "viagem" || travel.Count != 2 || travel.Income.Amount != 0 || travel.Expense.Amount != 70000 {
+		t.Errorf("viagem = %+v", travel)
+	}
+}
@@ -1,30 +1,74 @@
 package models
 
 import (
+	"errors"
+	"sort"
+	"sync"
 	"time"
 )
 
+// ErrTransactionNotFound is returned when no transaction has the requested ID
+var ErrTransactionNotFound = errors.New("transaction not found")
+
 // Transaction represents a financial transaction
 type Transaction struct {
-	ID          int       `json:"id"`
-	Type        string    `json:"type"`
-	Value       float64   `json:"value"`
-	Description string    `json:"description"`
-	Category    string    `json:"category"`
-	Date        time.Time `json:"date"`
+	ID           int             `json:"id"`
+	Type         TransactionType `json:"type"`
+	Value        Money           `json:"value"`
+	Description  string          `json:"description"`
+	Category     string          `json:"category"`
+	Date         time.Time       `json:"date"`
+	AccountID    int             `json:"account_id"`
+	LinkedID     int             `json:"linked_id,omitempty"`
+	RecurrenceID int             `json:"recurrence_id,omitempty"`
+	PurchaseID   int             `json:"purchase_id,omitempty"`
+	Installment  int             `json:"installment,omitempty"`
+	Status       ReconcileStatus `json:"status,omitempty"`
+	ImportID     string          `json:"import_id,omitempty"`
+	Tags         []string        `json:"tags,omitempty"`
+	Splits       []Split         `json:"splits,omitempty"`
+	Attachments  []Attachment    `json:"attachments,omitempty"`
+}
+
+// Normalize fixes the type and the sign of the value so that income is positive
+// and expense is negative. Unknown types are inferred from the sign. It reports whether anything changed.
+func (t *Transaction) Normalize() bool {
+	original := *t
+
+	if !t.Type.IsValid() {
+		if parsed, err := ParseTransactionType(string(t.Type)); err == nil {
+			t.Type = parsed
+		} else {
+			t.Type = TransactionTypeForValue(t.Value)
+		}
+	}
+	t.Value = t.Type.SignedValue(t.Value)
+
+	return t.Type != original.Type || t.Value != original.Value
 }
 
 // TransactionList holds a collection of transactions
 type TransactionList struct {
-	Transactions []Transaction `json:"transactions"`
+	Transactions     []Transaction         `json:"transactions"`
+	Accounts         []Account             `json:"accounts"`
+	BaseCurrency     string                `json:"base_currency"`
+	ExchangeRates    []ExchangeRate        `json:"exchange_rates"`
+	RecurrenceRules  []RecurrenceRule      `json:"recurrence_rules"`
+	Budgets          []Budget              `json:"budgets"`
+	Goals            []Goal                `json:"goals,omitempty"`
+	Loans            []Loan                `json:"loans,omitempty"`
+	Purchases        []InstallmentPurchase `json:"purchases,omitempty"`
+	Rules            []CategoryRule        `json:"rules,omitempty"`
+	FiscalMonthStart int                   `json:"fiscal_month_start,omitempty"`
+	NextID           int                   `json:"next_id"`
 }
 
 // NewTransaction creates a new transaction
-func NewTransaction(transactionType string, value float64, description, category string) Transaction {
+func NewTransaction(transactionType TransactionType, value Money, description, category string) Transaction {
 	return Transaction{
 		ID:          generateID(),
 		Type:        transactionType,
-		Value:       value,
+		Value:       transactionType.SignedValue(value),
 		Description: description,
 		Category:    category,
 		Date:        time.Now(),
@@ -32,20 +76,59 @@ func NewTransaction(transactionType string, value float64, description, category
 }
 
 // NewTransactionWithDate creates a new transaction with a specific date
-func NewTransactionWithDate(transactionType string, value float64, description, category string, date time.Time) Transaction {
+func NewTransactionWithDate(transactionType TransactionType, value Money, description, category string, date time.Time) Transaction {
 	return Transaction{
 		ID:          generateID(),
 		Type:        transactionType,
-		Value:       value,
+		Value:       transactionType.SignedValue(value),
 		Description: description,
 		Category:    category,
 		Date:        date,
 	}
 }
 
-// AddTransaction adds a transaction to the list
-func (tl *TransactionList) AddTransaction(transaction Transaction) {
+// AddTransaction adds a transaction to the list, assigning a fresh ID if its ID is
+// missing or already in use and the default account if it has none. A transaction
+// whose splits do not add up to its value is rejected.
+func (tl *TransactionList) AddTransaction(transaction Transaction) error {
+	transaction.Normalize()
+	transaction.Category = NormalizeCategory(transaction.Category)
+	transaction.Tags = NormalizeTags(transaction.Tags)
+	transaction.Splits = normalizeSplits(transaction.Type, transaction.Splits)
+	if err := transaction.ValidateSplits(); err != nil {
+		return err
+	}
+	if transaction.ID <= 0 || (transaction.ID < tl.NextID && tl.hasID(transaction.ID)) {
+		transaction.ID = generateID()
+	}
+	if transaction.AccountID == 0 {
+		transaction.AccountID = tl.DefaultAccountID()
+	}
+	tl.reserveID(transaction.ID)
 	tl.Transactions = append(tl.Transactions, transaction)
+	return nil
+}
+
+// EnsureUniqueIDs renumbers transactions with a missing or duplicated ID and
+// seeds the ID generator past every ID in use. It returns how many were renumbered.
+func (tl *TransactionList) EnsureUniqueIDs() int {
+	for _, tx := range tl.Transactions {
+		tl.reserveID(tx.ID)
+	}
+	seedID(tl.NextID)
+
+	repaired := 0
+	seen := make(map[int]bool, len(tl.Transactions))
+	for i := range tl.Transactions {
+		tx := &tl.Transactions[i]
+		if tx.ID <= 0 || seen[tx.ID] {
+			tx.ID = generateID()
+			tl.reserveID(tx.ID)
+			repaired++
+		}
+		seen[tx.ID] = true
+	}
+	return repaired
 }
 
 // GetTransactions returns all transactions
@@ -53,55 +136,155 @@ func (tl *TransactionList) GetTransactions() []Transaction {
 	return tl.Transactions
 }
 
-// GetBalance calculates the total balance from all transactions
-func (tl *TransactionList) GetBalance() float64 {
-	var total float64
-	for _, tx := range tl.Transactions {
-		total += tx.Value
+// NormalizeSigns applies the canonical sign convention to every transaction.
+// It returns how many transactions were changed.
+func (tl *TransactionList) NormalizeSigns() int {
+	changed := 0
+	for i := range tl.Transactions {
+		if tl.Transactions[i].Normalize() {
+			changed++
+		}
 	}
-	return total
+	return changed
 }
 
-// GetTransactionsByDateRange returns transactions within a date range
-func (tl *TransactionList) GetTransactionsByDateRange(start, end time.Time) []Transaction {
-	var filtered []Transaction
-	for _, tx := range tl.Transactions {
-		if (tx.Date.Equal(start) || tx.Date.After(start)) && (tx.Date.Equal(end) || tx.Date.Before(end)) {
-			filtered = append(filtered, tx)
+// GetTransactionByID returns the transaction with the given ID
+func (tl *TransactionList) GetTransactionByID(id int) (Transaction, error) {
+	index := tl.indexOf(id)
+	if index < 0 {
+		return Transaction{}, ErrTransactionNotFound
+	}
+	return tl.Transactions[index], nil
+}
+
+// UpdateTransaction replaces the stored transaction that has the same ID
+func (tl *TransactionList) UpdateTransaction(transaction Transaction) error {
+	index := tl.indexOf(transaction.ID)
+	if index < 0 {
+		return ErrTransactionNotFound
+	}
+	if err := tl.checkLocked(tl.Transactions[index], transaction); err != nil {
+		return err
+	}
+	// The status only changes through clearing and reconciliation
+	transaction.Status = tl.Transactions[index].Status
+	transaction.Normalize()
+	transaction.Category = NormalizeCategory(transaction.Category)
+	transaction.Tags = NormalizeTags(transaction.Tags)
+	transaction.Splits = normalizeSplits(transaction.Type, transaction.Splits)
+	if err := transaction.ValidateSplits(); err != nil {
+		return err
+	}
+	// Purchase totals add up the installments, so they all share the purchase's currency
+	if purchase := tl.purchase(transaction.PurchaseID); purchase != nil && transaction.Value.Currency != purchase.Total.Currency {
+		return ErrPurchaseCurrency
+	}
+	if linked := tl.indexOf(transaction.LinkedID); transaction.LinkedID != 0 && linked >= 0 {
+		if tl.Transactions[linked].AccountID == transaction.AccountID {
+			return ErrSameAccountTransfer
 		}
 	}
-	return filtered
+	tl.Transactions[index] = transaction
+
+	// Keep both legs of a transfer in sync
+	if transaction.LinkedID != 0 {
+		if linked := tl.indexOf(transaction.LinkedID); linked >= 0 {
+			tl.Transactions[linked].Value = transaction.Value.Neg()
+			tl.Transactions[linked].Description = transaction.Description
+			tl.Transactions[linked].Date = transaction.Date
+		}
+	}
+	return nil
 }
 
-// GetTransactionsByCategory returns transactions filtered by category
-func (tl *TransactionList) GetTransactionsByCategory(category string) []Transaction {
-	var filtered []Transaction
-	for _, tx := range tl.Transactions {
-		if tx.Category == category {
-			filtered = append(filtered, tx)
+// DeleteTransaction removes the transaction with the given ID, along with the other leg of a transfer
+func (tl *TransactionList) DeleteTransaction(id int) error {
+	index := tl.indexOf(id)
+	if index < 0 {
+		return ErrTransactionNotFound
+	}
+	linkedID := tl.Transactions[index].LinkedID
+	if tl.Transactions[index].IsLocked() {
+		return ErrTransactionLocked
+	}
+	if linked := tl.indexOf(linkedID); linkedID != 0 && linked >= 0 && tl.Transactions[linked].IsLocked() {
+		return ErrTransactionLocked
+	}
+	tl.Transactions = append(tl.Transactions[:index], tl.Transactions[index+1:]...)
+
+	if linkedID != 0 {
+		if linked := tl.indexOf(linkedID); linked >= 0 {
+			tl.Transactions = append(tl.Transactions[:linked], tl.Transactions[linked+1:]...)
 		}
 	}
-	return filtered
+	return nil
 }
 
-// GetCategories returns all unique categories
+// GetBalance calculates the total balance from all transactions in the base currency
+func (tl *TransactionList) GetBalance() Money {
+	total := NewMoney(0, tl.GetBaseCurrency())
+	for _, tx := range tl.Transactions {
+		total = total.Add(tl.BaseValue(tx))
+	}
+	return total
+}
+
+// GetCategories returns all unique categories used by transactions and splits, sorted
 func (tl *TransactionList) GetCategories() []string {
 	categories := make(map[string]bool)
 	for _, tx := range tl.Transactions {
-		categories[tx.Category] = true
+		for _, part := range tx.Parts() {
+			categories[part.Category] = true
+		}
 	}
 
 	var result []string
 	for category := range categories {
 		result = append(result, category)
 	}
+	sort.Strings(result)
 	return result
 }
 
-var nextID = 1
+func (tl *TransactionList) indexOf(id int) int {
+	for i, tx := range tl.Transactions {
+		if tx.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (tl *TransactionList) hasID(id int) bool {
+	return tl.indexOf(id) >= 0
+}
+
+// reserveID keeps NextID and the ID generator ahead of the given ID
+func (tl *TransactionList) reserveID(id int) {
+	if id >= tl.NextID {
+		tl.NextID = id + 1
+	}
+	seedID(tl.NextID)
+}
+
+var (
+	idMutex sync.Mutex
+	nextID  = 1
+)
 
 func generateID() int {
+	idMutex.Lock()
+	defer idMutex.Unlock()
 	id := nextID
 	nextID++
 	return id
 }
+
+// seedID makes sure the generator never hands out an ID below next
+func seedID(next int) {
+	idMutex.Lock()
+	defer idMutex.Unlock()
+	if next > nextID {
+		nextID = next
+	}
+}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEnsureUniqueIDs(t *testing.T) {
+	tl := &TransactionList{Transactions: []Transaction{
+		{ID: 5, Description: "a"},
+		{ID: 5, Description: "b"},
+		{ID: 0, Description: "c"},
+		{ID: 9, Description: "d"},
+		{ID: -1, Description: "e"},
+	}}
+	if repaired := tl.EnsureUniqueIDs(); repaired != 3 {
+		t.Errorf("EnsureUniqueIDs repaired %d transactions, want 3", repaired)
+	}
+
+	seen := make(map[int]bool)
+	for _, tx := range tl.Transactions {
+		if tx.ID <= 0 || seen[tx.ID] {
+			t.Errorf("transaction %q has ID %d after the repair", tx.Description, tx.ID)
+		}
+		seen[tx.ID] = true
+	}
+	// The first holder of an ID and valid IDs keep them; the others are numbered past the highest
+	if tl.Transactions[0].ID != 5 || tl.Transactions[3].ID != 9 || tl.Transactions[1].ID <= 9 {
+		t.Errorf("IDs after the repair = %d, %d, %d", tl.Transactions[0].ID, tl.Transactions[1].ID, tl.Transactions[3].ID)
+	}
+	for _, tx := range tl.Transactions {
+		if tx.ID >= tl.NextID {
+			t.Errorf("NextID %d is not past ID %d", tl.NextID, tx.ID)
+		}
+	}
+	if again := tl.EnsureUniqueIDs(); again != 0 {
+		t.Errorf("running EnsureUniqueIDs again repaired %d transactions", again)
+	}
+}
+
+func TestIDsSurviveARestart(t *testing.T) {
+	// A ledger saved with a high NextID must never hand out an ID below it, even if
+	// the transactions holding the highest IDs were deleted before saving
+	tl := &TransactionList{NextID: 1000000, Transactions: []Transaction{{ID: 3}}}
+	tl.EnsureUniqueIDs()
+	tx := NewTransactionWithDate(TransactionTypeIncome, NewMoney(100, "BRL"), "Pix", "", date(2026, 1, 1))
+	if tx.ID < 1000000 {
+		t.Errorf("new transaction got ID %d, below the saved NextID", tx.ID)
+	}
+}
+
+func TestAddTransactionKeepsIDsUnique(t *testing.T) {
+	tl := &TransactionList{}
+	tl.EnsureAccounts()
+	first := NewTransactionWithDate(TransactionTypeIncome, NewMoney(100, "BRL"), "Pix", "", date(2026, 1, 1))
+	if err := tl.AddTransaction(first); err != nil {
+		t.Fatal(err)
+	}
+	// Adding a copy, as an import of the same row would, must not reuse the ID
+	if err := tl.AddTransaction(first); err != nil {
+		t.Fatal(err)
+	}
+	missing := first
+	missing.ID = 0
+	if err := tl.AddTransaction(missing); err != nil {
+		t.Fatal(err)
+	}
+
+	if tl.Transactions[0].ID != first.ID {
+		t.Errorf("the first transaction got ID %d, want its own %d", tl.Transactions[0].ID, first.ID)
+	}
+	if a, b, c := tl.Transactions[0].ID, tl.Transactions[1].ID, tl.Transactions[2].ID; a == b || b == c || a == c || c <= 0 {
+		t.Errorf("IDs = %d, %d, %d; want three distinct positive IDs", a, b, c)
+	}
+	if tl.NextID <= tl.Transactions[2].ID {
+		t.Errorf("NextID = %d, not past the IDs in use", tl.NextID)
+	}
+}
+
+func TestUpdateTransaction(t *testing.T) {
+	tl := &TransactionList{}
+	tl.EnsureAccounts()
+	if err := tl.AddTransaction(NewTransactionWithDate(TransactionTypeExpense, NewMoney(5000, "BRL"), "Mercado", "Alimentação", date(2026, 1, 10))); err != nil {
+		t.Fatal(err)
+	}
+	tx := tl.Transactions[0]
+
+	tx.Type = TransactionTypeIncome
+	tx.Value = NewMoney(-7000, "BRL")
+	tx.Description = "Reembolso"
+	tx.Category = "Casa>Reparos"
+	if err := tl.UpdateTransaction(tx); err != nil {
+		t.Fatal(err)
+	}
+	got, err := tl.GetTransactionByID(tx.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Value.Amount != 7000 || got.Description != "Reembolso" || got.Category != "Casa > Reparos" || len(tl.Transactions) != 1 {
+		t.Errorf("updated transaction = %+v", got)
+	}
+
+	missing := tx
+	missing.ID = tx.ID + 100
+	if err := tl.UpdateTransaction(missing); !errors.Is(err, ErrTransactionNotFound) {
+		t.Errorf("updating a missing transaction error = %v, want ErrTransactionNotFound", err)
+	}
+	if _, err := tl.GetTransactionByID(missing.ID); !errors.Is(err, ErrTransactionNotFound) {
+		t.Errorf("GetTransactionByID of a missing transaction error = %v, want ErrTransactionNotFound", err)
+	}
+}
+
+func TestDeleteTransaction(t *testing.T) {
+	tl := &TransactionList{}
+	tl.EnsureAccounts()
+	for _, description := range []string{"Aluguel", "Luz", "Água"} {
+		if err := tl.AddTransaction(NewTransactionWithDate(TransactionTypeExpense, NewMoney(1000, "BRL"), description, "Casa", date(2026, 1, 5))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deleted := tl.Transactions[1].ID
+
+	if err := tl.DeleteTransaction(deleted); err != nil {
+		t.Fatal(err)
+	}
+	if len(tl.Transactions) != 2 || tl.Transactions[0].Description != "Aluguel" || tl.Transactions[1].Description != "Água" {
+		t.Errorf("transactions after the delete = %+v", tl.Transactions)
+	}
+	if got := tl.GetBalance(); got.Amount != -2000 {
+		t.Errorf("balance after the delete = %v, want -20.00", got)
+	}
+	if err := tl.DeleteTransaction(deleted); !errors.Is(err, ErrTransactionNotFound) {
+		t.Errorf("deleting twice error = %v, want ErrTransactionNotFound", err)
+	}
+}
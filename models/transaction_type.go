@@ -0,0 +1,72 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TransactionType classifies a transaction as income, expense or transfer
+type TransactionType string
+
+const (
+	// TransactionTypeIncome is money coming in; its value is always stored positive
+	TransactionTypeIncome TransactionType = "Receita"
+	// TransactionTypeExpense is money going out; its value is always stored negative
+	TransactionTypeExpense TransactionType = "Despesa"
+	// TransactionTypeTransfer moves money between accounts; the outgoing leg is negative and the incoming leg positive
+	TransactionTypeTransfer TransactionType = "Transferência"
+)
+
+// TransactionTypes returns the transaction types selectable in the entry form, in display order
+func TransactionTypes() []TransactionType {
+	return []TransactionType{TransactionTypeIncome, TransactionTypeExpense}
+}
+
+// TransactionTypeNames returns the transaction types as strings, for select widgets
+func TransactionTypeNames() []string {
+	var names []string
+	for _, t := range TransactionTypes() {
+		names = append(names, string(t))
+	}
+	return names
+}
+
+// ParseTransactionType parses a transaction type name, case-insensitively
+func ParseTransactionType(s string) (TransactionType, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "receita", "income":
+		return TransactionTypeIncome, nil
+	case "despesa", "expense":
+		return TransactionTypeExpense, nil
+	case "transferência", "transferencia", "transfer":
+		return TransactionTypeTransfer, nil
+	default:
+		return "", fmt.Errorf("invalid transaction type %q", s)
+	}
+}
+
+// TransactionTypeForValue returns the type implied by the sign of a value
+func TransactionTypeForValue(value Money) TransactionType {
+	if value.IsNegative() {
+		return TransactionTypeExpense
+	}
+	return TransactionTypeIncome
+}
+
+// IsValid reports whether t is a known transaction type
+func (t TransactionType) IsValid() bool {
+	return t == TransactionTypeIncome || t == TransactionTypeExpense || t == TransactionTypeTransfer
+}
+
+// SignedValue applies the canonical sign convention: income positive, expense
+// negative. Transfer legs keep their sign, which tells the direction.
+func (t TransactionType) SignedValue(value Money) Money {
+	switch t {
+	case TransactionTypeExpense:
+		return value.Abs().Neg()
+	case TransactionTypeTransfer:
+		return value
+	default:
+		return value.Abs()
+	}
+}
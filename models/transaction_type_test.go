@@ -0,0 +1,84 @@
+package models
+
+import "testing"
+
+func TestParseTransactionType(t *testing.T) {
+	tests := []struct {
+		in   string
+		want TransactionType
+	}{
+		{"Receita", TransactionTypeIncome},
+		{" income ", TransactionTypeIncome},
+		{"DESPESA", TransactionTypeExpense},
+		{"expense", TransactionTypeExpense},
+		{"Transferência", TransactionTypeTransfer},
+		{"transferencia", TransactionTypeTransfer},
+	}
+	for _, tt := range tests {
+		if got, err := ParseTransactionType(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseTransactionType(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	for _, invalid := range []string{"", "Investimento"} {
+		if _, err := ParseTransactionType(invalid); err == nil {
+			t.Errorf("ParseTransactionType(%q) accepted an unknown type", invalid)
+		}
+	}
+}
+
+func TestSignedValue(t *testing.T) {
+	tests := []struct {
+		typ    TransactionType
+		amount int64
+		want   int64
+	}{
+		{TransactionTypeIncome, 1500, 1500},
+		{TransactionTypeIncome, -1500, 1500},
+		{TransactionTypeExpense, 1500, -1500},
+		{TransactionTypeExpense, -1500, -1500},
+		{TransactionTypeTransfer, -1500, -1500},
+		{TransactionTypeTransfer, 1500, 1500},
+	}
+	for _, tt := range tests {
+		if got := tt.typ.SignedValue(NewMoney(tt.amount, "BRL")); got.Amount != tt.want {
+			t.Errorf("%s.SignedValue(%d) = %d, want %d", tt.typ, tt.amount, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestNormalizeSigns(t *testing.T) {
+	tl := &TransactionList{Transactions: []Transaction{
+		{ID: 1, Type: TransactionTypeIncome, Value: NewMoney(1000, "BRL")},
+		{ID: 2, Type: TransactionTypeExpense, Value: NewMoney(2000, "BRL")},
+		{ID: 3, Type: "despesa", Value: NewMoney(-3000, "BRL")},
+		{ID: 4, Value: NewMoney(-4000, "BRL")},
+		{ID: 5, Type: "Outro", Value: NewMoney(5000, "BRL")},
+		{ID: 6, Type: TransactionTypeTransfer, Value: NewMoney(-6000, "BRL")},
+	}}
+	// Older ledgers stored expenses as positive values or with free-form types
+	want := []struct {
+		typ    TransactionType
+		amount int64
+	}{
+		{TransactionTypeIncome, 1000},
+		{TransactionTypeExpense, -2000},
+		{TransactionTypeExpense, -3000},
+		{TransactionTypeExpense, -4000},
+		{TransactionTypeIncome, 5000},
+		{TransactionTypeTransfer, -6000},
+	}
+	if changed := tl.NormalizeSigns(); changed != 4 {
+		t.Errorf("NormalizeSigns changed %d transactions, want 4", changed)
+	}
+	for i, tx := range tl.Transactions {
+		if tx.Type != want[i].typ || tx.Value.Amount != want[i].amount {
+			t.Errorf("transaction %d = %q %d, want %q %d", tx.ID, tx.Type, tx.Value.Amount, want[i].typ, want[i].amount)
+		}
+	}
+	if changed := tl.NormalizeSigns(); changed != 0 {
+		t.Errorf("running NormalizeSigns again changed %d transactions", changed)
+	}
+	if got := tl.GetBalance(); got.Amount != 1000-2000-3000-4000+5000-6000 {
+		t.Errorf("balance = %v", got)
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"finance_go/models"
+)
+
+// ErrLedgerNotLoaded is returned when unused attachment files are removed before a ledger was loaded
+var ErrLedgerNotLoaded = errors.New("ledger not loaded, keeping attachment files")
+
+// AttachmentService copies receipts and documents into a managed folder and links them to transactions
+type AttachmentService struct {
+	financeService *FinanceService
+	dir            string
+}
+
+// NewAttachmentService creates a new attachment service storing files in dir
+func NewAttachmentService(financeService *FinanceService, dir string) *AttachmentService {
+	return &AttachmentService{
+		financeService: financeService,
+		dir:            dir,
+	}
+}
+
+// Attach copies a file into the attachments folder and links it to a transaction.
+// Files are named after their content, so attaching the same document twice stores it once.
+func (as *AttachmentService) Attach(id int, sourcePath string) (models.Attachment, error) {
+	if _, err := as.financeService.GetTransactionByID(id); err != nil {
+		return models.Attachment{}, fmt.Errorf("error attaching file: %w", err)
+	}
+
+	file, err := as.copyFile(sourcePath)
+	if err != nil {
+		return models.Attachment{}, fmt.Errorf("error copying attachment: %w", err)
+	}
+
+	attachment := models.Attachment{
+		Name:    filepath.Base(sourcePath),
+		File:    file,
+		AddedAt: time.Now(),
+	}
+	if err := as.financeService.GetTransactionList().AddAttachment(id, attachment); err != nil {
+		return models.Attachment{}, fmt.Errorf("error attaching file: %w", err)
+	}
+	return attachment, nil
+}
+
+// Remove unlinks an attachment from a transaction. The file is kept until RemoveUnusedFiles runs
+// after the ledger is saved, since the saved ledger may still reference it.
+func (as *AttachmentService) Remove(id int, file string) error {
+	if err := as.financeService.GetTransactionList().RemoveAttachment(id, file); err != nil {
+		return fmt.Errorf("error removing attachment: %w", err)
+	}
+	return nil
+}
+
+// DeleteTransaction deletes a transaction. Its attachment files are kept until RemoveUnusedFiles
+// runs after the ledger is saved.
+func (as *AttachmentService) DeleteTransaction(id int) error {
+	return as.financeService.DeleteTransaction(id)
+}
+
+// GetAttachments returns the attachments of a transaction
+func (as *AttachmentService) GetAttachments(id int) ([]models.Attachment, error) {
+	tx, err := as.financeService.GetTransactionByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting attachments: %w", err)
+	}
+	return tx.Attachments, nil
+}
+
+// Path returns the absolute path of the managed copy of an attachment
+func (as *AttachmentService) Path(attachment models.Attachment) (string, error) {
+	path, err := filepath.Abs(filepath.Join(as.dir, attachment.File))
+	if err != nil {
+		return "", fmt.Errorf("error resolving attachment path: %w", err)
+	}
+	return path, nil
+}
+
+// RemoveUnusedFiles deletes files in the attachments folder that no transaction references. It must
+// only run when the ledger in memory matches the saved one: after loading it or after saving it.
+// It refuses to run before a ledger was loaded, when every file would look unused.
+func (as *AttachmentService) RemoveUnusedFiles() (int, error) {
+	if !as.financeService.loaded {
+		return 0, ErrLedgerNotLoaded
+	}
+	entries, err := os.ReadDir(as.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("error reading attachments folder: %w", err)
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() || as.financeService.GetTransactionList().IsAttachmentInUse(entry.Name()) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(as.dir, entry.Name())); err != nil {
+			return removed, fmt.Errorf("error removing attachment: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// copyFile stores a copy of the source file named after its SHA-256 hash and returns that name
+func (as *AttachmentService) copyFile(sourcePath string) (string, error) {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return "", err
+	}
+	defer source.Close()
+
+	if err := os.MkdirAll(as.dir, 0755); err != nil {
+		return "", err
+	}
+
+	temp, err := os.CreateTemp(as.dir, ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(temp.Name())
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(temp, hash), source); err != nil {
+		temp.Close()
+		return "", err
+	}
+	if err := temp.Close(); err != nil {
+		return "", err
+	}
+
+	file := hex.EncodeToString(hash.Sum(nil)) + strings.ToLower(filepath.Ext(sourcePath))
+	target := filepath.Join(as.dir, file)
+	if _, err := os.Stat(target); err == nil {
+		return file, nil
+	}
+	if err := os.Rename(temp.Name(), target); err != nil {
+		return "", err
+	}
+	return file, nil
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"finance_go/models"
+)
+
+// newLoadedAttachmentService returns an attachment service over a loaded, empty ledger and a temporary folder
+func newLoadedAttachmentService(t *testing.T) (*FinanceService, *AttachmentService) {
+	t.Helper()
+	fs := NewFinanceService()
+	fs.SetTransactionList(&models.TransactionList{})
+	return fs, NewAttachmentService(fs, filepath.Join(t.TempDir(), "attachments"))
+}
+
+// attachNewFile adds an expense and attaches a new file with the given content to it
+func attachNewFile(t *testing.T, fs *FinanceService, as *AttachmentService, content string) (models.Transaction, models.Attachment) {
+	t.Helper()
+	fs.AddTransactionFromModel(models.NewTransaction(models.TransactionTypeExpense, models.NewMoney(1000, "BRL"), "Mercado", ""))
+	tx := fs.GetTransactionList().Transactions[len(fs.GetTransactionList().Transactions)-1]
+
+	source := filepath.Join(t.TempDir(), "recibo.pdf")
+	if err := os.WriteFile(source, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	attachment, err := as.Attach(tx.ID, source)
+	if err != nil {
+		t.Fatalf("Attach: %v", err)
+	}
+	return tx, attachment
+}
+
+func fileExists(t *testing.T, as *AttachmentService, attachment models.Attachment) bool {
+	t.Helper()
+	path, err := as.Path(attachment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+func TestRemoveUnusedFilesRefusesBeforeLoad(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "recibo.pdf")
+	if err := os.WriteFile(file, []byte("recibo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A ledger that failed to load leaves the service with its initial empty ledger
+	as := NewAttachmentService(NewFinanceService(), dir)
+	removed, err := as.RemoveUnusedFiles()
+	if !errors.Is(err, ErrLedgerNotLoaded) || removed != 0 {
+		t.Fatalf("RemoveUnusedFiles() = %d, %v; want 0, ErrLedgerNotLoaded", removed, err)
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Fatalf("file was removed: %v", err)
+	}
+}
+
+func TestDeleteTransactionKeepsFileUntilSweep(t *testing.T) {
+	fs, as := newLoadedAttachmentService(t)
+	tx, attachment := attachNewFile(t, fs, as, "recibo")
+
+	if err := as.DeleteTransaction(tx.ID); err != nil {
+		t.Fatalf("DeleteTransaction: %v", err)
+	}
+	if !fileExists(t, as, attachment) {
+		t.Fatal("file removed before the ledger was saved")
+	}
+
+	removed, err := as.RemoveUnusedFiles()
+	if err != nil || removed != 1 {
+		t.Fatalf("RemoveUnusedFiles() = %d, %v; want 1, nil", removed, err)
+	}
+	if fileExists(t, as, attachment) {
+		t.Fatal("unused file was kept by the sweep")
+	}
+}
+
+func TestRemoveKeepsFileUntilSweep(t *testing.T) {
+	fs, as := newLoadedAttachmentService(t)
+	tx, attachment := attachNewFile(t, fs, as, "recibo")
+	_, kept := attachNewFile(t, fs, as, "nota fiscal")
+
+	if err := as.Remove(tx.ID, attachment.File); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if !fileExists(t, as, attachment) {
+		t.Fatal("file removed before the ledger was saved")
+	}
+
+	removed, err := as.RemoveUnusedFiles()
+	if err != nil || removed != 1 {
+		t.Fatalf("RemoveUnusedFiles() = %d, %v; want 1, nil", removed, err)
+	}
+	if fileExists(t, as, attachment) || !fileExists(t, as, kept) {
+		t.Fatal("the sweep must remove only files no transaction references")
+	}
+}
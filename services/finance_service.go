@@ -1,34 +1,68 @@
 package services
 
-import "finance_go/models"
+import (
+	"fmt"
+	"time"
+
+	"finance_go/models"
+)
 
 // FinanceService handles business logic for financial operations
 type FinanceService struct {
 	transactionList *models.TransactionList
+	// loaded is set once a ledger from storage replaces the initial empty one
+	loaded bool
+	// classifier is trained on the first suggestion and dropped whenever transactions change
+	classifier *models.CategoryClassifier
 }
 
 // NewFinanceService creates a new finance service
 func NewFinanceService() *FinanceService {
+	transactionList := &models.TransactionList{
+		Transactions: make([]models.Transaction, 0),
+	}
+	transactionList.EnsureAccounts()
+
 	return &FinanceService{
-		transactionList: &models.TransactionList{
-			Transactions: make([]models.Transaction, 0),
-		},
+		transactionList: transactionList,
 	}
 }
 
 // AddTransaction adds a new transaction with business logic
-func (fs *FinanceService) AddTransaction(transactionType string, amount float64) {
-	if transactionType == "Despesa" {
-		amount = -amount
+func (fs *FinanceService) AddTransaction(transactionType models.TransactionType, amount models.Money) error {
+	return fs.AddTransactionFromModel(models.NewTransaction(transactionType, amount, "", ""))
+}
+
+// AddTransactionFromModel adds a transaction directly from a model (for imports)
+func (fs *FinanceService) AddTransactionFromModel(transaction models.Transaction) error {
+	defer fs.transactionsChanged()
+	if err := fs.transactionList.AddTransaction(transaction); err != nil {
+		return fmt.Errorf("error adding transaction: %w", err)
 	}
+	return nil
+}
 
-	transaction := models.NewTransaction(transactionType, amount, "", "")
-	fs.transactionList.AddTransaction(transaction)
+// GetTransactionByID returns a single transaction
+func (fs *FinanceService) GetTransactionByID(id int) (models.Transaction, error) {
+	return fs.transactionList.GetTransactionByID(id)
 }
 
-// AddTransactionFromModel adds a transaction directly from a model (for imports)
-func (fs *FinanceService) AddTransactionFromModel(transaction models.Transaction) {
-	fs.transactionList.AddTransaction(transaction)
+// UpdateTransaction saves changes to an existing transaction
+func (fs *FinanceService) UpdateTransaction(transaction models.Transaction) error {
+	defer fs.transactionsChanged()
+	if err := fs.transactionList.UpdateTransaction(transaction); err != nil {
+		return fmt.Errorf("error updating transaction %d: %w", transaction.ID, err)
+	}
+	return nil
+}
+
+// DeleteTransaction removes a transaction by ID
+func (fs *FinanceService) DeleteTransaction(id int) error {
+	defer fs.transactionsChanged()
+	if err := fs.transactionList.DeleteTransaction(id); err != nil {
+		return fmt.Errorf("error deleting transaction %d: %w", id, err)
+	}
+	return nil
 }
 
 // GetTransactions returns all transactions
@@ -36,11 +70,356 @@ func (fs *FinanceService) GetTransactions() []models.Transaction {
 	return fs.transactionList.GetTransactions()
 }
 
+// QueryTransactions returns the transactions matching a query
+func (fs *FinanceService) QueryTransactions(query models.Query) []models.Transaction {
+	return fs.transactionList.Query(query)
+}
+
+// GetFiscalMonthStart returns the payday on which fiscal months start
+func (fs *FinanceService) GetFiscalMonthStart() int {
+	return fs.transactionList.GetFiscalMonthStart()
+}
+
+// SetFiscalMonthStart sets the payday on which fiscal months start
+func (fs *FinanceService) SetFiscalMonthStart(day int) error {
+	if err := fs.transactionList.SetFiscalMonthStart(day); err != nil {
+		return fmt.Errorf("error setting fiscal month start: %w", err)
+	}
+	return nil
+}
+
+// PeriodContaining returns the period of a kind that contains date
+func (fs *FinanceService) PeriodContaining(kind models.PeriodKind, date time.Time) models.Period {
+	return fs.transactionList.PeriodContaining(kind, date)
+}
+
 // GetBalance returns the current balance
-func (fs *FinanceService) GetBalance() float64 {
+func (fs *FinanceService) GetBalance() models.Money {
 	return fs.transactionList.GetBalance()
 }
 
+// AddAccount creates a new account
+func (fs *FinanceService) AddAccount(name string, accountType models.AccountType) (models.Account, error) {
+	account, err := fs.transactionList.AddAccount(name, accountType)
+	if err != nil {
+		return models.Account{}, fmt.Errorf("error adding account: %w", err)
+	}
+	return account, nil
+}
+
+// AddCreditCard adds a credit card account with its statement closing and due days
+func (fs *FinanceService) AddCreditCard(name string, closingDay, dueDay int) (models.Account, error) {
+	account, err := fs.transactionList.AddCreditCard(name, closingDay, dueDay)
+	if err != nil {
+		return models.Account{}, fmt.Errorf("error adding credit card: %w", err)
+	}
+	return account, nil
+}
+
+// GetStatements returns the statements of a credit card
+func (fs *FinanceService) GetStatements(accountID int, now time.Time) ([]models.Statement, error) {
+	statements, err := fs.transactionList.GetStatements(accountID, now)
+	if err != nil {
+		return nil, fmt.Errorf("error getting statements: %w", err)
+	}
+	return statements, nil
+}
+
+// PayStatement records the payment of a credit card statement as a transfer from another account
+func (fs *FinanceService) PayStatement(cardID, fromAccountID int, statement models.Statement, amount models.Money, date time.Time) error {
+	if _, _, err := fs.transactionList.PayStatement(cardID, fromAccountID, statement, amount, date); err != nil {
+		return fmt.Errorf("error paying statement: %w", err)
+	}
+	return nil
+}
+
+// GetAccounts returns all accounts
+func (fs *FinanceService) GetAccounts() []models.Account {
+	return fs.transactionList.GetAccounts()
+}
+
+// GetAccountBalance returns the balance of a single account
+func (fs *FinanceService) GetAccountBalance(accountID int) models.Money {
+	return fs.transactionList.GetAccountBalance(accountID)
+}
+
+// GetAccountBalances returns the balance of every account, keyed by account ID
+func (fs *FinanceService) GetAccountBalances() map[int]models.Money {
+	balances := make(map[int]models.Money)
+	for _, account := range fs.transactionList.GetAccounts() {
+		balances[account.ID] = fs.transactionList.GetAccountBalance(account.ID)
+	}
+	return balances
+}
+
+// Transfer moves money between two accounts without counting as income or expense
+func (fs *FinanceService) Transfer(fromAccountID, toAccountID int, amount models.Money, description string, date time.Time) error {
+	if _, _, err := fs.transactionList.AddTransfer(fromAccountID, toAccountID, amount, description, date); err != nil {
+		return fmt.Errorf("error creating transfer: %w", err)
+	}
+	return nil
+}
+
+// GetBaseCurrency returns the currency balances and reports are shown in
+func (fs *FinanceService) GetBaseCurrency() string {
+	return fs.transactionList.GetBaseCurrency()
+}
+
+// SetBaseCurrency changes the currency balances and reports are shown in
+func (fs *FinanceService) SetBaseCurrency(currency string) {
+	fs.transactionList.SetBaseCurrency(currency)
+}
+
+// SetExchangeRate stores an exchange rate in the local rate table
+func (fs *FinanceService) SetExchangeRate(rate models.ExchangeRate) error {
+	if err := fs.transactionList.SetExchangeRate(rate); err != nil {
+		return fmt.Errorf("error setting exchange rate: %w", err)
+	}
+	return nil
+}
+
+// GetExchangeRates returns the local rate table
+func (fs *FinanceService) GetExchangeRates() []models.ExchangeRate {
+	return fs.transactionList.GetExchangeRates()
+}
+
+// ValueInBase returns a transaction value converted to the base currency at the transaction date,
+// or an error when no rate is available
+func (fs *FinanceService) ValueInBase(tx models.Transaction) (models.Money, error) {
+	return fs.transactionList.ValueInBase(tx)
+}
+
+// MissingRates returns the currencies left out of totals because they have no rate to the base currency
+func (fs *FinanceService) MissingRates(now time.Time) []models.MissingRate {
+	return fs.transactionList.MissingRates(now)
+}
+
+// SetBudget creates or replaces the budget of a category in a month
+func (fs *FinanceService) SetBudget(category string, year int, month time.Month, limit models.Money) (models.Budget, error) {
+	budget, err := fs.transactionList.SetBudget(category, year, month, limit)
+	if err != nil {
+		return models.Budget{}, fmt.Errorf("error setting budget: %w", err)
+	}
+	return budget, nil
+}
+
+// DeleteBudget removes a budget
+func (fs *FinanceService) DeleteBudget(id int) error {
+	if err := fs.transactionList.DeleteBudget(id); err != nil {
+		return fmt.Errorf("error deleting budget %d: %w", id, err)
+	}
+	return nil
+}
+
+// GetBudgetStatus returns budget vs. actual vs. remaining for every budget of a month
+func (fs *FinanceService) GetBudgetStatus(year int, month time.Month) []models.BudgetStatus {
+	return fs.transactionList.GetBudgetStatus(year, month)
+}
+
+// AddGoal stores a new savings goal
+func (fs *FinanceService) AddGoal(goal models.Goal) (models.Goal, error) {
+	goal, err := fs.transactionList.AddGoal(goal)
+	if err != nil {
+		return models.Goal{}, fmt.Errorf("error adding goal: %w", err)
+	}
+	return goal, nil
+}
+
+// DeleteGoal removes a savings goal
+func (fs *FinanceService) DeleteGoal(id int) error {
+	if err := fs.transactionList.DeleteGoal(id); err != nil {
+		return fmt.Errorf("error deleting goal %d: %w", id, err)
+	}
+	return nil
+}
+
+// GetGoalStatus returns the progress of every savings goal
+func (fs *FinanceService) GetGoalStatus(now time.Time) []models.GoalStatus {
+	return fs.transactionList.GetGoalStatus(now)
+}
+
+// AddRule stores a new categorization rule after the existing ones
+func (fs *FinanceService) AddRule(rule models.CategoryRule) (models.CategoryRule, error) {
+	rule, err := fs.transactionList.AddRule(rule)
+	if err != nil {
+		return models.CategoryRule{}, fmt.Errorf("error adding rule: %w", err)
+	}
+	return rule, nil
+}
+
+// DeleteRule removes a categorization rule
+func (fs *FinanceService) DeleteRule(id int) error {
+	if err := fs.transactionList.DeleteRule(id); err != nil {
+		return fmt.Errorf("error deleting rule %d: %w", id, err)
+	}
+	return nil
+}
+
+// MoveRule moves a categorization rule up or down in the order rules are tried
+func (fs *FinanceService) MoveRule(id int, up bool) error {
+	if err := fs.transactionList.MoveRule(id, up); err != nil {
+		return fmt.Errorf("error moving rule %d: %w", id, err)
+	}
+	return nil
+}
+
+// GetRules returns the categorization rules in the order they are tried
+func (fs *FinanceService) GetRules() []models.CategoryRule {
+	return fs.transactionList.GetRules()
+}
+
+// DescribeRule describes the conditions and the category of a rule
+func (fs *FinanceService) DescribeRule(rule models.CategoryRule) string {
+	return fs.transactionList.DescribeRule(rule)
+}
+
+// Categorize fills the category of an uncategorized transaction from the rules
+func (fs *FinanceService) Categorize(transaction models.Transaction) models.Transaction {
+	return fs.transactionList.Categorize(transaction)
+}
+
+// PreviewRules returns the category changes running the rules over the ledger would make
+func (fs *FinanceService) PreviewRules(overwrite bool) []models.RuleChange {
+	return fs.transactionList.PreviewRules(overwrite)
+}
+
+// ApplyRules runs the rules over the ledger and returns the changes made
+func (fs *FinanceService) ApplyRules(overwrite bool) []models.RuleChange {
+	defer fs.transactionsChanged()
+	return fs.transactionList.ApplyRules(overwrite)
+}
+
+// SuggestCategories returns up to limit categories for a description, learned from how the
+// ledger was categorized so far, most likely first
+func (fs *FinanceService) SuggestCategories(description string, transactionType models.TransactionType, limit int) []models.CategorySuggestion {
+	return fs.Classifier().Suggest(description, transactionType, limit)
+}
+
+// Classifier returns the category classifier trained on the current transactions. It is trained
+// once and kept until a transaction is added, changed or removed.
+func (fs *FinanceService) Classifier() *models.CategoryClassifier {
+	if fs.classifier == nil {
+		fs.classifier = fs.transactionList.TrainClassifier()
+	}
+	return fs.classifier
+}
+
+// transactionsChanged drops what was derived from the transactions so it is computed again
+func (fs *FinanceService) transactionsChanged() {
+	fs.classifier = nil
+}
+
+// AddLoan stores a new loan
+func (fs *FinanceService) AddLoan(loan models.Loan) (models.Loan, error) {
+	loan, err := fs.transactionList.AddLoan(loan)
+	if err != nil {
+		return models.Loan{}, fmt.Errorf("error adding loan: %w", err)
+	}
+	return loan, nil
+}
+
+// DeleteLoan removes a loan
+func (fs *FinanceService) DeleteLoan(id int) error {
+	if err := fs.transactionList.DeleteLoan(id); err != nil {
+		return fmt.Errorf("error deleting loan %d: %w", id, err)
+	}
+	return nil
+}
+
+// GetLoans returns all loans
+func (fs *FinanceService) GetLoans() []models.Loan {
+	return fs.transactionList.GetLoans()
+}
+
+// GetLoanStatus returns the schedule, outstanding balance and interest paid of a loan
+func (fs *FinanceService) GetLoanStatus(id int) (models.LoanStatus, error) {
+	status, err := fs.transactionList.GetLoanStatus(id)
+	if err != nil {
+		return models.LoanStatus{}, fmt.Errorf("error getting loan %d: %w", id, err)
+	}
+	return status, nil
+}
+
+// PayLoanInstallment records the payment of a loan installment as an expense
+func (fs *FinanceService) PayLoanInstallment(loanID, number int, date time.Time) (models.Transaction, error) {
+	defer fs.transactionsChanged()
+	tx, err := fs.transactionList.PayLoanInstallment(loanID, number, date)
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("error paying installment %d of loan %d: %w", number, loanID, err)
+	}
+	return tx, nil
+}
+
+// LinkLoanInstallment marks a loan installment as paid by an existing transaction
+func (fs *FinanceService) LinkLoanInstallment(loanID, number, transactionID int) error {
+	if err := fs.transactionList.LinkLoanInstallment(loanID, number, transactionID); err != nil {
+		return fmt.Errorf("error linking installment %d of loan %d: %w", number, loanID, err)
+	}
+	return nil
+}
+
+// AddInstallmentPurchase stores a purchase and generates its installment transactions
+func (fs *FinanceService) AddInstallmentPurchase(purchase models.InstallmentPurchase) (models.InstallmentPurchase, error) {
+	defer fs.transactionsChanged()
+	purchase, err := fs.transactionList.AddInstallmentPurchase(purchase)
+	if err != nil {
+		return models.InstallmentPurchase{}, fmt.Errorf("error adding installment purchase: %w", err)
+	}
+	return purchase, nil
+}
+
+// GetPurchaseStatus returns the paid and pending installments of every purchase
+func (fs *FinanceService) GetPurchaseStatus(now time.Time) []models.PurchaseStatus {
+	return fs.transactionList.GetPurchaseStatus(now)
+}
+
+// CancelInstallmentPurchase removes the pending installments of a purchase and returns how many
+// were removed and how many were kept because they are reconciled
+func (fs *FinanceService) CancelInstallmentPurchase(id int, now time.Time) (removed, locked int, err error) {
+	defer fs.transactionsChanged()
+	removed, locked, err = fs.transactionList.CancelInstallmentPurchase(id, now)
+	if err != nil {
+		return 0, locked, fmt.Errorf("error cancelling installment purchase %d: %w", id, err)
+	}
+	return removed, locked, nil
+}
+
+// PrepayInstallmentPurchase replaces the pending installments of a purchase with a single payment
+func (fs *FinanceService) PrepayInstallmentPurchase(id int, date time.Time, amount models.Money) (models.Transaction, error) {
+	defer fs.transactionsChanged()
+	tx, err := fs.transactionList.PrepayInstallmentPurchase(id, date, amount)
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("error prepaying installment purchase %d: %w", id, err)
+	}
+	return tx, nil
+}
+
+// SetCleared ticks a transaction off as seen on the bank statement, or unticks it
+func (fs *FinanceService) SetCleared(id int, cleared bool) error {
+	if err := fs.transactionList.SetCleared(id, cleared); err != nil {
+		return fmt.Errorf("error clearing transaction %d: %w", id, err)
+	}
+	return nil
+}
+
+// GetReconciliation returns the state of an account checked against a bank statement
+func (fs *FinanceService) GetReconciliation(accountID int, statementDate time.Time, endingBalance models.Money) (models.Reconciliation, error) {
+	reconciliation, err := fs.transactionList.GetReconciliation(accountID, statementDate, endingBalance)
+	if err != nil {
+		return models.Reconciliation{}, fmt.Errorf("error reconciling account %d: %w", accountID, err)
+	}
+	return reconciliation, nil
+}
+
+// FinishReconciliation locks the cleared transactions once they match the bank statement
+func (fs *FinanceService) FinishReconciliation(accountID int, statementDate time.Time, endingBalance models.Money) (int, error) {
+	reconciled, err := fs.transactionList.FinishReconciliation(accountID, statementDate, endingBalance)
+	if err != nil {
+		return 0, fmt.Errorf("error finishing reconciliation of account %d: %w", accountID, err)
+	}
+	return reconciled, nil
+}
+
 // GetTransactionList returns the transaction list for storage operations
 func (fs *FinanceService) GetTransactionList() *models.TransactionList {
 	return fs.transactionList
@@ -48,5 +427,10 @@ func (fs *FinanceService) GetTransactionList() *models.TransactionList {
 
 // SetTransactionList sets the transaction list (for loading from storage)
 func (fs *FinanceService) SetTransactionList(tl *models.TransactionList) {
+	tl.EnsureUniqueIDs()
+	tl.NormalizeSigns()
+	tl.EnsureAccounts()
 	fs.transactionList = tl
+	fs.loaded = true
+	fs.transactionsChanged()
 }
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+
+	"finance_go/models"
+)
+
+func TestClassifierIsKeptUntilTransactionsChange(t *testing.T) {
+	fs := NewFinanceService()
+	fs.SetTransactionList(&models.TransactionList{})
+	fs.AddTransactionFromModel(models.NewTransaction(models.TransactionTypeExpense, models.NewMoney(1000, "BRL"), "Padaria", "Mercado"))
+
+	classifier := fs.Classifier()
+	if fs.Classifier() != classifier {
+		t.Fatal("the classifier was trained again without any change")
+	}
+	if best := fs.SuggestCategories("padaria", models.TransactionTypeExpense, 1); len(best) != 1 || best[0].Category != "Mercado" {
+		t.Fatalf("SuggestCategories(padaria) = %+v, want Mercado", best)
+	}
+
+	fs.AddTransactionFromModel(models.NewTransaction(models.TransactionTypeExpense, models.NewMoney(5000, "BRL"), "Farmácia", "Saúde"))
+	if fs.Classifier() == classifier {
+		t.Fatal("the classifier was kept after a transaction was added")
+	}
+	if best := fs.SuggestCategories("farmacia", models.TransactionTypeExpense, 1); len(best) != 1 || best[0].Category != "Saúde" {
+		t.Errorf("SuggestCategories(farmacia) = %+v, want Saúde", best)
+	}
+
+	tx := fs.GetTransactions()[1]
+	tx.Category = "Farmácia"
+	if err := fs.UpdateTransaction(tx); err != nil {
+		t.Fatal(err)
+	}
+	if best := fs.SuggestCategories("farmacia", models.TransactionTypeExpense, 1); len(best) != 1 || best[0].Category != "Farmácia" {
+		t.Errorf("after recategorizing, SuggestCategories(farmacia) = %+v, want Farmácia", best)
+	}
+
+	classifier = fs.Classifier()
+	if err := fs.DeleteTransaction(tx.ID); err != nil {
+		t.Fatal(err)
+	}
+	if fs.Classifier() == classifier {
+		t.Error("the classifier was kept after a transaction was deleted")
+	}
+}
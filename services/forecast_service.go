@@ -0,0 +1,165 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"finance_go/models"
+)
+
+// DefaultForecastHistoryMonths is how many past months the variable spending estimate averages
+const DefaultForecastHistoryMonths = 3
+
+// ForecastService projects the balance into the future from scheduled items and past spending
+type ForecastService struct {
+	financeService *FinanceService
+}
+
+// NewForecastService creates a new forecast service
+func NewForecastService(financeService *FinanceService) *ForecastService {
+	return &ForecastService{
+		financeService: financeService,
+	}
+}
+
+// ForecastItem is a known transaction expected on a forecast day
+type ForecastItem struct {
+	Description string
+	Value       models.Money
+	// Recurring is true for occurrences of recurrence rules not generated yet and unpaid loan
+	// installments, false for transactions already entered with a future date
+	Recurring bool
+}
+
+// ForecastDay is the projection of a single day, all amounts in the base currency
+type ForecastDay struct {
+	Date      time.Time
+	Items     []ForecastItem
+	Scheduled models.Money
+	Variable  models.Money
+	Balance   models.Money
+}
+
+// Forecast is a day-by-day balance projection
+type Forecast struct {
+	// StartBalance is the balance at the end of today
+	StartBalance models.Money
+	// DailyVariable is the estimated spending per day that is not scheduled, as a negative amount
+	DailyVariable models.Money
+	Days          []ForecastDay
+	Lowest        ForecastDay
+	// FirstNegative is the index in Days of the first day ending below zero, or -1
+	FirstNegative int
+	// NextPayday is the start of the next fiscal month
+	NextPayday time.Time
+}
+
+// Forecast projects the balance for the days after now. It starts from the balance at the end of
+// today, adds transactions entered with a future date, the upcoming occurrences of recurrence
+// rules and unpaid loan installments, and subtracts the average daily variable spending of the
+// last historyMonths full months.
+func (fcs *ForecastService) Forecast(now time.Time, days, historyMonths int) (Forecast, error) {
+	if days <= 0 {
+		return Forecast{}, fmt.Errorf("error forecasting: horizon must be at least one day")
+	}
+	if historyMonths <= 0 {
+		historyMonths = DefaultForecastHistoryMonths
+	}
+
+	tl := fcs.financeService.GetTransactionList()
+	base := tl.GetBaseCurrency()
+	tomorrow := models.NewPeriod(models.PeriodDay, now, 0).End
+	horizon := models.CustomPeriod(tomorrow, tomorrow.AddDate(0, 0, days))
+
+	forecast := Forecast{
+		StartBalance:  models.NewMoney(0, base),
+		DailyVariable: fcs.dailyVariableSpending(now, historyMonths).Neg(),
+		FirstNegative: -1,
+		NextPayday:    tl.PeriodContaining(models.PeriodFiscalMonth, now).End,
+	}
+	for _, tx := range tl.Query(models.Query{Period: models.CustomPeriod(time.Time{}, tomorrow)}) {
+		forecast.StartBalance = forecast.StartBalance.Add(tl.BaseValue(tx))
+	}
+
+	// Known items by day offset from tomorrow
+	items := make(map[int][]ForecastItem)
+	addItem := func(tx models.Transaction, recurring bool) {
+		for day := 0; day < days; day++ {
+			if models.NewPeriod(models.PeriodDay, tomorrow.AddDate(0, 0, day), 0).Contains(tx.Date) {
+				items[day] = append(items[day], ForecastItem{
+					Description: tx.Description,
+					Value:       tl.BaseValue(tx),
+					Recurring:   recurring,
+				})
+				return
+			}
+		}
+	}
+	for _, tx := range tl.Query(models.Query{Period: horizon, SortBy: models.SortByDate}) {
+		addItem(tx, false)
+	}
+	for _, tx := range tl.UpcomingRecurring(now, horizon.End.Add(-time.Nanosecond)) {
+		addItem(tx, true)
+	}
+	for _, tx := range tl.UpcomingLoanInstallments(now, horizon.End.Add(-time.Nanosecond)) {
+		addItem(tx, true)
+	}
+
+	balance := forecast.StartBalance
+	for day := 0; day < days; day++ {
+		projected := ForecastDay{
+			Date:      tomorrow.AddDate(0, 0, day),
+			Items:     items[day],
+			Scheduled: models.NewMoney(0, base),
+			Variable:  forecast.DailyVariable,
+		}
+		for _, item := range projected.Items {
+			projected.Scheduled = projected.Scheduled.Add(item.Value)
+		}
+		balance = balance.Add(projected.Scheduled).Add(projected.Variable)
+		projected.Balance = balance
+
+		if day == 0 || projected.Balance.Amount < forecast.Lowest.Balance.Amount {
+			forecast.Lowest = projected
+		}
+		if forecast.FirstNegative < 0 && projected.Balance.IsNegative() {
+			forecast.FirstNegative = day
+		}
+		forecast.Days = append(forecast.Days, projected)
+	}
+	return forecast, nil
+}
+
+// dailyVariableSpending averages the expenses of the last full months per day, leaving out the
+// scheduled ones the forecast adds on their own dates: recurring transactions, installments of
+// purchases and loan payments. Months before the first transaction are left out so a new ledger
+// is not underestimated.
+func (fcs *ForecastService) dailyVariableSpending(now time.Time, historyMonths int) models.Money {
+	tl := fcs.financeService.GetTransactionList()
+	base := tl.GetBaseCurrency()
+
+	currentMonth := models.NewPeriod(models.PeriodMonth, now, 0)
+	window := models.CustomPeriod(currentMonth.Start.AddDate(0, -historyMonths, 0), currentMonth.Start)
+	if first := tl.Query(models.Query{SortBy: models.SortByDate, Limit: 1}); len(first) > 0 {
+		firstMonth := models.NewPeriod(models.PeriodMonth, first[0].Date, 0).Start
+		firstMonth = time.Date(firstMonth.Year(), firstMonth.Month(), 1, 0, 0, 0, 0, now.Location())
+		if firstMonth.After(window.Start) {
+			window.Start = firstMonth
+		}
+	}
+	if !window.Start.Before(window.End) {
+		return models.NewMoney(0, base)
+	}
+
+	total := models.NewMoney(0, base)
+	expenses := tl.Query(models.Query{Period: window, Types: []models.TransactionType{models.TransactionTypeExpense}})
+	for _, tx := range expenses {
+		if tx.RecurrenceID != 0 || tx.PurchaseID != 0 || tl.IsLoanPayment(tx.ID) {
+			continue
+		}
+		total = total.Add(tl.BaseValue(tx).Abs())
+	}
+
+	days := int64(window.End.Sub(window.Start).Hours()/24 + 0.5)
+	return models.NewMoney((total.Amount+days/2)/days, base)
+}
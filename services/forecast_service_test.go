@@ -0,0 +1,99 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"finance_go/models"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// newForecastLedger returns a ledger with 900.00 of variable spending from January to March 2026
+// next to a recurring bill, card installments and loan payments of the same period
+func newForecastLedger(t *testing.T) *FinanceService {
+	t.Helper()
+	tl := &models.TransactionList{BaseCurrency: "BRL"}
+	tl.EnsureAccounts()
+
+	tl.AddTransaction(models.NewTransactionWithDate(models.TransactionTypeIncome, models.NewMoney(500000, "BRL"), "Salário", "Salário", date(2026, 1, 2)))
+	tl.AddTransaction(models.NewTransactionWithDate(models.TransactionTypeExpense, models.NewMoney(60000, "BRL"), "Mercado", "Alimentação", date(2026, 1, 10)))
+	tl.AddTransaction(models.NewTransactionWithDate(models.TransactionTypeExpense, models.NewMoney(30000, "BRL"), "Restaurante", "Alimentação", date(2026, 3, 14)))
+
+	if _, err := tl.AddRecurrenceRule(models.RecurrenceRule{
+		Type:        models.TransactionTypeExpense,
+		Value:       models.NewMoney(5000, "BRL"),
+		Description: "Internet",
+		Frequency:   models.FrequencyMonthly,
+		StartDate:   date(2026, 1, 5),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	tl.MaterializeRecurring(date(2026, 4, 15))
+
+	if _, err := tl.AddInstallmentPurchase(models.InstallmentPurchase{
+		Description:  "Geladeira",
+		Total:        models.NewMoney(30000, "BRL"),
+		Installments: 3,
+		FirstDate:    date(2026, 1, 20),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	loan, err := tl.AddLoan(models.Loan{
+		Name:         "Carro",
+		Principal:    models.NewMoney(120000, "BRL"),
+		Term:         12,
+		System:       models.AmortizationPrice,
+		FirstDueDate: date(2026, 2, 10),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for number, paid := range []time.Time{date(2026, 2, 10), date(2026, 3, 10)} {
+		if _, err := tl.PayLoanInstallment(loan.ID, number+1, paid); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fs := NewFinanceService()
+	fs.SetTransactionList(tl)
+	return fs
+}
+
+func TestForecastVariableSpendingLeavesOutScheduledItems(t *testing.T) {
+	fcs := NewForecastService(newForecastLedger(t))
+	forecast, err := fcs.Forecast(date(2026, 4, 15), 30, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 900.00 over the 90 days from January 1 to March 31
+	if want := models.NewMoney(-1000, "BRL"); forecast.DailyVariable != want {
+		t.Errorf("DailyVariable = %v, want %v", forecast.DailyVariable, want)
+	}
+
+	scheduled := make(map[string]models.Money)
+	for _, day := range forecast.Days {
+		for _, item := range day.Items {
+			if !item.Recurring {
+				t.Errorf("unexpected entered item %+v on %v", item, day.Date)
+			}
+			scheduled[day.Date.Format("2006-01-02")] = item.Value
+		}
+	}
+	want := map[string]models.Money{
+		"2026-05-05": models.NewMoney(-5000, "BRL"),
+		"2026-05-10": models.NewMoney(-10000, "BRL"),
+	}
+	if len(scheduled) != len(want) {
+		t.Fatalf("scheduled items = %v, want %v", scheduled, want)
+	}
+	for day, value := range want {
+		if scheduled[day] != value {
+			t.Errorf("item on %s = %v, want %v", day, scheduled[day], value)
+		}
+	}
+}
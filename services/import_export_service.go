@@ -25,116 +25,385 @@ func NewImportExportService(financeService *FinanceService) *ImportExportService
 	}
 }
 
-// ImportFromCSV imports transactions from a CSV file
-func (ies *ImportExportService) ImportFromCSV(filename string) error {
+// ImportRow is a data row of an imported file and what happened to it
+type ImportRow struct {
+	// Line is the line of the row in the file, counting the header as line 1
+	Line        int
+	Transaction models.Transaction
+	// Reason explains why the row was skipped
+	Reason string
+	// Suggestion is the category learned from the ledger for a row imported without one. Confident
+	// suggestions are used as the category; the others are left for review.
+	Suggestion *models.CategorySuggestion
+}
+
+// ImportReport lists the rows an import added and the ones it skipped
+type ImportReport struct {
+	Added      []ImportRow
+	Duplicates []ImportRow
+	Invalid    []ImportRow
+}
+
+// NeedsReview returns the added rows whose suggested category was too uncertain to be used
+func (r ImportReport) NeedsReview() []ImportRow {
+	var rows []ImportRow
+	for _, row := range r.Added {
+		if row.Suggestion != nil && row.Suggestion.NeedsReview() {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// ImportFromCSV imports transactions from a CSV file into the given account, skipping rows
+// already imported or matching an existing transaction
+func (ies *ImportExportService) ImportFromCSV(filename string, accountID int) (ImportReport, error) {
 	file, err := os.Open(filename)
 	if err != nil {
-		return fmt.Errorf("error opening CSV file: %w", err)
+		return ImportReport{}, fmt.Errorf("error opening CSV file: %w", err)
 	}
 	defer file.Close()
 
 	reader := csv.NewReader(file)
 	records, err := reader.ReadAll()
 	if err != nil {
-		return fmt.Errorf("error reading CSV: %w", err)
+		return ImportReport{}, fmt.Errorf("error reading CSV: %w", err)
 	}
 
 	if len(records) < 2 {
-		return fmt.Errorf("CSV file must have at least a header and one data row")
+		return ImportReport{}, fmt.Errorf("CSV file must have at least a header and one data row")
 	}
 
-	for i := 1; i < len(records); i++ {
-		record := records[i]
-		if len(record) < 4 {
-			continue
+	return ies.importRows(records, accountID), nil
+}
+
+// ImportFromExcel imports transactions from an Excel file into the given account, skipping rows
+// already imported or matching an existing transaction
+func (ies *ImportExportService) ImportFromExcel(filename string, accountID int) (ImportReport, error) {
+	f, err := excelize.OpenFile(filename)
+	if err != nil {
+		return ImportReport{}, fmt.Errorf("error opening Excel file: %w", err)
+	}
+	defer f.Close()
+
+	sheetName := f.GetSheetName(0)
+	rows, err := f.GetRows(sheetName)
+	if err != nil {
+		return ImportReport{}, fmt.Errorf("error reading Excel sheet: %w", err)
+	}
+
+	if len(rows) < 2 {
+		return ImportReport{}, fmt.Errorf("Excel file must have at least a header and one data row")
+	}
+
+	return ies.importRows(rows, accountID), nil
+}
+
+// importColumns locates the optional columns of an imported sheet by header name.
+// The first four columns are always Data, Valor, Descrição and Categoria.
+type importColumns struct {
+	currency int
+	tags     int
+	group    int
+}
+
+func newImportColumns(header []string) importColumns {
+	columns := importColumns{currency: -1, tags: -1, group: -1}
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "moeda":
+			columns.currency = i
+		case "tags":
+			columns.tags = i
+		case "transação", "transacao":
+			columns.group = i
 		}
+	}
+	return columns
+}
+
+// field returns the trimmed value of an optional column, or an empty string if it is missing
+func (ic importColumns) field(row []string, index int) string {
+	if index < 0 || index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
+
+// transaction parses a data row; rows without a valid amount are rejected
+func (ic importColumns) transaction(row []string) (models.Transaction, error) {
+	if len(row) < 4 {
+		return models.Transaction{}, fmt.Errorf("row must have at least 4 columns")
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(row[0]), time.Local)
+	if err != nil {
+		date = time.Now()
+	}
 
-		date, err := time.Parse("2006-01-02", strings.TrimSpace(record[0]))
+	currency := models.DefaultCurrency
+	if value := ic.field(row, ic.currency); value != "" {
+		currency = value
+	}
+
+	amount, err := models.ParseMoney(row[1], currency)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	// The sign in the file decides the type; NewTransactionWithDate keeps the sign canonical
+	transactionType := models.TransactionTypeForValue(amount)
+
+	description := strings.TrimSpace(row[2])
+	category := strings.TrimSpace(row[3])
+
+	transaction := models.NewTransactionWithDate(transactionType, amount, description, category, date)
+	transaction.Tags = models.ParseTags(ic.field(row, ic.tags))
+	return transaction, nil
+}
+
+// importRows adds the data rows of an imported sheet; the first row is the header.
+// Consecutive or scattered rows sharing a "Transação" value become one split transaction.
+// Rows without a category get one from the first matching categorization rule or, failing
+// that, from the category suggested by the history with enough confidence.
+// A transaction is skipped when its fingerprint was already imported, or when an existing
+// transaction of the account has the same value, a close date and a similar description.
+func (ies *ImportExportService) importRows(rows [][]string, accountID int) ImportReport {
+	columns := newImportColumns(rows[0])
+
+	var report ImportReport
+	var pending []ImportRow
+	var cells [][]string
+	groups := make(map[string]int)
+	for i, row := range rows[1:] {
+		line := i + 2
+		transaction, err := columns.transaction(row)
 		if err != nil {
-			date = time.Now()
+			report.Invalid = append(report.Invalid, ImportRow{Line: line, Reason: err.Error()})
+			continue
 		}
-
-		amountStr := strings.ReplaceAll(strings.TrimSpace(record[1]), ",", ".")
-		amount, err := strconv.ParseFloat(amountStr, 64)
+		transaction.AccountID = accountID
+		transaction = ies.financeService.Categorize(transaction)
+
+		key := columns.field(row, columns.group)
+		index, grouped := groups[key]
+		if key == "" || !grouped {
+			if key != "" {
+				groups[key] = len(pending)
+			}
+			pending = append(pending, ImportRow{Line: line, Transaction: transaction})
+			cells = append(cells, append([]string(nil), row...))
+			continue
+		}
+		if pending[index].Reason != "" {
+			continue
+		}
+		merged, err := mergeSplit(pending[index].Transaction, transaction)
 		if err != nil {
+			pending[index].Reason = fmt.Sprintf("linha %d da transação %q: %v", line, key, err)
 			continue
 		}
+		pending[index].Transaction = merged
+		cells[index] = append(cells[index], row...)
+	}
 
-		transactionType := "Receita"
-		if amount < 0 {
-			transactionType = "Despesa"
-			amount = -amount
+	tl := ies.financeService.GetTransactionList()
+	// Trained before adding anything, so the file does not learn from itself
+	classifier := ies.financeService.Classifier()
+	occurrences := make(map[string]int)
+	matched := make(map[int]bool)
+	for i, row := range pending {
+		if row.Reason != "" {
+			report.Invalid = append(report.Invalid, row)
+			continue
+		}
+		raw := strings.Join(cells[i], "\x1f")
+		row.Transaction.ImportID = models.ImportFingerprint(accountID, cells[i], occurrences[raw])
+		occurrences[raw]++
+
+		if existing, found := tl.FindByImportID(row.Transaction.ImportID); found {
+			matched[existing.ID] = true
+			row.Reason = fmt.Sprintf("já importada (transação %d)", existing.ID)
+			report.Duplicates = append(report.Duplicates, row)
+			continue
+		}
+		if existing, found := tl.FindDuplicate(row.Transaction, models.DefaultDuplicateDays, matched); found {
+			matched[existing.ID] = true
+			row.Reason = fmt.Sprintf("igual à transação %d de %s: %s", existing.ID, existing.Date.Format("02/01/2006"), existing.Description)
+			report.Duplicates = append(report.Duplicates, row)
+			continue
 		}
 
-		description := strings.TrimSpace(record[2])
-		category := strings.TrimSpace(record[3])
+		if row.Transaction.Category == "" && !row.Transaction.IsSplit() {
+			if suggestion, found := classifier.Best(row.Transaction.Description, row.Transaction.Type); found {
+				row.Suggestion = &suggestion
+				if !suggestion.NeedsReview() {
+					row.Transaction.Category = suggestion.Category
+				}
+			}
+		}
 
-		transaction := models.NewTransactionWithDate(transactionType, amount, description, category, date)
-		ies.financeService.AddTransactionFromModel(transaction)
+		if err := ies.financeService.AddTransactionFromModel(row.Transaction); err != nil {
+			row.Reason = err.Error()
+			report.Invalid = append(report.Invalid, row)
+			continue
+		}
+		// Rows of the same file never match the transactions it adds
+		row.Transaction = tl.Transactions[len(tl.Transactions)-1]
+		matched[row.Transaction.ID] = true
+		report.Added = append(report.Added, row)
 	}
-
-	return nil
+	return report
 }
 
-// ImportFromExcel imports transactions from an Excel file
-func (ies *ImportExportService) ImportFromExcel(filename string) error {
-	f, err := excelize.OpenFile(filename)
-	if err != nil {
-		return fmt.Errorf("error opening Excel file: %w", err)
+// mergeSplit adds row as a split of transaction, turning transaction into a split transaction if needed.
+// Every row of a split transaction must be in the transaction's currency.
+func mergeSplit(transaction, row models.Transaction) (models.Transaction, error) {
+	if row.Value.Currency != transaction.Value.Currency {
+		return models.Transaction{}, fmt.Errorf("%w: row in %s, transaction in %s", models.ErrCurrencyMismatch, row.Value.Currency, transaction.Value.Currency)
 	}
-	defer f.Close()
+	if !transaction.IsSplit() {
+		transaction.Splits = []models.Split{{
+			Value:       transaction.Value,
+			Category:    transaction.Category,
+			Description: transaction.Description,
+		}}
+		transaction.Category = ""
+	}
+	transaction.Splits = append(transaction.Splits, models.Split{
+		Value:       row.Value,
+		Category:    row.Category,
+		Description: row.Description,
+	})
+
+	total := models.NewMoney(0, transaction.Value.Currency)
+	for _, split := range transaction.Splits {
+		total = total.Add(split.Value)
+	}
+	transaction.Value = total
+	transaction.Type = models.TransactionTypeForValue(total)
+	return transaction, nil
+}
 
-	sheetName := f.GetSheetName(0)
-	rows, err := f.GetRows(sheetName)
+// ImportExchangeRatesFromCSV imports exchange rates into the local rate table and
+// returns how many were stored. Two layouts are accepted: the Central Bank PTAX
+// file (DDMMYYYY;code;type;currency;buy;sell;...) and a generic CSV with a
+// header and Data,Moeda,Taxa[,Cotação] columns.
+func (ies *ImportExportService) ImportExchangeRatesFromCSV(filename string) (int, error) {
+	data, err := os.ReadFile(filename)
 	if err != nil {
-		return fmt.Errorf("error reading Excel sheet: %w", err)
+		return 0, fmt.Errorf("error opening CSV file: %w", err)
 	}
 
-	if len(rows) < 2 {
-		return fmt.Errorf("Excel file must have at least a header and one data row")
+	ptax := strings.Count(firstLine(string(data)), ";") >= 5
+	reader := csv.NewReader(strings.NewReader(string(data)))
+	reader.FieldsPerRecord = -1
+	if ptax {
+		reader.Comma = ';'
 	}
 
-	for i := 1; i < len(rows); i++ {
-		row := rows[i]
-		if len(row) < 4 {
-			continue
-		}
+	records, err := reader.ReadAll()
+	if err != nil {
+		return 0, fmt.Errorf("error reading CSV: %w", err)
+	}
 
-		dateStr := strings.TrimSpace(row[0])
-		var date time.Time
-		if dateStr != "" {
-			date, err = time.Parse("2006-01-02", dateStr)
-			if err != nil {
-				date = time.Now()
-			}
+	imported := 0
+	for i, record := range records {
+		var rate models.ExchangeRate
+		if ptax {
+			rate, err = parsePTAXRecord(record)
 		} else {
-			date = time.Now()
+			if i == 0 {
+				continue // header
+			}
+			rate, err = parseExchangeRateRecord(record)
 		}
-
-		amountStr := strings.ReplaceAll(strings.TrimSpace(row[1]), ",", ".")
-		amount, err := strconv.ParseFloat(amountStr, 64)
 		if err != nil {
 			continue
 		}
 
-		transactionType := "Receita"
-		if amount < 0 {
-			transactionType = "Despesa"
-			amount = -amount
+		if err := ies.financeService.SetExchangeRate(rate); err != nil {
+			continue
 		}
+		imported++
+	}
 
-		description := strings.TrimSpace(row[2])
-		category := strings.TrimSpace(row[3])
+	if imported == 0 {
+		return 0, fmt.Errorf("no valid exchange rates found")
+	}
+	return imported, nil
+}
 
-		transaction := models.NewTransactionWithDate(transactionType, amount, description, category, date)
-		ies.financeService.AddTransactionFromModel(transaction)
+// parsePTAXRecord parses a Central Bank PTAX line, using the selling rate quoted in BRL
+func parsePTAXRecord(record []string) (models.ExchangeRate, error) {
+	if len(record) < 6 {
+		return models.ExchangeRate{}, fmt.Errorf("PTAX record must have at least 6 fields")
 	}
 
-	return nil
+	date, err := time.Parse("02012006", strings.TrimSpace(record[0]))
+	if err != nil {
+		return models.ExchangeRate{}, fmt.Errorf("invalid PTAX date: %w", err)
+	}
+
+	rate, err := parseRate(record[5])
+	if err != nil {
+		return models.ExchangeRate{}, err
+	}
+
+	return models.ExchangeRate{
+		Date:     date,
+		Currency: strings.TrimSpace(record[3]),
+		Quote:    "BRL",
+		Rate:     rate,
+	}, nil
+}
+
+// parseExchangeRateRecord parses a generic Data,Moeda,Taxa[,Cotação] line
+func parseExchangeRateRecord(record []string) (models.ExchangeRate, error) {
+	if len(record) < 3 {
+		return models.ExchangeRate{}, fmt.Errorf("exchange rate record must have at least 3 fields")
+	}
+
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(record[0]))
+	if err != nil {
+		return models.ExchangeRate{}, fmt.Errorf("invalid date: %w", err)
+	}
+
+	rate, err := parseRate(record[2])
+	if err != nil {
+		return models.ExchangeRate{}, err
+	}
+
+	quote := "BRL"
+	if len(record) > 3 && strings.TrimSpace(record[3]) != "" {
+		quote = strings.TrimSpace(record[3])
+	}
+
+	return models.ExchangeRate{
+		Date:     date,
+		Currency: strings.TrimSpace(record[1]),
+		Quote:    quote,
+		Rate:     rate,
+	}, nil
+}
+
+func parseRate(s string) (float64, error) {
+	rate, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", "."), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate %q: %w", s, err)
+	}
+	return rate, nil
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
 }
 
-// ExportToCSV exports transactions to a CSV file
-func (ies *ImportExportService) ExportToCSV(filename string) error {
+// ExportToCSV exports the transactions matching a query to a CSV file
+func (ies *ImportExportService) ExportToCSV(filename string, query models.Query) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("error creating CSV file: %w", err)
@@ -144,59 +413,114 @@ func (ies *ImportExportService) ExportToCSV(filename string) error {
 	writer := csv.NewWriter(file)
 	defer writer.Flush()
 
-	header := []string{"Data", "Valor", "Descrição", "Categoria", "Tipo"}
+	header := []string{"Data", "Valor", "Descrição", "Categoria", "Tipo", "Conta", "Moeda", "Valor Base", "Tags", "Transação"}
 	if err := writer.Write(header); err != nil {
 		return fmt.Errorf("error writing CSV header: %w", err)
 	}
 
-	transactions := ies.financeService.GetTransactions()
+	// Split transactions are written as one row per split, sharing the "Transação" ID
+	transactions := ies.financeService.QueryTransactions(query)
 	for _, tx := range transactions {
-		amount := tx.Value
-		if tx.Type == "Despesa" {
-			amount = -amount
-		}
-
-		record := []string{
-			tx.Date.Format("2006-01-02"),
-			fmt.Sprintf("%.2f", amount),
-			tx.Description,
-			tx.Category,
-			tx.Type,
-		}
-		if err := writer.Write(record); err != nil {
-			return fmt.Errorf("error writing CSV record: %w", err)
+		for _, part := range tx.Parts() {
+			record := []string{
+				tx.Date.Format("2006-01-02"),
+				part.Value.Decimal(),
+				part.Description,
+				part.Category,
+				string(tx.Type),
+				ies.accountName(tx.AccountID),
+				part.Value.Currency,
+				ies.baseDecimal(part.Value, tx.Date),
+				models.FormatTags(tx.Tags),
+				strconv.Itoa(tx.ID),
+			}
+			if err := writer.Write(record); err != nil {
+				return fmt.Errorf("error writing CSV record: %w", err)
+			}
 		}
 	}
 
 	return nil
 }
 
-// ExportToExcel exports transactions to an Excel file
-func (ies *ImportExportService) ExportToExcel(filename string) error {
+// ExportToExcel exports the transactions matching a query to an Excel file
+func (ies *ImportExportService) ExportToExcel(filename string, query models.Query) error {
 	f := excelize.NewFile()
 	defer f.Close()
 
-	headers := []string{"Data", "Valor", "Descrição", "Categoria", "Tipo"}
+	headers := []string{"Data", "Valor", "Descrição", "Categoria", "Tipo", "Conta", "Moeda", "Valor Base", "Tags", "Transação"}
 	for i, header := range headers {
 		cell := fmt.Sprintf("%c1", 'A'+i)
 		f.SetCellValue("Sheet1", cell, header)
 	}
 
-	transactions := ies.financeService.GetTransactions()
-	for i, tx := range transactions {
-		row := i + 2 // Start from row 2 (after header)
-		amount := tx.Value
-		if tx.Type == "Despesa" {
-			amount = -amount
+	// Split transactions are written as one row per split, sharing the "Transação" ID
+	row := 2 // Start from row 2 (after header)
+	transactions := ies.financeService.QueryTransactions(query)
+	for _, tx := range transactions {
+		for _, part := range tx.Parts() {
+			f.SetCellValue("Sheet1", fmt.Sprintf("A%d", row), tx.Date.Format("2006-01-02"))
+			f.SetCellValue("Sheet1", fmt.Sprintf("B%d", row), part.Value.Float64())
+			f.SetCellValue("Sheet1", fmt.Sprintf("C%d", row), part.Description)
+			f.SetCellValue("Sheet1", fmt.Sprintf("D%d", row), part.Category)
+			f.SetCellValue("Sheet1", fmt.Sprintf("E%d", row), string(tx.Type))
+			f.SetCellValue("Sheet1", fmt.Sprintf("F%d", row), ies.accountName(tx.AccountID))
+			f.SetCellValue("Sheet1", fmt.Sprintf("G%d", row), part.Value.Currency)
+			// Values without an exchange rate leave the base value cell empty
+			if base, err := ies.financeService.GetTransactionList().Convert(part.Value, ies.financeService.GetBaseCurrency(), tx.Date); err == nil {
+				f.SetCellValue("Sheet1", fmt.Sprintf("H%d", row), base.Float64())
+			}
+			f.SetCellValue("Sheet1", fmt.Sprintf("I%d", row), models.FormatTags(tx.Tags))
+			f.SetCellValue("Sheet1", fmt.Sprintf("J%d", row), tx.ID)
+			row++
 		}
+	}
 
-		f.SetCellValue("Sheet1", fmt.Sprintf("A%d", row), tx.Date.Format("2006-01-02"))
-		f.SetCellValue("Sheet1", fmt.Sprintf("B%d", row), amount)
-		f.SetCellValue("Sheet1", fmt.Sprintf("C%d", row), tx.Description)
-		f.SetCellValue("Sheet1", fmt.Sprintf("D%d", row), tx.Category)
-		f.SetCellValue("Sheet1", fmt.Sprintf("E%d", row), tx.Type)
+	for i := 0; i < len(headers); i++ {
+		col := string(rune('A' + i))
+		f.SetColWidth("Sheet1", col, col, 15)
 	}
 
+	return f.SaveAs(filename)
+}
+
+// ExportLoanScheduleToExcel exports the amortization schedule of a loan to an Excel file
+func (ies *ImportExportService) ExportLoanScheduleToExcel(filename string, loanID int) error {
+	status, err := ies.financeService.GetLoanStatus(loanID)
+	if err != nil {
+		return err
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	headers := []string{"Parcela", "Vencimento", "Prestação", "Juros", "Amortização", "Saldo Devedor", "Transação"}
+	for i, header := range headers {
+		cell := fmt.Sprintf("%c1", 'A'+i)
+		f.SetCellValue("Sheet1", cell, header)
+	}
+
+	row := 2
+	for _, installment := range status.Schedule {
+		f.SetCellValue("Sheet1", fmt.Sprintf("A%d", row), installment.Number)
+		f.SetCellValue("Sheet1", fmt.Sprintf("B%d", row), installment.DueDate.Format("2006-01-02"))
+		f.SetCellValue("Sheet1", fmt.Sprintf("C%d", row), installment.Payment.Float64())
+		f.SetCellValue("Sheet1", fmt.Sprintf("D%d", row), installment.Interest.Float64())
+		f.SetCellValue("Sheet1", fmt.Sprintf("E%d", row), installment.Amortization.Float64())
+		f.SetCellValue("Sheet1", fmt.Sprintf("F%d", row), installment.Balance.Float64())
+		if installment.IsPaid() {
+			f.SetCellValue("Sheet1", fmt.Sprintf("G%d", row), installment.TransactionID)
+		}
+		row++
+	}
+
+	row++
+	f.SetCellValue("Sheet1", fmt.Sprintf("A%d", row), "Saldo Devedor Atual")
+	f.SetCellValue("Sheet1", fmt.Sprintf("C%d", row), status.Outstanding.Float64())
+	row++
+	f.SetCellValue("Sheet1", fmt.Sprintf("A%d", row), "Juros Pagos")
+	f.SetCellValue("Sheet1", fmt.Sprintf("C%d", row), status.InterestPaid.Float64())
+
 	for i := 0; i < len(headers); i++ {
 		col := string(rune('A' + i))
 		f.SetColWidth("Sheet1", col, col, 15)
@@ -204,3 +528,21 @@ func (ies *ImportExportService) ExportToExcel(filename string) error {
 
 	return f.SaveAs(filename)
 }
+
+// baseDecimal formats a value converted to the base currency, or an empty string when no rate is available
+func (ies *ImportExportService) baseDecimal(value models.Money, date time.Time) string {
+	base, err := ies.financeService.GetTransactionList().Convert(value, ies.financeService.GetBaseCurrency(), date)
+	if err != nil {
+		return ""
+	}
+	return base.Decimal()
+}
+
+// accountName returns the name of an account, or an empty string if it does not exist
+func (ies *ImportExportService) accountName(accountID int) string {
+	account, err := ies.financeService.GetTransactionList().GetAccountByID(accountID)
+	if err != nil {
+		return ""
+	}
+	return account.Name
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// importCSV writes content to a CSV file and imports it into the default account
+func importCSV(t *testing.T, fs *FinanceService, content string) ImportReport {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "extrato.csv")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	report, err := NewImportExportService(fs).ImportFromCSV(path, fs.GetTransactionList().DefaultAccountID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func TestImportSplitGroups(t *testing.T) {
+	fs := NewFinanceService()
+	report := importCSV(t, fs, strings.Join([]string{
+		"Data,Valor,Descrição,Categoria,Moeda,Transação",
+		"2026-01-10,-70.00,Mercado,Alimentação,BRL,A",
+		"2026-01-10,-30.00,Mercado,Casa > Limpeza,BRL,A",
+		"2026-01-11,-20.00,Loja,Casa,BRL,B",
+		"2026-01-11,-5.00,Loja,Casa,USD,B",
+		"2026-01-11,-7.00,Loja,Casa,BRL,B",
+		"2026-01-12,-9.00,Padaria,Alimentação,BRL,",
+	}, "\n"))
+
+	if len(report.Added) != 2 || len(report.Invalid) != 1 {
+		t.Fatalf("added %d and invalid %d rows, want 2 and 1: %+v", len(report.Added), len(report.Invalid), report.Invalid)
+	}
+	market := report.Added[0].Transaction
+	if len(market.Splits) != 2 || market.Value.Amount != -10000 {
+		t.Errorf("grouped transaction = %+v", market)
+	}
+	// A group mixing currencies is rejected as a whole, naming the offending row
+	invalid := report.Invalid[0]
+	if invalid.Line != 4 || !strings.Contains(invalid.Reason, "linha 5") || !strings.Contains(invalid.Reason, "USD") {
+		t.Errorf("invalid group = line %d, %q", invalid.Line, invalid.Reason)
+	}
+	if got := len(fs.GetTransactions()); got != 2 {
+		t.Errorf("%d transactions stored, want 2", got)
+	}
+}
@@ -2,25 +2,45 @@ package services
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"finance_go/models"
+
 	"github.com/jung-kurt/gofpdf"
 )
 
 // PDFExportService handles PDF report generation
 type PDFExportService struct {
 	financeService *FinanceService
+	reportService  *ReportService
+	categoryDepth  int
+	forecastDays   int
 }
 
 // NewPDFExportService creates a new PDF export service
 func NewPDFExportService(financeService *FinanceService) *PDFExportService {
 	return &PDFExportService{
 		financeService: financeService,
+		reportService:  NewReportService(financeService),
 	}
 }
 
-// ExportToPDF exports transactions to a PDF report
-func (pes *PDFExportService) ExportToPDF(filename string) error {
+// SetCategoryDepth limits the category summary to the given number of levels (0 shows all levels)
+func (pes *PDFExportService) SetCategoryDepth(depth int) {
+	pes.categoryDepth = depth
+}
+
+// SetForecastDays adds a cash-flow forecast for the given number of days to ExportToPDF (0 leaves it out)
+func (pes *PDFExportService) SetForecastDays(days int) {
+	pes.forecastDays = days
+}
+
+// ExportToPDF exports a PDF report of the transactions matching a query. Totals, the transaction
+// table and the category and tag summaries cover the matching transactions; balances cover the whole ledger.
+func (pes *PDFExportService) ExportToPDF(filename string, query models.Query) error {
+	report := pes.reportService.Build(query)
+
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.AddPage()
 
@@ -29,39 +49,241 @@ func (pes *PDFExportService) ExportToPDF(filename string) error {
 	pdf.Cell(190, 10, "Relatório Financeiro")
 	pdf.Ln(15)
 
+	if !query.Period.IsAll() {
+		pdf.SetFont("Arial", "", 10)
+		pdf.Cell(190, 6, fmt.Sprintf("Período: %s", query.Period.Label()))
+		pdf.Ln(8)
+	}
+
 	pdf.SetFont("Arial", "B", 12)
 	pdf.Cell(190, 8, "Resumo")
 	pdf.Ln(10)
 
 	pdf.SetFont("Arial", "", 10)
 	balance := pes.financeService.GetBalance()
-	pdf.Cell(190, 6, fmt.Sprintf("Saldo Total: R$ %.2f", balance))
+	pdf.Cell(190, 6, fmt.Sprintf("Saldo Total: %s", balance))
+	pdf.Ln(6)
+	pdf.Cell(190, 6, fmt.Sprintf("Moeda Base: %s", pes.financeService.GetBaseCurrency()))
 	pdf.Ln(8)
+	pes.writeMissingRates(pdf)
 
-	transactions := pes.financeService.GetTransactions()
-	totalIncome := 0.0
-	totalExpenses := 0.0
+	pdf.Cell(190, 6, fmt.Sprintf("Total Receitas: %s", report.Totals.Income))
+	pdf.Ln(6)
+	pdf.Cell(190, 6, fmt.Sprintf("Total Despesas: %s", report.Totals.Expense))
+	pdf.Ln(6)
+	pes.writeCommittedSummary(pdf, report)
+	pdf.Ln(4)
 
-	for _, tx := range transactions {
-		if tx.Type == "Receita" {
-			totalIncome += tx.Value
-		} else {
-			totalExpenses += tx.Value
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(190, 8, "Saldo por Conta")
+	pdf.Ln(10)
+
+	pdf.SetFont("Arial", "", 10)
+	for _, account := range pes.financeService.GetAccounts() {
+		pdf.Cell(190, 6, fmt.Sprintf("%s: %s", account.Name, pes.financeService.GetAccountBalance(account.ID)))
+		pdf.Ln(6)
+	}
+	pdf.Ln(4)
+
+	pes.writeTransactionTable(pdf, "Transações", report.Transactions)
+	pes.writeCommittedSection(pdf, report)
+	pes.writeCategorySection(pdf, report.Categories)
+	pes.writeTagSection(pdf, report.Tags)
+
+	// Budgets follow the month selected in the query, or the current month
+	budgetMonth := time.Now()
+	if query.Period.Kind == models.PeriodMonth {
+		budgetMonth = query.Period.Start
+	}
+	pes.writeBudgetSection(pdf, budgetMonth.Year(), budgetMonth.Month())
+	pes.writeGoalSection(pdf, time.Now())
+
+	if pes.forecastDays > 0 {
+		forecast, err := NewForecastService(pes.financeService).Forecast(time.Now(), pes.forecastDays, DefaultForecastHistoryMonths)
+		if err != nil {
+			return err
 		}
+		pes.writeForecastSection(pdf, forecast)
+	}
+
+	pdf.Ln(10)
+	pdf.SetFont("Arial", "I", 8)
+	pdf.Cell(190, 6, fmt.Sprintf("Relatório gerado em: %s", time.Now().Format("02/01/2006 15:04:05")))
+
+	return pdf.OutputFileAndClose(filename)
+}
+
+// ExportPeriodReport exports a report of the transactions within a period to PDF
+func (pes *PDFExportService) ExportPeriodReport(filename string, period models.Period) error {
+	query := models.Query{Period: period, SortBy: models.SortByDate}
+	report := pes.reportService.Build(query)
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+
+	pdf.SetFont("Arial", "B", 16)
+
+	title := "Relatório"
+	if period.Kind == models.PeriodMonth {
+		title = "Relatório Mensal"
+	}
+	pdf.Cell(190, 10, fmt.Sprintf("%s - %s", title, period.Label()))
+	pdf.Ln(15)
+
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(190, 8, "Resumo do Período")
+	pdf.Ln(10)
+	pes.writeMissingRates(pdf)
+
+	pdf.SetFont("Arial", "", 10)
+	pdf.Cell(190, 6, fmt.Sprintf("Saldo Inicial: %s", report.Opening))
+	pdf.Ln(6)
+	pdf.Cell(190, 6, fmt.Sprintf("Receitas do Período: %s", report.Totals.Income))
+	pdf.Ln(6)
+	pdf.Cell(190, 6, fmt.Sprintf("Despesas do Período: %s", report.Totals.Expense))
+	pdf.Ln(6)
+	pdf.Cell(190, 6, fmt.Sprintf("Saldo do Período: %s", report.Totals.Balance()))
+	pdf.Ln(6)
+	pdf.Cell(190, 6, fmt.Sprintf("Saldo Final: %s", report.Closing))
+	pdf.Ln(6)
+	pes.writeCommittedSummary(pdf, report)
+	pdf.Ln(4)
+
+	// Longer periods are broken down into months, months into weeks
+	switch period.Kind {
+	case models.PeriodYear, models.PeriodQuarter:
+		pes.writePeriodSeries(pdf, "Evolução Mensal", pes.reportService.TotalsByPeriod(query, models.PeriodMonth))
+	case models.PeriodMonth, models.PeriodFiscalMonth:
+		pes.writePeriodSeries(pdf, "Evolução Semanal", pes.reportService.TotalsByPeriod(query, models.PeriodWeek))
+	}
+
+	pes.writeTransactionTable(pdf, "Transações do Período", report.Transactions)
+	pes.writeCommittedSection(pdf, report)
+	pes.writeCategorySection(pdf, report.Categories)
+
+	// Budgets are monthly, so they are only meaningful for calendar month reports
+	if period.Kind == models.PeriodMonth {
+		pes.writeBudgetSection(pdf, period.Start.Year(), period.Start.Month())
 	}
 
-	pdf.Cell(190, 6, fmt.Sprintf("Total Receitas: R$ %.2f", totalIncome))
+	pdf.Ln(10)
+	pdf.SetFont("Arial", "I", 8)
+	pdf.Cell(190, 6, fmt.Sprintf("Relatório gerado em: %s", time.Now().Format("02/01/2006 15:04:05")))
+
+	return pdf.OutputFileAndClose(filename)
+}
+
+// ExportLoanSchedule exports the amortization schedule of a loan to a PDF, marking paid installments
+func (pes *PDFExportService) ExportLoanSchedule(filename string, loanID int) error {
+	status, err := pes.financeService.GetLoanStatus(loanID)
+	if err != nil {
+		return err
+	}
+	loan := status.Loan
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+
+	pdf.SetFont("Arial", "B", 16)
+	pdf.Cell(190, 10, fmt.Sprintf("Financiamento - %s", loan.Name))
+	pdf.Ln(15)
+
+	pdf.SetFont("Arial", "", 10)
+	pdf.Cell(190, 6, fmt.Sprintf("Valor Financiado: %s", loan.Principal))
+	pdf.Ln(6)
+	pdf.Cell(190, 6, fmt.Sprintf("Taxa: %.4g%% a.m. | Prazo: %d meses | Sistema: %s", loan.MonthlyRate, loan.Term, loan.System))
 	pdf.Ln(6)
-	pdf.Cell(190, 6, fmt.Sprintf("Total Despesas: R$ %.2f", totalExpenses))
+	pdf.Cell(190, 6, fmt.Sprintf("Parcelas Pagas: %d de %d", status.PaidCount, loan.Term))
+	pdf.Ln(6)
+	pdf.Cell(190, 6, fmt.Sprintf("Saldo Devedor: %s", status.Outstanding))
+	pdf.Ln(6)
+	pdf.Cell(190, 6, fmt.Sprintf("Juros Pagos: %s de %s", status.InterestPaid, status.TotalInterest))
+	pdf.Ln(10)
+
+	pdf.SetFont("Arial", "B", 9)
+	pdf.Cell(15, 7, "Nº")
+	pdf.Cell(25, 7, "Vencimento")
+	pdf.Cell(35, 7, "Prestação")
+	pdf.Cell(35, 7, "Juros")
+	pdf.Cell(35, 7, "Amortização")
+	pdf.Cell(35, 7, "Saldo Devedor")
+	pdf.Cell(10, 7, "Paga")
+	pdf.Ln(-1)
+
+	pdf.SetFont("Arial", "", 8)
+	for _, installment := range status.Schedule {
+		paid := ""
+		if installment.IsPaid() {
+			paid = "Sim"
+			pdf.SetTextColor(0, 120, 0)
+		}
+		pdf.Cell(15, 6, fmt.Sprintf("%d", installment.Number))
+		pdf.Cell(25, 6, installment.DueDate.Format("02/01/2006"))
+		pdf.Cell(35, 6, installment.Payment.String())
+		pdf.Cell(35, 6, installment.Interest.String())
+		pdf.Cell(35, 6, installment.Amortization.String())
+		pdf.Cell(35, 6, installment.Balance.String())
+		pdf.Cell(10, 6, paid)
+		pdf.Ln(-1)
+		pdf.SetTextColor(0, 0, 0)
+	}
+
 	pdf.Ln(10)
+	pdf.SetFont("Arial", "I", 8)
+	pdf.Cell(190, 6, fmt.Sprintf("Relatório gerado em: %s", time.Now().Format("02/01/2006 15:04:05")))
+
+	return pdf.OutputFileAndClose(filename)
+}
 
+// writeMissingRates warns that amounts in currencies without an exchange rate are left out of the totals
+func (pes *PDFExportService) writeMissingRates(pdf *gofpdf.Fpdf) {
+	missing := pes.financeService.MissingRates(time.Now())
+	if len(missing) == 0 {
+		return
+	}
+	pdf.SetFont("Arial", "B", 10)
+	pdf.SetTextColor(200, 0, 0)
+	pdf.Cell(190, 6, "Valores sem cotação ficaram fora dos totais:")
+	pdf.Ln(6)
+	pdf.SetFont("Arial", "", 10)
+	for _, rate := range missing {
+		pdf.Cell(190, 6, rate.Label())
+		pdf.Ln(6)
+	}
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(2)
+}
+
+// writeCommittedSummary writes realized spending apart from the installments still to come,
+// when the report has any
+func (pes *PDFExportService) writeCommittedSummary(pdf *gofpdf.Fpdf, report Report) {
+	if report.Committed.Count == 0 {
+		return
+	}
+	pdf.Cell(190, 6, fmt.Sprintf("Despesas Realizadas: %s", report.Realized.Expense))
+	pdf.Ln(6)
+	pdf.Cell(190, 6, fmt.Sprintf("Parcelas a Vencer: %s (%d parcelas)", report.Committed.Expense, report.Committed.Count))
+	pdf.Ln(6)
+}
+
+// writeCommittedSection lists the installments still to come, when the report has any
+func (pes *PDFExportService) writeCommittedSection(pdf *gofpdf.Fpdf, report Report) {
+	if len(report.CommittedInstallments) == 0 {
+		return
+	}
+	pdf.Ln(6)
+	pes.writeTransactionTable(pdf, "Parcelas a Vencer", report.CommittedInstallments)
+}
+
+// writeTransactionTable writes one row per transaction with its value in the base currency
+func (pes *PDFExportService) writeTransactionTable(pdf *gofpdf.Fpdf, title string, transactions []models.Transaction) {
 	pdf.SetFont("Arial", "B", 12)
-	pdf.Cell(190, 8, "Transações")
+	pdf.Cell(190, 8, title)
 	pdf.Ln(10)
 
 	pdf.SetFont("Arial", "B", 9)
-	headers := []string{"Data", "Tipo", "Valor", "Descrição", "Categoria"}
-	widths := []float64{25, 20, 25, 70, 30}
+	headers := []string{"Data", "Tipo", "Valor", "Valor Base", "Descrição", "Categoria"}
+	widths := []float64{22, 22, 28, 28, 60, 30}
 
 	for i, header := range headers {
 		pdf.CellFormat(widths[i], 7, header, "1", 0, "", false, 0, "")
@@ -70,25 +292,27 @@ func (pes *PDFExportService) ExportToPDF(filename string) error {
 
 	pdf.SetFont("Arial", "", 8)
 	for _, tx := range transactions {
-		amount := tx.Value
-		if tx.Type == "Despesa" {
-			amount = -amount
-		}
-
 		pdf.CellFormat(widths[0], 6, tx.Date.Format("02/01/2006"), "1", 0, "", false, 0, "")
-		pdf.CellFormat(widths[1], 6, tx.Type, "1", 0, "", false, 0, "")
-		pdf.CellFormat(widths[2], 6, fmt.Sprintf("R$ %.2f", amount), "1", 0, "", false, 0, "")
-		pdf.CellFormat(widths[3], 6, tx.Description, "1", 0, "", false, 0, "")
-		pdf.CellFormat(widths[4], 6, tx.Category, "1", 0, "", false, 0, "")
+		pdf.CellFormat(widths[1], 6, string(tx.Type), "1", 0, "", false, 0, "")
+		pdf.CellFormat(widths[2], 6, tx.Value.String(), "1", 0, "", false, 0, "")
+		base := "sem cotação"
+		if value, err := pes.financeService.ValueInBase(tx); err == nil {
+			base = value.String()
+		}
+		pdf.CellFormat(widths[3], 6, base, "1", 0, "", false, 0, "")
+		pdf.CellFormat(widths[4], 6, tx.Description, "1", 0, "", false, 0, "")
+		pdf.CellFormat(widths[5], 6, tx.CategoryLabel(), "1", 0, "", false, 0, "")
 		pdf.Ln(-1)
 	}
+}
 
+// writeCategorySection writes the category hierarchy down to the configured depth
+func (pes *PDFExportService) writeCategorySection(pdf *gofpdf.Fpdf, tree []*models.CategoryNode) {
 	pdf.Ln(10)
 	pdf.SetFont("Arial", "B", 12)
 	pdf.Cell(190, 8, "Resumo por Categoria")
 	pdf.Ln(10)
 
-	categories := pes.financeService.GetTransactionList().GetCategories()
 	pdf.SetFont("Arial", "B", 9)
 	pdf.Cell(60, 7, "Categoria")
 	pdf.Cell(40, 7, "Receitas")
@@ -96,107 +320,201 @@ func (pes *PDFExportService) ExportToPDF(filename string) error {
 	pdf.Cell(40, 7, "Saldo")
 	pdf.Ln(-1)
 
+	// Totals of each category include its subcategories; deeper levels are indented
 	pdf.SetFont("Arial", "", 8)
-	for _, category := range categories {
-		categoryTxs := pes.financeService.GetTransactionList().GetTransactionsByCategory(category)
-		categoryIncome := 0.0
-		categoryExpenses := 0.0
-
-		for _, tx := range categoryTxs {
-			if tx.Type == "Receita" {
-				categoryIncome += tx.Value
-			} else {
-				categoryExpenses += tx.Value
-			}
+	for _, node := range models.FlattenCategoryTree(tree, pes.categoryDepth) {
+		name := node.Name
+		if name == "" {
+			name = "Sem categoria"
 		}
 
-		categoryBalance := categoryIncome - categoryExpenses
-
-		pdf.Cell(60, 6, category)
-		pdf.Cell(40, 6, fmt.Sprintf("R$ %.2f", categoryIncome))
-		pdf.Cell(40, 6, fmt.Sprintf("R$ %.2f", categoryExpenses))
-		pdf.Cell(40, 6, fmt.Sprintf("R$ %.2f", categoryBalance))
+		pdf.Cell(60, 6, strings.Repeat("    ", node.Depth)+name)
+		pdf.Cell(40, 6, node.Income.String())
+		pdf.Cell(40, 6, node.Expense.String())
+		pdf.Cell(40, 6, node.Balance().String())
 		pdf.Ln(-1)
 	}
-
-	pdf.Ln(10)
-	pdf.SetFont("Arial", "I", 8)
-	pdf.Cell(190, 6, fmt.Sprintf("Relatório gerado em: %s", time.Now().Format("02/01/2006 15:04:05")))
-
-	return pdf.OutputFileAndClose(filename)
 }
 
-// ExportMonthlyReport exports a monthly report to PDF
-func (pes *PDFExportService) ExportMonthlyReport(filename string, year int, month time.Month) error {
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
+// writePeriodSeries writes income, expense and the running balance of consecutive periods
+func (pes *PDFExportService) writePeriodSeries(pdf *gofpdf.Fpdf, title string, series []PeriodTotal) {
+	if len(series) == 0 {
+		return
+	}
 
-	pdf.SetFont("Arial", "B", 16)
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(190, 8, title)
+	pdf.Ln(10)
 
-	pdf.Cell(190, 10, fmt.Sprintf("Relatório Mensal - %s/%d", month.String(), year))
-	pdf.Ln(15)
+	pdf.SetFont("Arial", "B", 9)
+	pdf.Cell(50, 7, "Período")
+	pdf.Cell(35, 7, "Receitas")
+	pdf.Cell(35, 7, "Despesas")
+	pdf.Cell(35, 7, "Saldo")
+	pdf.Cell(35, 7, "Acumulado")
+	pdf.Ln(-1)
 
-	startDate := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.AddDate(0, 1, -1)
+	pdf.SetFont("Arial", "", 8)
+	for _, total := range series {
+		pdf.Cell(50, 6, total.Period.Label())
+		pdf.Cell(35, 6, total.Totals.Income.String())
+		pdf.Cell(35, 6, total.Totals.Expense.String())
+		pdf.Cell(35, 6, total.Totals.Balance().String())
+		pdf.Cell(35, 6, total.Closing.String())
+		pdf.Ln(-1)
+	}
+	pdf.Ln(6)
+}
 
-	monthlyTransactions := pes.financeService.GetTransactionList().GetTransactionsByDateRange(startDate, endDate)
+// writeForecastSection writes the forecast summary and the days with scheduled items,
+// highlighting negative balances in red
+func (pes *PDFExportService) writeForecastSection(pdf *gofpdf.Fpdf, forecast Forecast) {
+	if len(forecast.Days) == 0 {
+		return
+	}
 
+	pdf.Ln(10)
 	pdf.SetFont("Arial", "B", 12)
-	pdf.Cell(190, 8, "Resumo Mensal")
+	pdf.Cell(190, 8, fmt.Sprintf("Previsão de Caixa - %d dias", len(forecast.Days)))
 	pdf.Ln(10)
 
 	pdf.SetFont("Arial", "", 10)
-	monthlyIncome := 0.0
-	monthlyExpenses := 0.0
-
-	for _, tx := range monthlyTransactions {
-		if tx.Type == "Receita" {
-			monthlyIncome += tx.Value
-		} else {
-			monthlyExpenses += tx.Value
+	pdf.Cell(190, 6, fmt.Sprintf("Saldo Atual: %s", forecast.StartBalance))
+	pdf.Ln(6)
+	pdf.Cell(190, 6, fmt.Sprintf("Gasto Variável Estimado por Dia: %s", forecast.DailyVariable.Abs()))
+	pdf.Ln(6)
+	pdf.Cell(190, 6, fmt.Sprintf("Menor Saldo: %s em %s", forecast.Lowest.Balance, forecast.Lowest.Date.Format("02/01/2006")))
+	pdf.Ln(6)
+	if forecast.FirstNegative >= 0 {
+		pdf.SetTextColor(200, 0, 0)
+		pdf.Cell(190, 6, fmt.Sprintf("Saldo negativo a partir de %s (próximo pagamento em %s)",
+			forecast.Days[forecast.FirstNegative].Date.Format("02/01/2006"), forecast.NextPayday.Format("02/01/2006")))
+		pdf.SetTextColor(0, 0, 0)
+		pdf.Ln(6)
+	}
+	pdf.Ln(4)
+
+	pdf.SetFont("Arial", "B", 9)
+	pdf.Cell(25, 7, "Data")
+	pdf.Cell(95, 7, "Lançamentos Previstos")
+	pdf.Cell(35, 7, "Previsto")
+	pdf.Cell(35, 7, "Saldo")
+	pdf.Ln(-1)
+
+	pdf.SetFont("Arial", "", 8)
+	last := len(forecast.Days) - 1
+	for i, day := range forecast.Days {
+		if len(day.Items) == 0 && i != last {
+			continue
+		}
+		var descriptions []string
+		for _, item := range day.Items {
+			descriptions = append(descriptions, item.Description)
+		}
+		if day.Balance.IsNegative() {
+			pdf.SetTextColor(200, 0, 0)
 		}
+		pdf.Cell(25, 6, day.Date.Format("02/01/2006"))
+		pdf.Cell(95, 6, strings.Join(descriptions, ", "))
+		pdf.Cell(35, 6, day.Scheduled.String())
+		pdf.Cell(35, 6, day.Balance.String())
+		pdf.Ln(-1)
+		pdf.SetTextColor(0, 0, 0)
 	}
+}
 
-	monthlyBalance := monthlyIncome - monthlyExpenses
+// writeGoalSection writes the progress of every savings goal, highlighting overdue goals in red
+func (pes *PDFExportService) writeGoalSection(pdf *gofpdf.Fpdf, now time.Time) {
+	statuses := pes.financeService.GetGoalStatus(now)
+	if len(statuses) == 0 {
+		return
+	}
 
-	pdf.Cell(190, 6, fmt.Sprintf("Receitas do Mês: R$ %.2f", monthlyIncome))
-	pdf.Ln(6)
-	pdf.Cell(190, 6, fmt.Sprintf("Despesas do Mês: R$ %.2f", monthlyExpenses))
-	pdf.Ln(6)
-	pdf.Cell(190, 6, fmt.Sprintf("Saldo do Mês: R$ %.2f", monthlyBalance))
 	pdf.Ln(10)
-
 	pdf.SetFont("Arial", "B", 12)
-	pdf.Cell(190, 8, "Transações do Mês")
+	pdf.Cell(190, 8, "Metas de Economia")
 	pdf.Ln(10)
 
 	pdf.SetFont("Arial", "B", 9)
-	headers := []string{"Data", "Tipo", "Valor", "Descrição", "Categoria"}
-	widths := []float64{25, 20, 25, 70, 30}
+	pdf.Cell(50, 7, "Meta")
+	pdf.Cell(25, 7, "Prazo")
+	pdf.Cell(40, 7, "Guardado")
+	pdf.Cell(35, 7, "Objetivo")
+	pdf.Cell(40, 7, "Por Mês")
+	pdf.Ln(-1)
 
-	for i, header := range headers {
-		pdf.CellFormat(widths[i], 7, header, "1", 0, "", false, 0, "")
+	pdf.SetFont("Arial", "", 8)
+	for _, status := range statuses {
+		if status.Overdue() {
+			pdf.SetTextColor(200, 0, 0)
+		}
+		pdf.Cell(50, 6, status.Goal.Name)
+		pdf.Cell(25, 6, status.Goal.Deadline.Format("02/01/2006"))
+		pdf.Cell(40, 6, fmt.Sprintf("%s (%.0f%%)", status.Saved, status.Percent()))
+		pdf.Cell(35, 6, status.Goal.Target.String())
+		pdf.Cell(40, 6, status.MonthlyNeeded.String())
+		pdf.Ln(-1)
+		pdf.SetTextColor(0, 0, 0)
 	}
+}
+
+// writeBudgetSection writes budget vs. actual for a month, highlighting overruns in red
+func (pes *PDFExportService) writeBudgetSection(pdf *gofpdf.Fpdf, year int, month time.Month) {
+	statuses := pes.financeService.GetBudgetStatus(year, month)
+	if len(statuses) == 0 {
+		return
+	}
+
+	pdf.Ln(10)
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(190, 8, fmt.Sprintf("Orçamentos - %02d/%d", int(month), year))
+	pdf.Ln(10)
+
+	pdf.SetFont("Arial", "B", 9)
+	pdf.Cell(60, 7, "Categoria")
+	pdf.Cell(40, 7, "Orçado")
+	pdf.Cell(40, 7, "Realizado")
+	pdf.Cell(40, 7, "Restante")
 	pdf.Ln(-1)
 
 	pdf.SetFont("Arial", "", 8)
-	for _, tx := range monthlyTransactions {
-		amount := tx.Value
-		if tx.Type == "Despesa" {
-			amount = -amount
+	for _, status := range statuses {
+		if status.OverBudget() {
+			pdf.SetTextColor(200, 0, 0)
 		}
-
-		pdf.CellFormat(widths[0], 6, tx.Date.Format("02/01/2006"), "1", 0, "", false, 0, "")
-		pdf.CellFormat(widths[1], 6, tx.Type, "1", 0, "", false, 0, "")
-		pdf.CellFormat(widths[2], 6, fmt.Sprintf("R$ %.2f", amount), "1", 0, "", false, 0, "")
-		pdf.CellFormat(widths[3], 6, tx.Description, "1", 0, "", false, 0, "")
-		pdf.CellFormat(widths[4], 6, tx.Category, "1", 0, "", false, 0, "")
+		pdf.Cell(60, 6, status.Budget.Category)
+		pdf.Cell(40, 6, status.Budget.Limit.String())
+		pdf.Cell(40, 6, fmt.Sprintf("%s (%.0f%%)", status.Actual, status.UsedPercent()))
+		pdf.Cell(40, 6, status.Remaining.String())
 		pdf.Ln(-1)
+		pdf.SetTextColor(0, 0, 0)
+	}
+}
+
+// writeTagSection writes income, expense and balance per tag
+func (pes *PDFExportService) writeTagSection(pdf *gofpdf.Fpdf, totals []models.TagTotal) {
+	if len(totals) == 0 {
+		return
 	}
 
 	pdf.Ln(10)
-	pdf.SetFont("Arial", "I", 8)
-	pdf.Cell(190, 6, fmt.Sprintf("Relatório gerado em: %s", time.Now().Format("02/01/2006 15:04:05")))
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(190, 8, "Resumo por Tag")
+	pdf.Ln(10)
 
-	return pdf.OutputFileAndClose(filename)
+	pdf.SetFont("Arial", "B", 9)
+	pdf.Cell(60, 7, "Tag")
+	pdf.Cell(40, 7, "Receitas")
+	pdf.Cell(40, 7, "Despesas")
+	pdf.Cell(40, 7, "Saldo")
+	pdf.Ln(-1)
+
+	pdf.SetFont("Arial", "", 8)
+	for _, total := range totals {
+		pdf.Cell(60, 6, fmt.Sprintf("%s (%d)", total.Tag, total.Count))
+		pdf.Cell(40, 6, total.Income.String())
+		pdf.Cell(40, 6, total.Expense.String())
+		pdf.Cell(40, 6, total.Balance().String())
+		pdf.Ln(-1)
+	}
 }
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"finance_go/models"
+)
+
+// RecurrenceService handles recurring transaction rules and their materialization
+type RecurrenceService struct {
+	financeService *FinanceService
+}
+
+// NewRecurrenceService creates a new recurrence service
+func NewRecurrenceService(financeService *FinanceService) *RecurrenceService {
+	return &RecurrenceService{
+		financeService: financeService,
+	}
+}
+
+// AddRule stores a new recurrence rule
+func (rs *RecurrenceService) AddRule(rule models.RecurrenceRule) (models.RecurrenceRule, error) {
+	rule, err := rs.financeService.GetTransactionList().AddRecurrenceRule(rule)
+	if err != nil {
+		return models.RecurrenceRule{}, fmt.Errorf("error adding recurrence rule: %w", err)
+	}
+	return rule, nil
+}
+
+// GetRules returns all recurrence rules
+func (rs *RecurrenceService) GetRules() []models.RecurrenceRule {
+	return rs.financeService.GetTransactionList().GetRecurrenceRules()
+}
+
+// DeleteRule removes a recurrence rule, keeping the transactions it already generated
+func (rs *RecurrenceService) DeleteRule(id int) error {
+	if err := rs.financeService.GetTransactionList().DeleteRecurrenceRule(id); err != nil {
+		return fmt.Errorf("error deleting recurrence rule %d: %w", id, err)
+	}
+	return nil
+}
+
+// SkipOccurrence prevents a single future occurrence from being generated
+func (rs *RecurrenceService) SkipOccurrence(ruleID int, date time.Time) error {
+	exception := models.RecurrenceException{Date: date, Skip: true}
+	if err := rs.financeService.GetTransactionList().SetRecurrenceException(ruleID, exception); err != nil {
+		return fmt.Errorf("error skipping occurrence: %w", err)
+	}
+	return nil
+}
+
+// ChangeOccurrence changes the value and description of a single future occurrence
+func (rs *RecurrenceService) ChangeOccurrence(ruleID int, date time.Time, value models.Money, description string) error {
+	exception := models.RecurrenceException{Date: date, Value: &value, Description: description}
+	if err := rs.financeService.GetTransactionList().SetRecurrenceException(ruleID, exception); err != nil {
+		return fmt.Errorf("error changing occurrence: %w", err)
+	}
+	return nil
+}
+
+// GenerateDue creates the transactions of every occurrence due up to now.
+// It is safe to call repeatedly: an occurrence is never generated twice.
+func (rs *RecurrenceService) GenerateDue(now time.Time) []models.Transaction {
+	defer rs.financeService.transactionsChanged()
+	return rs.financeService.GetTransactionList().MaterializeRecurring(now)
+}
+
+// UpcomingOccurrences returns the not yet generated occurrence dates of a rule up to until
+func (rs *RecurrenceService) UpcomingOccurrences(ruleID int, until time.Time) ([]time.Time, error) {
+	for _, rule := range rs.GetRules() {
+		if rule.ID != ruleID {
+			continue
+		}
+		var upcoming []time.Time
+		for _, date := range rule.OccurrencesUntil(until) {
+			if date.After(rule.GeneratedThrough) {
+				upcoming = append(upcoming, date)
+			}
+		}
+		return upcoming, nil
+	}
+	return nil, fmt.Errorf("error listing occurrences of rule %d: %w", ruleID, models.ErrRecurrenceRuleNotFound)
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"finance_go/models"
+)
+
+// ReportService computes the aggregates rendered by the PDF reports, the UI and exporters
+type ReportService struct {
+	financeService *FinanceService
+}
+
+// NewReportService creates a new report service
+func NewReportService(financeService *FinanceService) *ReportService {
+	return &ReportService{
+		financeService: financeService,
+	}
+}
+
+// TypeTotal is the number and sum of transactions of one type in the base currency
+type TypeTotal struct {
+	Type  models.TransactionType
+	Count int
+	Total models.Money
+}
+
+// PeriodTotal holds the totals of one period of a time series along with the running balance.
+// Net includes transfers, so Closing follows the balance of the selected transactions.
+type PeriodTotal struct {
+	Period  models.Period
+	Totals  models.QuerySummary
+	Net     models.Money
+	Closing models.Money
+}
+
+// Report holds every aggregate of the transactions matching a query
+type Report struct {
+	Query        models.Query
+	Transactions []models.Transaction
+	Totals       models.QuerySummary
+	ByType       []TypeTotal
+	Categories   []*models.CategoryNode
+	Tags         []models.TagTotal
+	// Opening and Closing are the balances before and after the query's period
+	Opening models.Money
+	Closing models.Money
+	// Realized covers the transactions already due; Committed the installments of purchases
+	// still to come, which are also part of Totals
+	Realized  models.QuerySummary
+	Committed models.QuerySummary
+	// CommittedInstallments are the installments still to come, ordered by date
+	CommittedInstallments []models.Transaction
+}
+
+// Build computes the report of the transactions matching a query
+func (rs *ReportService) Build(query models.Query) Report {
+	tl := rs.financeService.GetTransactionList()
+	transactions := tl.Query(query)
+	base := tl.GetBaseCurrency()
+
+	opening := rs.openingBalance(query)
+	closing := opening
+	for _, tx := range transactions {
+		closing = closing.Add(tl.BaseValue(tx))
+	}
+
+	realized, committed := rs.splitCommitted(transactions, time.Now())
+
+	return Report{
+		Query:                 query,
+		Transactions:          transactions,
+		Totals:                tl.Summarize(transactions),
+		ByType:                rs.totalsByType(transactions),
+		Categories:            models.BuildCategoryTree(transactions, tl.BaseAmount, base),
+		Tags:                  models.BuildTagTotals(transactions, tl.BaseAmount, base),
+		Opening:               opening,
+		Closing:               closing,
+		Realized:              tl.Summarize(realized),
+		Committed:             tl.Summarize(committed),
+		CommittedInstallments: committed,
+	}
+}
+
+// splitCommitted separates the installments still to come at now from the other transactions,
+// returning the committed ones ordered by date
+func (rs *ReportService) splitCommitted(transactions []models.Transaction, now time.Time) (realized, committed []models.Transaction) {
+	for _, tx := range transactions {
+		if tx.IsCommitted(now) {
+			committed = append(committed, tx)
+		} else {
+			realized = append(realized, tx)
+		}
+	}
+	sort.SliceStable(committed, func(i, j int) bool {
+		return committed[i].Date.Before(committed[j].Date)
+	})
+	return realized, committed
+}
+
+// Totals returns the count, income and expense of the transactions matching a query
+func (rs *ReportService) Totals(query models.Query) models.QuerySummary {
+	tl := rs.financeService.GetTransactionList()
+	return tl.Summarize(tl.Query(query))
+}
+
+// TotalsByType returns the totals of each transaction type matching a query
+func (rs *ReportService) TotalsByType(query models.Query) []TypeTotal {
+	return rs.totalsByType(rs.financeService.QueryTransactions(query))
+}
+
+// TotalsByPeriod splits the transactions matching a query into consecutive periods of a kind,
+// from the start of the query's period (or the first transaction) to its end (or the last
+// transaction). Periods without transactions are included so the series has no gaps.
+func (rs *ReportService) TotalsByPeriod(query models.Query, kind models.PeriodKind) []PeriodTotal {
+	tl := rs.financeService.GetTransactionList()
+	query.SortBy, query.Descending = models.SortByDate, false
+	transactions := tl.Query(query)
+	if len(transactions) == 0 && (query.Period.Start.IsZero() || query.Period.End.IsZero()) {
+		return nil
+	}
+
+	first, last := query.Period.Start, query.Period.End.Add(-time.Nanosecond)
+	if query.Period.Start.IsZero() {
+		first = localWallClock(transactions[0].Date)
+	}
+	if query.Period.End.IsZero() {
+		last = localWallClock(transactions[len(transactions)-1].Date)
+	}
+
+	// Group transactions by the start of the period that contains them
+	buckets := make(map[int64][]models.Transaction)
+	for _, tx := range transactions {
+		period := tl.PeriodContaining(kind, localWallClock(tx.Date))
+		buckets[period.Start.Unix()] = append(buckets[period.Start.Unix()], tx)
+	}
+
+	balance := rs.openingBalance(query)
+	var series []PeriodTotal
+	for period := tl.PeriodContaining(kind, first); !period.Start.After(last); period = period.Next() {
+		bucket := buckets[period.Start.Unix()]
+		net := models.NewMoney(0, tl.GetBaseCurrency())
+		for _, tx := range bucket {
+			net = net.Add(tl.BaseValue(tx))
+		}
+		balance = balance.Add(net)
+		series = append(series, PeriodTotal{
+			Period:  period,
+			Totals:  tl.Summarize(bucket),
+			Net:     net,
+			Closing: balance,
+		})
+	}
+	return series
+}
+
+// openingBalance sums the transactions matching the query's filters dated before its period
+func (rs *ReportService) openingBalance(query models.Query) models.Money {
+	tl := rs.financeService.GetTransactionList()
+	opening := models.NewMoney(0, tl.GetBaseCurrency())
+	if query.Period.Start.IsZero() {
+		return opening
+	}
+
+	query.Period = models.CustomPeriod(time.Time{}, query.Period.Start)
+	query.Limit, query.Offset = 0, 0
+	for _, tx := range tl.Query(query) {
+		opening = opening.Add(tl.BaseValue(tx))
+	}
+	return opening
+}
+
+func (rs *ReportService) totalsByType(transactions []models.Transaction) []TypeTotal {
+	tl := rs.financeService.GetTransactionList()
+	types := []models.TransactionType{models.TransactionTypeIncome, models.TransactionTypeExpense, models.TransactionTypeTransfer}
+	totals := make([]TypeTotal, len(types))
+	for i, transactionType := range types {
+		totals[i] = TypeTotal{Type: transactionType, Total: models.NewMoney(0, tl.GetBaseCurrency())}
+	}
+
+	for _, tx := range transactions {
+		for i := range totals {
+			if totals[i].Type == tx.Type {
+				totals[i].Count++
+				totals[i].Total = totals[i].Total.Add(tl.BaseValue(tx))
+			}
+		}
+	}
+	return totals
+}
+
+// localWallClock reads the date and time shown by t as local time, matching how periods compare dates
+func localWallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"finance_go/models"
+)
+
+// newReportService returns a report service over 1000.00 of income in January, nothing in
+// February and 250.00 of expenses in March 2026
+func newReportService(t *testing.T) *ReportService {
+	t.Helper()
+	fs := NewFinanceService()
+	for _, tx := range []models.Transaction{
+		models.NewTransactionWithDate(models.TransactionTypeIncome, models.NewMoney(100000, "BRL"), "Salário", "Salário", date(2026, 1, 5)),
+		models.NewTransactionWithDate(models.TransactionTypeExpense, models.NewMoney(20000, "BRL"), "Mercado", "Alimentação", date(2026, 3, 5)),
+		models.NewTransactionWithDate(models.TransactionTypeExpense, models.NewMoney(5000, "BRL"), "Padaria", "Alimentação > Padaria", date(2026, 3, 20)),
+	} {
+		if err := fs.AddTransactionFromModel(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewReportService(fs)
+}
+
+func TestBuildReport(t *testing.T) {
+	rs := newReportService(t)
+	report := rs.Build(models.Query{Period: models.MonthPeriod(2026, time.March, time.Local)})
+
+	if report.Totals.Count != 2 || report.Totals.Expense.Amount != 25000 || !report.Totals.Income.IsZero() {
+		t.Errorf("totals = %+v", report.Totals)
+	}
+	// Opening follows the transactions before the period
+	if report.Opening.Amount != 100000 || report.Closing.Amount != 75000 {
+		t.Errorf("opening %v, closing %v; want 1000.00 and 750.00", report.Opening, report.Closing)
+	}
+	if expense := report.ByType[1]; expense.Type != models.TransactionTypeExpense || expense.Count != 2 || expense.Total.Amount != -25000 {
+		t.Errorf("expense total = %+v", expense)
+	}
+	if len(report.Categories) != 1 || report.Categories[0].Path != "Alimentação" || report.Categories[0].Expense.Amount != 25000 {
+		t.Errorf("categories = %+v", report.Categories)
+	}
+	if report.Realized.Count != 2 || report.Committed.Count != 0 {
+		t.Errorf("realized %+v, committed %+v", report.Realized, report.Committed)
+	}
+}
+
+func TestTotalsByPeriod(t *testing.T) {
+	rs := newReportService(t)
+	series := rs.TotalsByPeriod(models.Query{}, models.PeriodMonth)
+
+	// February has no transactions but is part of the series
+	want := []struct {
+		month   time.Month
+		count   int
+		closing int64
+	}{
+		{time.January, 1, 100000},
+		{time.February, 0, 100000},
+		{time.March, 2, 75000},
+	}
+	if len(series) != len(want) {
+		t.Fatalf("%d periods, want %d", len(series), len(want))
+	}
+	for i, w := range want {
+		if series[i].Period.Start.Month() != w.month || series[i].Totals.Count != w.count || series[i].Closing.Amount != w.closing {
+			t.Errorf("period %d = %s with %d transactions closing at %v", i, series[i].Period.Label(), series[i].Totals.Count, series[i].Closing)
+		}
+	}
+
+	// A period wider than the transactions covers it all, starting from the opening balance
+	spring := rs.TotalsByPeriod(models.Query{Period: models.CustomPeriod(time.Date(2026, 2, 1, 0, 0, 0, 0, time.Local), time.Date(2026, 5, 1, 0, 0, 0, 0, time.Local))}, models.PeriodMonth)
+	if len(spring) != 3 || spring[0].Closing.Amount != 100000 || spring[2].Closing.Amount != 75000 {
+		t.Errorf("February to April = %+v", spring)
+	}
+	if empty := NewReportService(NewFinanceService()).TotalsByPeriod(models.Query{}, models.PeriodMonth); empty != nil {
+		t.Errorf("series of an empty ledger = %+v", empty)
+	}
+}
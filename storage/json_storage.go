@@ -47,9 +47,11 @@ func (js *JSONStorage) Load() (*models.TransactionList, error) {
 	if err != nil {
 		if os.IsNotExist(err) {
 			// Return empty transaction list if file doesn't exist
-			return &models.TransactionList{
+			transactionList := &models.TransactionList{
 				Transactions: make([]models.Transaction, 0),
-			}, nil
+			}
+			transactionList.EnsureAccounts()
+			return transactionList, nil
 		}
 		return nil, fmt.Errorf("error reading file: %w", err)
 	}
@@ -60,5 +62,18 @@ func (js *JSONStorage) Load() (*models.TransactionList, error) {
 		return nil, fmt.Errorf("error unmarshaling transactions: %w", err)
 	}
 
+	// Older files may contain missing or duplicated IDs, inconsistent signs or no accounts;
+	// repair them once and persist the result
+	repairedIDs := transactionList.EnsureUniqueIDs()
+	repairedSigns := transactionList.NormalizeSigns()
+	assignedAccounts := transactionList.EnsureAccounts()
+	if repairedIDs > 0 || repairedSigns > 0 || assignedAccounts > 0 {
+		fmt.Printf("Repaired %d transaction IDs, %d transaction signs and %d transaction accounts\n",
+			repairedIDs, repairedSigns, assignedAccounts)
+		if err := js.Save(&transactionList); err != nil {
+			return nil, fmt.Errorf("error saving repaired transactions: %w", err)
+		}
+	}
+
 	return &transactionList, nil
 }
@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"finance_go/models"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// allAccountsOption is the account filter entry that shows every transaction
+const allAccountsOption = "Todas"
+
+// showNewAccountDialog opens a dialog to create a new account
+func (mw *MainWindow) showNewAccountDialog() {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Nome da conta")
+
+	// Credit cards also need the statement closing and due days
+	closingDayEntry := widget.NewEntry()
+	closingDayEntry.SetPlaceHolder("Dia do fechamento")
+	dueDayEntry := widget.NewEntry()
+	dueDayEntry.SetPlaceHolder("Dia do vencimento")
+	cardDays := container.NewGridWithColumns(2, closingDayEntry, dueDayEntry)
+	cardDays.Hide()
+
+	typeSelect := widget.NewSelect(models.AccountTypeNames(), func(selected string) {
+		if models.AccountType(selected) == models.AccountTypeCreditCard {
+			cardDays.Show()
+		} else {
+			cardDays.Hide()
+		}
+	})
+	typeSelect.SetSelected(string(models.AccountTypeChecking))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Nome", nameEntry),
+		widget.NewFormItem("Tipo", typeSelect),
+		widget.NewFormItem("Fatura", cardDays),
+	}
+
+	dialog.ShowForm("Nova Conta", "Criar", "Cancelar", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		var err error
+		if models.AccountType(typeSelect.Selected) == models.AccountTypeCreditCard {
+			closingDay, closingErr := strconv.Atoi(strings.TrimSpace(closingDayEntry.Text))
+			dueDay, dueErr := strconv.Atoi(strings.TrimSpace(dueDayEntry.Text))
+			if closingErr != nil || dueErr != nil {
+				dialog.ShowError(fmt.Errorf("informe os dias de fechamento e vencimento da fatura"), mw.window)
+				return
+			}
+			_, err = mw.financeService.AddCreditCard(nameEntry.Text, closingDay, dueDay)
+		} else {
+			_, err = mw.financeService.AddAccount(nameEntry.Text, models.AccountType(typeSelect.Selected))
+		}
+		if err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+
+		mw.refreshAccountOptions()
+		mw.Refresh()
+	}, mw.window)
+}
+
+// showTransferDialog opens a dialog to move money between two accounts
+func (mw *MainWindow) showTransferDialog() {
+	// The amount is in the currency of the source account, which the user may still change
+	currencySelect := widget.NewSelect(models.SupportedCurrencies(), func(string) {})
+	fromSelect := widget.NewSelect(mw.accountNames(), func(selected string) {
+		currencySelect.SetSelected(mw.financeService.GetTransactionList().AccountCurrency(mw.accountIDByName(selected)))
+	})
+	fromSelect.SetSelected(mw.accountSelect.Selected)
+
+	toSelect := widget.NewSelect(mw.accountNames(), func(string) {})
+
+	amountEntry := widget.NewEntry()
+	amountEntry.SetPlaceHolder("Valor")
+
+	descriptionEntry := widget.NewEntry()
+	descriptionEntry.SetPlaceHolder("Descrição")
+
+	dateEntry := widget.NewEntry()
+	dateEntry.SetText(time.Now().Format("02/01/2006"))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("De", fromSelect),
+		widget.NewFormItem("Para", toSelect),
+		widget.NewFormItem("Valor", container.NewBorder(nil, nil, nil, currencySelect, amountEntry)),
+		widget.NewFormItem("Descrição", descriptionEntry),
+		widget.NewFormItem("Data", dateEntry),
+	}
+
+	dialog.ShowForm("Transferência", "Transferir", "Cancelar", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		amount, err := models.ParseMoney(amountEntry.Text, currencySelect.Selected)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("valor inválido"), mw.window)
+			return
+		}
+
+		date, err := time.ParseInLocation("02/01/2006", strings.TrimSpace(dateEntry.Text), time.Local)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("data inválida, use DD/MM/AAAA"), mw.window)
+			return
+		}
+
+		err = mw.financeService.Transfer(mw.accountIDByName(fromSelect.Selected), mw.accountIDByName(toSelect.Selected),
+			amount, descriptionEntry.Text, date)
+		if err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+
+		mw.Refresh()
+	}, mw.window)
+}
+
+// refreshAccountOptions reloads the account names in the form and filter selects
+func (mw *MainWindow) refreshAccountOptions() {
+	names := mw.accountNames()
+	mw.accountSelect.SetOptions(names)
+	mw.accountFilter.SetOptions(append([]string{allAccountsOption}, names...))
+}
+
+// selectedAccountID returns the account chosen in the form, falling back to the default account
+func (mw *MainWindow) selectedAccountID() int {
+	if id := mw.accountIDByName(mw.accountSelect.Selected); id != 0 {
+		return id
+	}
+	return mw.financeService.GetTransactionList().DefaultAccountID()
+}
+
+func (mw *MainWindow) accountNames() []string {
+	var names []string
+	for _, account := range mw.financeService.GetAccounts() {
+		names = append(names, account.Name)
+	}
+	return names
+}
+
+func (mw *MainWindow) defaultAccountName() string {
+	return mw.accountName(mw.financeService.GetTransactionList().DefaultAccountID())
+}
+
+func (mw *MainWindow) accountName(accountID int) string {
+	account, err := mw.financeService.GetTransactionList().GetAccountByID(accountID)
+	if err != nil {
+		return ""
+	}
+	return account.Name
+}
+
+func (mw *MainWindow) accountIDByName(name string) int {
+	account, err := mw.financeService.GetTransactionList().GetAccountByName(name)
+	if err != nil {
+		return 0
+	}
+	return account.ID
+}
+
+// accountFormItem returns a select preset to the given account, for edit forms
+func (mw *MainWindow) accountFormItem(accountID int) (*widget.FormItem, *widget.Select) {
+	accountSelect := widget.NewSelect(mw.accountNames(), func(string) {})
+	accountSelect.SetSelected(mw.accountName(accountID))
+	return widget.NewFormItem("Conta", accountSelect), accountSelect
+}
@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"fmt"
+	"net/url"
+
+	"finance_go/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showAttachmentDialog lists the attachments of a transaction and lets the user add, open or remove them
+func (mw *MainWindow) showAttachmentDialog(id int) {
+	selected := -1
+
+	attachments := func() []models.Attachment {
+		list, err := mw.attachmentService.GetAttachments(id)
+		if err != nil {
+			return nil
+		}
+		return list
+	}
+
+	list := widget.NewList(
+		func() int {
+			return len(attachments())
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(item widget.ListItemID, o fyne.CanvasObject) {
+			current := attachments()
+			if item < len(current) {
+				o.(*widget.Label).SetText(fmt.Sprintf("%s (%s)", current[item].Name, current[item].AddedAt.Format("02/01/2006")))
+			}
+		},
+	)
+	list.OnSelected = func(item widget.ListItemID) {
+		selected = item
+	}
+
+	selectedAttachment := func() (models.Attachment, bool) {
+		current := attachments()
+		if selected < 0 || selected >= len(current) {
+			dialog.ShowInformation("Anexos", "Selecione um anexo na lista.", mw.window)
+			return models.Attachment{}, false
+		}
+		return current[selected], true
+	}
+
+	addButton := widget.NewButton("Adicionar", func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			if _, err := mw.attachmentService.Attach(id, reader.URI().Path()); err != nil {
+				dialog.ShowError(fmt.Errorf("erro ao anexar arquivo: %v", err), mw.window)
+				return
+			}
+			list.Refresh()
+		}, mw.window)
+	})
+	openButton := widget.NewButton("Abrir", func() {
+		attachment, ok := selectedAttachment()
+		if !ok {
+			return
+		}
+		if err := mw.openAttachment(attachment); err != nil {
+			dialog.ShowError(fmt.Errorf("erro ao abrir anexo: %v", err), mw.window)
+		}
+	})
+	removeButton := widget.NewButton("Remover", func() {
+		attachment, ok := selectedAttachment()
+		if !ok {
+			return
+		}
+		dialog.ShowConfirm("Remover Anexo", fmt.Sprintf("Remover o anexo %s?", attachment.Name), func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if err := mw.attachmentService.Remove(id, attachment.File); err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+			selected = -1
+			list.UnselectAll()
+			list.Refresh()
+		}, mw.window)
+	})
+
+	// Reconciled transactions keep their attachments
+	if tx, err := mw.financeService.GetTransactionByID(id); err == nil && tx.IsLocked() {
+		removeButton.Disable()
+	}
+
+	buttons := container.NewHBox(addButton, openButton, removeButton)
+	content := container.NewBorder(nil, buttons, nil, nil, list)
+
+	d := dialog.NewCustom("Anexos", "Fechar", content, mw.window)
+	d.SetOnClosed(mw.Refresh)
+	d.Resize(fyne.NewSize(500, 300))
+	d.Show()
+}
+
+// openAttachment opens the managed copy of an attachment with the system's default application
+func (mw *MainWindow) openAttachment(attachment models.Attachment) error {
+	path, err := mw.attachmentService.Path(attachment)
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(storage.NewFileURI(path).String())
+	if err != nil {
+		return err
+	}
+	return fyne.CurrentApp().OpenURL(u)
+}
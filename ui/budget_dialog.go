@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"finance_go/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showBudgetDialog shows budget vs. actual per category for a month, highlighting overruns
+func (mw *MainWindow) showBudgetDialog() {
+	now := time.Now()
+	year, month := now.Year(), now.Month()
+	var statuses []models.BudgetStatus
+
+	table := widget.NewTable(
+		func() (int, int) {
+			return len(statuses), 4
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.TableCellID, o fyne.CanvasObject) {
+			label := o.(*widget.Label)
+			if id.Row >= len(statuses) {
+				return
+			}
+			status := statuses[id.Row]
+			label.Importance = widget.MediumImportance
+			if status.OverBudget() {
+				label.Importance = widget.DangerImportance
+			}
+			switch id.Col {
+			case 0:
+				label.SetText(status.Budget.Category)
+			case 1:
+				label.SetText(status.Budget.Limit.String())
+			case 2:
+				label.SetText(fmt.Sprintf("%s (%.0f%%)", status.Actual, status.UsedPercent()))
+			case 3:
+				label.SetText(status.Remaining.String())
+			}
+		},
+	)
+	table.SetColumnWidth(0, 160)
+	table.SetColumnWidth(1, 120)
+	table.SetColumnWidth(2, 160)
+	table.SetColumnWidth(3, 120)
+
+	reload := func() {
+		statuses = mw.financeService.GetBudgetStatus(year, month)
+		table.Refresh()
+	}
+
+	monthEntry := widget.NewEntry()
+	monthEntry.SetText(fmt.Sprintf("%02d/%d", int(month), year))
+	showButton := widget.NewButton("Mostrar", func() {
+		parsed, err := time.Parse("01/2006", strings.TrimSpace(monthEntry.Text))
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("mês inválido, use MM/AAAA"), mw.window)
+			return
+		}
+		year, month = parsed.Year(), parsed.Month()
+		reload()
+	})
+
+	categoryEntry := widget.NewSelectEntry(mw.financeService.GetTransactionList().GetCategories())
+	categoryEntry.SetPlaceHolder("Categoria")
+	limitEntry := widget.NewEntry()
+	limitEntry.SetPlaceHolder("Limite")
+	setButton := widget.NewButton("Definir Orçamento", func() {
+		limit, err := models.ParseMoney(limitEntry.Text, mw.financeService.GetBaseCurrency())
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("valor inválido"), mw.window)
+			return
+		}
+		if _, err := mw.financeService.SetBudget(categoryEntry.Text, year, month, limit); err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		categoryEntry.SetText("")
+		limitEntry.SetText("")
+		reload()
+	})
+
+	table.OnSelected = func(id widget.TableCellID) {
+		table.UnselectAll()
+		if id.Row >= len(statuses) {
+			return
+		}
+		budget := statuses[id.Row].Budget
+		dialog.ShowConfirm("Excluir Orçamento", fmt.Sprintf("Excluir o orçamento de %s?", budget.Category), func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if err := mw.financeService.DeleteBudget(budget.ID); err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+			reload()
+		}, mw.window)
+	}
+
+	headers := container.NewGridWithColumns(4,
+		widget.NewLabel("Categoria"),
+		widget.NewLabel("Orçado"),
+		widget.NewLabel("Realizado"),
+		widget.NewLabel("Restante"),
+	)
+	top := container.NewVBox(
+		container.NewHBox(widget.NewLabel("Mês:"), monthEntry, showButton),
+		container.NewGridWithColumns(3, categoryEntry, limitEntry, setButton),
+		headers,
+	)
+	content := container.NewBorder(top, nil, nil, nil, table)
+
+	reload()
+	d := dialog.NewCustom("Orçamentos", "Fechar", content, mw.window)
+	d.Resize(fyne.NewSize(640, 480))
+	d.Show()
+}
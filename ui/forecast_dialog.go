@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+	"time"
+
+	"finance_go/models"
+	"finance_go/services"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// forecastHorizons are the forecast lengths offered, in days
+var forecastHorizons = []string{"30", "60", "90", "180"}
+
+// forecastChartSize is the size of the balance chart in the forecast dialog
+var forecastChartSize = fyne.NewSize(720, 180)
+
+// showForecastDialog projects the balance day by day and shows it as a chart and a table
+func (mw *MainWindow) showForecastDialog() {
+	var forecast services.Forecast
+
+	summaryLabel := widget.NewLabel("")
+	chart := container.NewWithoutLayout()
+
+	table := widget.NewTable(
+		func() (int, int) {
+			return len(forecast.Days) + 1, 4
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.TableCellID, o fyne.CanvasObject) {
+			label := o.(*widget.Label)
+			label.Importance = widget.MediumImportance
+			if id.Row == 0 {
+				label.SetText([]string{"Data", "Lançamentos Previstos", "Previsto", "Saldo"}[id.Col])
+				return
+			}
+			day := forecast.Days[id.Row-1]
+			if day.Balance.IsNegative() {
+				label.Importance = widget.DangerImportance
+			}
+			switch id.Col {
+			case 0:
+				label.SetText(day.Date.Format("02/01/2006"))
+			case 1:
+				var descriptions []string
+				for _, item := range day.Items {
+					descriptions = append(descriptions, item.Description)
+				}
+				label.SetText(strings.Join(descriptions, ", "))
+			case 2:
+				label.SetText(day.Scheduled.String())
+			case 3:
+				label.SetText(day.Balance.String())
+			}
+		},
+	)
+	table.SetColumnWidth(0, 100)
+	table.SetColumnWidth(1, 320)
+	table.SetColumnWidth(2, 130)
+	table.SetColumnWidth(3, 130)
+
+	includeInPDF := widget.NewCheck("Incluir no PDF", nil)
+
+	horizonSelect := widget.NewSelect(forecastHorizons, func(selected string) {
+		days, _ := strconv.Atoi(selected)
+		result, err := mw.forecastService.Forecast(time.Now(), days, services.DefaultForecastHistoryMonths)
+		if err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		forecast = result
+
+		summary := fmt.Sprintf("Saldo atual: %s | Gasto variável estimado: %s/dia | Menor saldo: %s em %s",
+			forecast.StartBalance, forecast.DailyVariable.Abs(), forecast.Lowest.Balance, forecast.Lowest.Date.Format("02/01/2006"))
+		if forecast.FirstNegative >= 0 {
+			summary += fmt.Sprintf("\nSaldo negativo a partir de %s (próximo pagamento em %s)",
+				forecast.Days[forecast.FirstNegative].Date.Format("02/01/2006"), forecast.NextPayday.Format("02/01/2006"))
+			summaryLabel.Importance = widget.DangerImportance
+		} else {
+			summaryLabel.Importance = widget.MediumImportance
+		}
+		summaryLabel.SetText(summary)
+
+		chart.Objects = forecastChart(forecast)
+		chart.Refresh()
+		table.Refresh()
+		if includeInPDF.Checked {
+			mw.pdfExportService.SetForecastDays(days)
+		}
+	})
+
+	includeInPDF.OnChanged = func(checked bool) {
+		days := 0
+		if checked {
+			days, _ = strconv.Atoi(horizonSelect.Selected)
+		}
+		mw.pdfExportService.SetForecastDays(days)
+	}
+	horizonSelect.SetSelected(forecastHorizons[0])
+
+	chartArea := container.NewGridWrap(forecastChartSize, chart)
+	header := container.NewVBox(
+		container.NewHBox(widget.NewLabel("Dias:"), horizonSelect, includeInPDF),
+		summaryLabel,
+		chartArea,
+	)
+	content := container.NewBorder(header, nil, nil, nil, table)
+
+	d := dialog.NewCustom("Previsão de Caixa", "Fechar", content, mw.window)
+	d.Resize(fyne.NewSize(780, 650))
+	d.Show()
+}
+
+// forecastChart draws the projected balance as a line, with the zero line in red when it is in range
+func forecastChart(forecast services.Forecast) []fyne.CanvasObject {
+	if len(forecast.Days) == 0 {
+		return nil
+	}
+
+	min, max := forecast.StartBalance.Amount, forecast.StartBalance.Amount
+	for _, day := range forecast.Days {
+		if day.Balance.Amount < min {
+			min = day.Balance.Amount
+		}
+		if day.Balance.Amount > max {
+			max = day.Balance.Amount
+		}
+	}
+	if min == max {
+		min, max = min-1, max+1
+	}
+
+	width, height := forecastChartSize.Width, forecastChartSize.Height
+	point := func(index int, amount int64) fyne.Position {
+		x := width * float32(index) / float32(len(forecast.Days))
+		y := height - height*float32(amount-min)/float32(max-min)
+		return fyne.NewPos(x, y)
+	}
+
+	var objects []fyne.CanvasObject
+	if min < 0 && max > 0 {
+		zero := canvas.NewLine(color.NRGBA{R: 200, A: 255})
+		zero.Position1 = point(0, 0)
+		zero.Position2 = fyne.NewPos(width, point(0, 0).Y)
+		objects = append(objects, zero)
+	}
+
+	previous := point(0, forecast.StartBalance.Amount)
+	for i, day := range forecast.Days {
+		current := point(i+1, day.Balance.Amount)
+		line := canvas.NewLine(color.NRGBA{R: 30, G: 120, B: 200, A: 255})
+		line.StrokeWidth = 2
+		line.Position1, line.Position2 = previous, current
+		objects = append(objects, line)
+		previous = current
+	}
+
+	minLabel := canvas.NewText(models.NewMoney(min, forecast.StartBalance.Currency).String(), color.Gray{Y: 120})
+	minLabel.TextSize = 10
+	minLabel.Move(fyne.NewPos(0, height-12))
+	maxLabel := canvas.NewText(models.NewMoney(max, forecast.StartBalance.Currency).String(), color.Gray{Y: 120})
+	maxLabel.TextSize = 10
+	return append(objects, minLabel, maxLabel)
+}
@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"finance_go/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// noAccountOption links a new goal to a category instead of an account
+const noAccountOption = "Nenhuma (usar categoria)"
+
+// showGoalDialog lists the savings goals with their progress and lets the user add or delete them
+func (mw *MainWindow) showGoalDialog() {
+	var statuses []models.GoalStatus
+
+	list := widget.NewList(
+		func() int {
+			return len(statuses)
+		},
+		func() fyne.CanvasObject {
+			return container.NewVBox(widget.NewLabel(""), widget.NewProgressBar(), widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			if id >= len(statuses) {
+				return
+			}
+			status := statuses[id]
+			box := o.(*fyne.Container)
+			title := box.Objects[0].(*widget.Label)
+			progress := box.Objects[1].(*widget.ProgressBar)
+			detail := box.Objects[2].(*widget.Label)
+
+			title.SetText(fmt.Sprintf("%s — %s de %s (%s)", status.Goal.Name, status.Saved, status.Goal.Target, mw.goalLink(status.Goal)))
+			progress.SetValue(status.Percent() / 100)
+
+			detail.Importance = widget.MediumImportance
+			switch {
+			case status.Reached():
+				detail.Importance = widget.SuccessImportance
+				detail.SetText(fmt.Sprintf("Meta atingida! Prazo: %s", status.Goal.Deadline.Format("02/01/2006")))
+			case status.Overdue():
+				detail.Importance = widget.DangerImportance
+				detail.SetText(fmt.Sprintf("Prazo vencido em %s, faltam %s", status.Goal.Deadline.Format("02/01/2006"), status.Remaining))
+			default:
+				detail.SetText(fmt.Sprintf("Faltam %s até %s: %s por mês em %d meses",
+					status.Remaining, status.Goal.Deadline.Format("02/01/2006"), status.MonthlyNeeded, status.MonthsLeft))
+			}
+		},
+	)
+
+	reload := func() {
+		statuses = mw.financeService.GetGoalStatus(time.Now())
+		list.Refresh()
+	}
+
+	list.OnSelected = func(id widget.ListItemID) {
+		list.UnselectAll()
+		if id >= len(statuses) {
+			return
+		}
+		goal := statuses[id].Goal
+		dialog.ShowConfirm("Excluir Meta", fmt.Sprintf("Excluir a meta %s? As transações serão mantidas.", goal.Name), func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if err := mw.financeService.DeleteGoal(goal.ID); err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+			reload()
+		}, mw.window)
+	}
+
+	newButton := widget.NewButton("Nova Meta", func() {
+		mw.showNewGoalDialog(reload)
+	})
+
+	content := container.NewBorder(nil, newButton, nil, nil, list)
+
+	reload()
+	d := dialog.NewCustom("Metas de Economia", "Fechar", content, mw.window)
+	d.Resize(fyne.NewSize(640, 480))
+	d.Show()
+}
+
+// showNewGoalDialog opens a form to create a savings goal linked to an account or a category
+func (mw *MainWindow) showNewGoalDialog(onCreated func()) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Ex.: Viagem, Reserva de emergência")
+	targetEntry := widget.NewEntry()
+	deadlineEntry := widget.NewEntry()
+	deadlineEntry.SetPlaceHolder("DD/MM/AAAA")
+	accountSelect := widget.NewSelect(append([]string{noAccountOption}, mw.accountNames()...), func(string) {})
+	accountSelect.SetSelected(noAccountOption)
+	categoryEntry := widget.NewSelectEntry(mw.financeService.GetTransactionList().GetCategoryPaths())
+	categoryEntry.SetPlaceHolder("Ex.: Poupança > Viagem")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Nome", nameEntry),
+		widget.NewFormItem("Valor", targetEntry),
+		widget.NewFormItem("Prazo", deadlineEntry),
+		widget.NewFormItem("Conta", accountSelect),
+		widget.NewFormItem("Categoria", categoryEntry),
+	}
+
+	dialog.ShowForm("Nova Meta", "Criar", "Cancelar", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		target, err := models.ParseMoney(targetEntry.Text, mw.financeService.GetBaseCurrency())
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("valor inválido"), mw.window)
+			return
+		}
+		deadline, err := time.ParseInLocation("02/01/2006", strings.TrimSpace(deadlineEntry.Text), time.Local)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("prazo inválido, use DD/MM/AAAA"), mw.window)
+			return
+		}
+
+		goal := models.Goal{
+			Name:     nameEntry.Text,
+			Target:   target,
+			Deadline: deadline,
+		}
+		if accountSelect.Selected != noAccountOption {
+			goal.AccountID = mw.accountIDByName(accountSelect.Selected)
+		} else {
+			goal.Category = categoryEntry.Text
+		}
+
+		if _, err := mw.financeService.AddGoal(goal); err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		onCreated()
+	}, mw.window)
+}
+
+// goalLink describes what a goal's progress is computed from
+func (mw *MainWindow) goalLink(goal models.Goal) string {
+	if goal.AccountID != 0 {
+		return fmt.Sprintf("conta %s", mw.accountName(goal.AccountID))
+	}
+	return fmt.Sprintf("categoria %s", goal.Category)
+}
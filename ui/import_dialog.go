@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"fmt"
+
+	"finance_go/services"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// importReportRow is a line of the import report table
+type importReportRow struct {
+	status string
+	row    services.ImportRow
+}
+
+// showImportReport lists the rows an import added and the ones it skipped as duplicates or invalid
+func (mw *MainWindow) showImportReport(title string, report services.ImportReport) {
+	var rows []importReportRow
+	for _, row := range report.Added {
+		rows = append(rows, importReportRow{status: "Adicionada", row: row})
+	}
+	for _, row := range report.Duplicates {
+		rows = append(rows, importReportRow{status: "Duplicada", row: row})
+	}
+	for _, row := range report.Invalid {
+		rows = append(rows, importReportRow{status: "Inválida", row: row})
+	}
+
+	table := widget.NewTable(
+		func() (int, int) {
+			return len(rows) + 1, 6
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.TableCellID, o fyne.CanvasObject) {
+			label := o.(*widget.Label)
+			label.Importance = widget.MediumImportance
+			if id.Row == 0 {
+				label.SetText([]string{"Linha", "Situação", "Data", "Valor / Descrição", "Categoria", "Motivo"}[id.Col])
+				return
+			}
+			entry := rows[id.Row-1]
+			tx := entry.row.Transaction
+			switch entry.status {
+			case "Duplicada":
+				label.Importance = widget.WarningImportance
+			case "Inválida":
+				label.Importance = widget.DangerImportance
+			}
+			switch id.Col {
+			case 0:
+				label.SetText(fmt.Sprintf("%d", entry.row.Line))
+			case 1:
+				label.SetText(entry.status)
+			case 2:
+				if tx.Date.IsZero() {
+					label.SetText("")
+				} else {
+					label.SetText(tx.Date.Format("02/01/2006"))
+				}
+			case 3:
+				if tx.Description == "" && tx.Value.IsZero() {
+					label.SetText("")
+				} else {
+					label.SetText(fmt.Sprintf("%s %s", tx.Value, tx.Description))
+				}
+			case 4:
+				label.SetText(importCategory(entry.row))
+				if suggestion := entry.row.Suggestion; suggestion != nil && suggestion.NeedsReview() {
+					label.Importance = widget.WarningImportance
+				}
+			case 5:
+				label.SetText(entry.row.Reason)
+			}
+		},
+	)
+	table.SetColumnWidth(0, 60)
+	table.SetColumnWidth(1, 100)
+	table.SetColumnWidth(2, 100)
+	table.SetColumnWidth(3, 260)
+	table.SetColumnWidth(4, 240)
+	table.SetColumnWidth(5, 320)
+
+	summary := widget.NewLabel(fmt.Sprintf("%d adicionadas, %d ignoradas por serem duplicadas, %d inválidas, %d com categoria a revisar",
+		len(report.Added), len(report.Duplicates), len(report.Invalid), len(report.NeedsReview())))
+	content := container.NewBorder(summary, nil, nil, nil, table)
+
+	d := dialog.NewCustom(title, "Fechar", content, mw.window)
+	d.Resize(fyne.NewSize(1120, 480))
+	d.Show()
+}
+
+// importCategory shows the category of an imported row and, when it was suggested from the
+// history, the confidence of the suggestion
+func importCategory(row services.ImportRow) string {
+	suggestion := row.Suggestion
+	switch {
+	case suggestion == nil:
+		return row.Transaction.CategoryLabel()
+	case suggestion.NeedsReview():
+		return fmt.Sprintf("Revisar: %s? (%.0f%%)", suggestion.Category, suggestion.Confidence*100)
+	default:
+		return fmt.Sprintf("%s (sugerida, %.0f%%)", suggestion.Category, suggestion.Confidence*100)
+	}
+}
@@ -0,0 +1,345 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"finance_go/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showLoanDialog lists the loans with their outstanding balance and opens the schedule of the selected one
+func (mw *MainWindow) showLoanDialog() {
+	var statuses []models.LoanStatus
+
+	list := widget.NewList(
+		func() int {
+			return len(statuses)
+		},
+		func() fyne.CanvasObject {
+			return container.NewVBox(widget.NewLabel(""), widget.NewProgressBar(), widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			if id >= len(statuses) {
+				return
+			}
+			status := statuses[id]
+			box := o.(*fyne.Container)
+			title := box.Objects[0].(*widget.Label)
+			progress := box.Objects[1].(*widget.ProgressBar)
+			detail := box.Objects[2].(*widget.Label)
+
+			title.SetText(fmt.Sprintf("%s — %s em %d meses (%s, %.4g%% a.m.)",
+				status.Loan.Name, status.Loan.Principal, status.Loan.Term, status.Loan.System, status.Loan.MonthlyRate))
+			progress.SetValue(float64(status.PaidCount) / float64(status.Loan.Term))
+			detail.SetText(fmt.Sprintf("%d de %d parcelas pagas | Saldo devedor: %s | Juros pagos: %s",
+				status.PaidCount, status.Loan.Term, status.Outstanding, status.InterestPaid))
+		},
+	)
+
+	reload := func() {
+		statuses = nil
+		for _, loan := range mw.financeService.GetLoans() {
+			if status, err := mw.financeService.GetLoanStatus(loan.ID); err == nil {
+				statuses = append(statuses, status)
+			}
+		}
+		list.Refresh()
+	}
+
+	list.OnSelected = func(id widget.ListItemID) {
+		list.UnselectAll()
+		if id >= len(statuses) {
+			return
+		}
+		mw.showLoanScheduleDialog(statuses[id].Loan.ID, reload)
+	}
+
+	newButton := widget.NewButton("Novo Financiamento", func() {
+		mw.showNewLoanDialog(reload)
+	})
+
+	content := container.NewBorder(nil, newButton, nil, nil, list)
+
+	reload()
+	d := dialog.NewCustom("Empréstimos e Financiamentos", "Fechar", content, mw.window)
+	d.Resize(fyne.NewSize(700, 480))
+	d.Show()
+}
+
+// showLoanScheduleDialog shows the amortization schedule of a loan and lets the user pay
+// or link installments, export the schedule and delete the loan
+func (mw *MainWindow) showLoanScheduleDialog(loanID int, onChanged func()) {
+	var status models.LoanStatus
+
+	summaryLabel := widget.NewLabel("")
+	table := widget.NewTable(
+		func() (int, int) {
+			return len(status.Schedule) + 1, 7
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.TableCellID, o fyne.CanvasObject) {
+			label := o.(*widget.Label)
+			label.Importance = widget.MediumImportance
+			if id.Row == 0 {
+				label.SetText([]string{"Nº", "Vencimento", "Prestação", "Juros", "Amortização", "Saldo Devedor", "Paga"}[id.Col])
+				return
+			}
+			installment := status.Schedule[id.Row-1]
+			if installment.IsPaid() {
+				label.Importance = widget.SuccessImportance
+			}
+			switch id.Col {
+			case 0:
+				label.SetText(strconv.Itoa(installment.Number))
+			case 1:
+				label.SetText(installment.DueDate.Format("02/01/2006"))
+			case 2:
+				label.SetText(installment.Payment.String())
+			case 3:
+				label.SetText(installment.Interest.String())
+			case 4:
+				label.SetText(installment.Amortization.String())
+			case 5:
+				label.SetText(installment.Balance.String())
+			case 6:
+				if installment.IsPaid() {
+					label.SetText(fmt.Sprintf("Sim (#%d)", installment.TransactionID))
+				} else {
+					label.SetText("")
+				}
+			}
+		},
+	)
+	table.SetColumnWidth(0, 50)
+	table.SetColumnWidth(1, 100)
+	for col := 2; col <= 5; col++ {
+		table.SetColumnWidth(col, 120)
+	}
+	table.SetColumnWidth(6, 90)
+
+	reload := func() {
+		result, err := mw.financeService.GetLoanStatus(loanID)
+		if err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		status = result
+		summaryLabel.SetText(fmt.Sprintf("%d de %d parcelas pagas | Saldo devedor: %s | Juros pagos: %s de %s",
+			status.PaidCount, status.Loan.Term, status.Outstanding, status.InterestPaid, status.TotalInterest))
+		table.Refresh()
+	}
+	changed := func() {
+		reload()
+		onChanged()
+		mw.Refresh()
+	}
+
+	var d dialog.Dialog
+
+	payButton := widget.NewButton("Pagar Próxima Parcela", func() {
+		installment, ok := status.NextInstallment()
+		if !ok {
+			dialog.ShowInformation("Financiamento", "Todas as parcelas já foram pagas.", mw.window)
+			return
+		}
+		dateEntry := widget.NewEntry()
+		dateEntry.SetText(time.Now().Format("02/01/2006"))
+		items := []*widget.FormItem{
+			widget.NewFormItem("Parcela", widget.NewLabel(fmt.Sprintf("%d de %d: %s", installment.Number, status.Loan.Term, installment.Payment))),
+			widget.NewFormItem("Data do pagamento", dateEntry),
+		}
+		dialog.ShowForm("Pagar Parcela", "Pagar", "Cancelar", items, func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			date, err := time.ParseInLocation("02/01/2006", strings.TrimSpace(dateEntry.Text), time.Local)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("data inválida, use DD/MM/AAAA"), mw.window)
+				return
+			}
+			if _, err := mw.financeService.PayLoanInstallment(loanID, installment.Number, date); err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+			changed()
+		}, mw.window)
+	})
+
+	linkButton := widget.NewButton("Vincular Transação", func() {
+		numberEntry := widget.NewEntry()
+		if installment, ok := status.NextInstallment(); ok {
+			numberEntry.SetText(strconv.Itoa(installment.Number))
+		}
+		transactionEntry := widget.NewEntry()
+		transactionEntry.SetPlaceHolder("ID da transação")
+		items := []*widget.FormItem{
+			widget.NewFormItem("Parcela", numberEntry),
+			widget.NewFormItem("Transação", transactionEntry),
+		}
+		dialog.ShowForm("Vincular Transação", "Vincular", "Cancelar", items, func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			number, err := strconv.Atoi(strings.TrimSpace(numberEntry.Text))
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("número de parcela inválido"), mw.window)
+				return
+			}
+			transactionID, err := strconv.Atoi(strings.TrimSpace(transactionEntry.Text))
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("ID de transação inválido"), mw.window)
+				return
+			}
+			if err := mw.financeService.LinkLoanInstallment(loanID, number, transactionID); err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+			changed()
+		}, mw.window)
+	})
+
+	excelButton := widget.NewButton("Exportar Excel", func() {
+		dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+
+			if err := mw.importExportService.ExportLoanScheduleToExcel(writer.URI().Path(), loanID); err != nil {
+				dialog.ShowError(fmt.Errorf("erro ao exportar Excel: %v", err), mw.window)
+			} else {
+				dialog.ShowInformation("Sucesso", "Excel exportado com sucesso!", mw.window)
+			}
+		}, mw.window)
+	})
+
+	pdfButton := widget.NewButton("Exportar PDF", func() {
+		dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+
+			if err := mw.pdfExportService.ExportLoanSchedule(writer.URI().Path(), loanID); err != nil {
+				dialog.ShowError(fmt.Errorf("erro ao exportar PDF: %v", err), mw.window)
+			} else {
+				dialog.ShowInformation("Sucesso", "PDF exportado com sucesso!", mw.window)
+			}
+		}, mw.window)
+	})
+
+	deleteButton := widget.NewButton("Excluir", func() {
+		dialog.ShowConfirm("Excluir Financiamento", fmt.Sprintf("Excluir o financiamento %s? As transações serão mantidas.", status.Loan.Name), func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if err := mw.financeService.DeleteLoan(loanID); err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+			onChanged()
+			d.Hide()
+		}, mw.window)
+	})
+	deleteButton.Importance = widget.DangerImportance
+
+	reload()
+	buttons := container.NewHBox(payButton, linkButton, excelButton, pdfButton, deleteButton)
+	content := container.NewBorder(container.NewVBox(summaryLabel, buttons), nil, nil, nil, table)
+
+	d = dialog.NewCustom(fmt.Sprintf("Financiamento - %s", status.Loan.Name), "Fechar", content, mw.window)
+	d.Resize(fyne.NewSize(820, 600))
+	d.Show()
+}
+
+// showNewLoanDialog opens a form to create a loan with its rate, term and amortization system
+func (mw *MainWindow) showNewLoanDialog(onCreated func()) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Ex.: Financiamento do carro")
+	principalEntry := widget.NewEntry()
+	rateEntry := widget.NewEntry()
+	rateEntry.SetPlaceHolder("Ex.: 1,25")
+	termEntry := widget.NewEntry()
+	termEntry.SetPlaceHolder("Número de meses")
+	firstDueEntry := widget.NewEntry()
+	firstDueEntry.SetPlaceHolder("DD/MM/AAAA")
+	var systems []string
+	for _, system := range models.AmortizationSystems() {
+		systems = append(systems, string(system))
+	}
+	systemSelect := widget.NewSelect(systems, func(string) {})
+	systemSelect.SetSelected(systems[0])
+	accountSelect := widget.NewSelect(mw.accountNames(), func(string) {})
+	if len(mw.accountNames()) > 0 {
+		accountSelect.SetSelected(mw.accountNames()[0])
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Nome", nameEntry),
+		widget.NewFormItem("Valor financiado", principalEntry),
+		widget.NewFormItem("Juros (% a.m.)", rateEntry),
+		widget.NewFormItem("Prazo (meses)", termEntry),
+		widget.NewFormItem("1º vencimento", firstDueEntry),
+		widget.NewFormItem("Sistema", systemSelect),
+		widget.NewFormItem("Conta", accountSelect),
+	}
+
+	dialog.ShowForm("Novo Financiamento", "Criar", "Cancelar", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		principal, err := models.ParseMoney(principalEntry.Text, mw.financeService.GetBaseCurrency())
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("valor inválido"), mw.window)
+			return
+		}
+		rate, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(rateEntry.Text), ",", "."), 64)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("taxa de juros inválida"), mw.window)
+			return
+		}
+		term, err := strconv.Atoi(strings.TrimSpace(termEntry.Text))
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("prazo inválido"), mw.window)
+			return
+		}
+		firstDue, err := time.ParseInLocation("02/01/2006", strings.TrimSpace(firstDueEntry.Text), time.Local)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("data de vencimento inválida, use DD/MM/AAAA"), mw.window)
+			return
+		}
+
+		loan := models.Loan{
+			Name:         nameEntry.Text,
+			Principal:    principal,
+			MonthlyRate:  rate,
+			Term:         term,
+			System:       models.AmortizationSystem(systemSelect.Selected),
+			FirstDueDate: firstDue,
+			AccountID:    mw.accountIDByName(accountSelect.Selected),
+		}
+		if _, err := mw.financeService.AddLoan(loan); err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		onCreated()
+	}, mw.window)
+}
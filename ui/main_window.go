@@ -2,7 +2,10 @@ package ui
 
 import (
 	"fmt"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"finance_go/models"
 	"finance_go/services"
@@ -14,18 +17,71 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
+// allCategoriesOption is the category filter entry that shows every transaction
+const allCategoriesOption = "Todas"
+
+// allPeriodsOption is the period filter entry that shows every date
+const allPeriodsOption = "Tudo"
+
+// allTagsOption is the tag filter entry that shows every transaction
+const allTagsOption = "Todas"
+
+// sortOptions maps the table ordering choices to query sort fields; the ledger order has no field
+var sortOptions = []struct {
+	label      string
+	field      models.SortField
+	descending bool
+}{
+	{"Ordem de inclusão", "", false},
+	{"Data (recentes)", models.SortByDate, true},
+	{"Data (antigas)", models.SortByDate, false},
+	{"Valor (maior)", models.SortByValue, true},
+	{"Valor (menor)", models.SortByValue, false},
+	{"Descrição", models.SortByDescription, false},
+	{"Categoria", models.SortByCategory, false},
+}
+
+// categoryDepthOptions are the category levels offered for the PDF summary; the index is the depth
+var categoryDepthOptions = []string{"Todos os níveis", "1 nível", "2 níveis", "3 níveis"}
+
 // MainWindow represents the main application window
 type MainWindow struct {
 	window              fyne.Window
 	financeService      *services.FinanceService
 	importExportService *services.ImportExportService
 	pdfExportService    *services.PDFExportService
-	balance             binding.Float
+	recurrenceService   *services.RecurrenceService
+	attachmentService   *services.AttachmentService
+	reportService       *services.ReportService
+	forecastService     *services.ForecastService
+	balance             binding.String
+	filteredTotals      binding.String
+	accountBalances     binding.String
+	rateWarning         *widget.Label
 	transactions        *widget.Table
+	// visible holds the rows of the table; reloadTransactions recomputes it when the data or the filters change
+	visible             []models.Transaction
 	amountEntry         *widget.Entry
 	descriptionEntry    *widget.Entry
 	categoryEntry       *widget.Entry
+	categorySuggestion  *widget.Button
+	tagsEntry           *widget.Entry
 	typeSelect          *widget.Select
+	accountSelect       *widget.Select
+	accountFilter       *widget.Select
+	categoryFilter      *widget.Select
+	tagFilter           *widget.Select
+	sortSelect          *widget.Select
+	periodSelect        *widget.Select
+	periodLabel         *widget.Label
+	payDaySelect        *widget.Select
+	period              models.Period
+	searchEntry         *widget.Entry
+	searchError         *widget.Label
+	searchQuery         models.Query
+	categoryDepthSelect *widget.Select
+	currencySelect      *widget.Select
+	baseCurrencySelect  *widget.Select
 }
 
 // NewMainWindow creates a new main window
@@ -35,7 +91,13 @@ func NewMainWindow(window fyne.Window, financeService *services.FinanceService)
 		financeService:      financeService,
 		importExportService: services.NewImportExportService(financeService),
 		pdfExportService:    services.NewPDFExportService(financeService),
-		balance:             binding.NewFloat(),
+		recurrenceService:   services.NewRecurrenceService(financeService),
+		attachmentService:   services.NewAttachmentService(financeService, filepath.Join("data", "attachments")),
+		reportService:       services.NewReportService(financeService),
+		forecastService:     services.NewForecastService(financeService),
+		balance:             binding.NewString(),
+		filteredTotals:      binding.NewString(),
+		accountBalances:     binding.NewString(),
 	}
 
 	mw.buildUI()
@@ -54,58 +116,192 @@ func (mw *MainWindow) buildUI() {
 	mw.categoryEntry = widget.NewEntry()
 	mw.categoryEntry.SetPlaceHolder("Categoria")
 
-	mw.typeSelect = widget.NewSelect([]string{"Receita", "Despesa"}, func(string) {})
-	mw.typeSelect.SetSelected("Receita")
+	mw.tagsEntry = widget.NewEntry()
+	mw.tagsEntry.SetPlaceHolder("Tags (separadas por vírgula)")
+
+	mw.typeSelect = widget.NewSelect(models.TransactionTypeNames(), func(string) {})
+	mw.typeSelect.SetSelected(string(models.TransactionTypeIncome))
+
+	// Suggest a category learned from the history while the description is typed
+	mw.categorySuggestion = widget.NewButton("", nil)
+	mw.categorySuggestion.Hide()
+	mw.descriptionEntry.OnChanged = func(string) { mw.updateCategorySuggestion() }
+	mw.categoryEntry.OnChanged = func(string) { mw.updateCategorySuggestion() }
+	mw.typeSelect.OnChanged = func(string) { mw.updateCategorySuggestion() }
+
+	mw.currencySelect = widget.NewSelect(models.SupportedCurrencies(), func(string) {})
+	mw.currencySelect.SetSelected(mw.financeService.GetBaseCurrency())
+
+	// Balances and reports are converted to the base currency
+	mw.baseCurrencySelect = widget.NewSelect(models.SupportedCurrencies(), func(currency string) {
+		mw.financeService.SetBaseCurrency(currency)
+		mw.Refresh()
+	})
 
-	balanceLabel := widget.NewLabelWithData(binding.FloatToStringWithFormat(mw.balance, "Saldo: R$ %.2f"))
+	mw.accountSelect = widget.NewSelect(mw.accountNames(), func(string) {})
+	mw.accountSelect.SetSelected(mw.defaultAccountName())
+
+	// Filter the table by account; allAccountsOption shows every transaction
+	mw.accountFilter = widget.NewSelect(append([]string{allAccountsOption}, mw.accountNames()...), func(string) {
+		mw.applyFilters()
+	})
+
+	balanceLabel := widget.NewLabelWithData(mw.balance)
+	filteredTotalsLabel := widget.NewLabelWithData(mw.filteredTotals)
+	accountBalancesLabel := widget.NewLabelWithData(mw.accountBalances)
+	mw.rateWarning = widget.NewLabel("")
+	mw.rateWarning.Importance = widget.DangerImportance
+	mw.rateWarning.Wrapping = fyne.TextWrapWord
+	mw.rateWarning.Hide()
 
 	// Create transactions table
 	mw.transactions = widget.NewTable(
 		func() (int, int) {
-			return len(mw.financeService.GetTransactions()), 5
+			return len(mw.visible), 7
 		},
 		func() fyne.CanvasObject {
 			return widget.NewLabel("")
 		},
 		func(id widget.TableCellID, o fyne.CanvasObject) {
 			label := o.(*widget.Label)
-			transactions := mw.financeService.GetTransactions()
-			if id.Row < len(transactions) {
-				tx := transactions[id.Row]
+			if id.Row < len(mw.visible) {
+				tx := mw.visible[id.Row]
 				switch id.Col {
 				case 0:
-					label.SetText(tx.Date.Format("02/01/2006"))
+					// C marks cleared transactions, R reconciled (locked) ones
+					label.SetText(strings.TrimSpace(tx.Date.Format("02/01/2006") + " " + tx.Status.Mark()))
 				case 1:
-					label.SetText(tx.Type)
+					label.SetText(string(tx.Type))
 				case 2:
-					label.SetText(fmt.Sprintf("R$ %.2f", tx.Value))
+					label.SetText(mw.formatValue(tx))
 				case 3:
 					label.SetText(tx.Description)
 				case 4:
-					label.SetText(tx.Category)
+					label.SetText(tx.CategoryLabel())
+				case 5:
+					label.SetText(mw.accountName(tx.AccountID))
+				case 6:
+					label.SetText(models.FormatTags(tx.Tags))
 				}
 			}
 		},
 	)
 
+	mw.accountFilter.SetSelected(allAccountsOption)
+
+	// Filter the table by category; a parent category also shows its subcategories
+	mw.categoryFilter = widget.NewSelect(mw.categoryFilterOptions(), func(string) {
+		mw.applyFilters()
+	})
+	mw.categoryFilter.SetSelected(allCategoriesOption)
+
+	// Filter the table by tag
+	mw.tagFilter = widget.NewSelect(mw.tagFilterOptions(), func(string) {
+		mw.applyFilters()
+	})
+	mw.tagFilter.SetSelected(allTagsOption)
+
+	// Order the table
+	var sortLabels []string
+	for _, option := range sortOptions {
+		sortLabels = append(sortLabels, option.label)
+	}
+	mw.sortSelect = widget.NewSelect(sortLabels, func(string) {
+		mw.applyFilters()
+	})
+	mw.sortSelect.SetSelected(sortLabels[0])
+
+	// Restrict the table to a period; the arrows move to the previous or next one
+	periodOptions := []string{allPeriodsOption}
+	for _, kind := range models.PeriodKinds() {
+		periodOptions = append(periodOptions, string(kind))
+	}
+	mw.periodLabel = widget.NewLabel("")
+	var payDays []string
+	for day := 1; day <= 31; day++ {
+		payDays = append(payDays, strconv.Itoa(day))
+	}
+	mw.payDaySelect = widget.NewSelect(payDays, func(selected string) {
+		day, _ := strconv.Atoi(selected)
+		if day == 0 || day == mw.financeService.GetFiscalMonthStart() {
+			return
+		}
+		if err := mw.financeService.SetFiscalMonthStart(day); err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		mw.setPeriod(mw.financeService.PeriodContaining(models.PeriodFiscalMonth, time.Now()))
+	})
+	mw.payDaySelect.SetSelected(strconv.Itoa(mw.financeService.GetFiscalMonthStart()))
+	mw.periodSelect = widget.NewSelect(periodOptions, func(selected string) {
+		if selected == allPeriodsOption {
+			mw.setPeriod(models.Period{})
+			return
+		}
+		mw.setPeriod(mw.financeService.PeriodContaining(models.PeriodKind(selected), time.Now()))
+	})
+	mw.periodSelect.SetSelected(allPeriodsOption)
+	previousPeriodButton := widget.NewButton("<", func() {
+		if !mw.period.IsAll() {
+			mw.setPeriod(mw.period.Previous())
+		}
+	})
+	nextPeriodButton := widget.NewButton(">", func() {
+		if !mw.period.IsAll() {
+			mw.setPeriod(mw.period.Next())
+		}
+	})
+
+	// Search the table with the query language, e.g. cat:Alimentação valor>100 data:2025-03 "ifood"
+	mw.searchEntry = widget.NewEntry()
+	mw.searchEntry.SetPlaceHolder(`Buscar: cat:Alimentação valor>100 data:2025-03 "ifood"`)
+	mw.searchError = widget.NewLabel("")
+	mw.searchError.Importance = widget.DangerImportance
+	mw.searchError.Hide()
+	mw.searchEntry.OnChanged = mw.search
+
+	// Open the edit dialog when a row is selected
+	mw.transactions.OnSelected = func(id widget.TableCellID) {
+		if id.Row < len(mw.visible) {
+			mw.showEditTransactionDialog(mw.visible[id.Row].ID)
+		}
+	}
+
 	// Set table column widths
-	mw.transactions.SetColumnWidth(0, 100) // Date
+	mw.transactions.SetColumnWidth(0, 115) // Date and reconciliation mark
 	mw.transactions.SetColumnWidth(1, 80)  // Type
-	mw.transactions.SetColumnWidth(2, 100) // Value
+	mw.transactions.SetColumnWidth(2, 160) // Value
 	mw.transactions.SetColumnWidth(3, 200) // Description
 	mw.transactions.SetColumnWidth(4, 120) // Category
+	mw.transactions.SetColumnWidth(5, 120) // Account
+	mw.transactions.SetColumnWidth(6, 150) // Tags
 
 	// Create table headers
-	headers := container.NewGridWithColumns(5,
+	headers := container.NewGridWithColumns(7,
 		widget.NewLabel("Data"),
 		widget.NewLabel("Tipo"),
 		widget.NewLabel("Valor"),
 		widget.NewLabel("Descrição"),
 		widget.NewLabel("Categoria"),
+		widget.NewLabel("Conta"),
+		widget.NewLabel("Tags"),
 	)
 
 	// Create table container with headers
 	tableContainer := container.NewVBox(
+		mw.searchEntry,
+		mw.searchError,
+		container.NewHBox(
+			widget.NewLabel("Conta:"), mw.accountFilter,
+			widget.NewLabel("Categoria:"), mw.categoryFilter,
+			widget.NewLabel("Tag:"), mw.tagFilter,
+			widget.NewLabel("Ordenar:"), mw.sortSelect,
+		),
+		container.NewHBox(
+			widget.NewLabel("Período:"), mw.periodSelect,
+			previousPeriodButton, mw.periodLabel, nextPeriodButton,
+			mw.payDaySelect,
+		),
 		headers,
 		widget.NewSeparator(),
 		container.NewVScroll(container.NewMax(mw.transactions)), // Scrollable container that expands
@@ -122,6 +318,23 @@ func (mw *MainWindow) buildUI() {
 	exportExcelButton := widget.NewButton("Exportar Excel", mw.exportExcel)
 	exportPDFButton := widget.NewButton("Exportar PDF", mw.exportPDF)
 
+	// Number of category levels in the PDF category summary
+	mw.categoryDepthSelect = widget.NewSelect(categoryDepthOptions, func(string) {})
+	mw.categoryDepthSelect.SetSelected(categoryDepthOptions[0])
+	importRatesButton := widget.NewButton("Importar Câmbio", mw.importExchangeRates)
+	newAccountButton := widget.NewButton("Nova Conta", mw.showNewAccountDialog)
+	transferButton := widget.NewButton("Transferir", mw.showTransferDialog)
+	recurrenceButton := widget.NewButton("Recorrências", mw.showRecurrenceDialog)
+	budgetButton := widget.NewButton("Orçamentos", mw.showBudgetDialog)
+	reportButton := widget.NewButton("Relatório", mw.showReportDialog)
+	forecastButton := widget.NewButton("Previsão", mw.showForecastDialog)
+	goalButton := widget.NewButton("Metas", mw.showGoalDialog)
+	loanButton := widget.NewButton("Financiamentos", mw.showLoanDialog)
+	purchaseButton := widget.NewButton("Parcelados", mw.showPurchaseDialog)
+	statementButton := widget.NewButton("Faturas", mw.showStatementDialog)
+	reconcileButton := widget.NewButton("Conciliar", mw.showReconcileDialog)
+	ruleButton := widget.NewButton("Regras", mw.showRuleDialog)
+
 	// Create import/export buttons layout - place them at the top
 	importExportButtons := container.NewHBox(
 		importCSVButton,
@@ -129,14 +342,35 @@ func (mw *MainWindow) buildUI() {
 		exportCSVButton,
 		exportExcelButton,
 		exportPDFButton,
+		mw.categoryDepthSelect,
+		importRatesButton,
+		newAccountButton,
+		transferButton,
+		recurrenceButton,
+		budgetButton,
+		reportButton,
+		forecastButton,
+		goalButton,
+		loanButton,
+		purchaseButton,
+		statementButton,
+		reconcileButton,
+		ruleButton,
 	)
 
 	// Create form layout with import/export buttons at the top
-	formFields := container.NewGridWithColumns(4,
-		mw.amountEntry,
-		mw.descriptionEntry,
-		mw.categoryEntry,
-		mw.typeSelect,
+	formFields := container.NewVBox(
+		container.NewGridWithColumns(4,
+			mw.amountEntry,
+			mw.currencySelect,
+			mw.typeSelect,
+			mw.accountSelect,
+		),
+		container.NewGridWithColumns(3,
+			mw.descriptionEntry,
+			container.NewBorder(nil, nil, nil, mw.categorySuggestion, mw.categoryEntry),
+			mw.tagsEntry,
+		),
 	)
 
 	form := container.NewVBox(
@@ -144,7 +378,9 @@ func (mw *MainWindow) buildUI() {
 		widget.NewSeparator(),
 		formFields,
 		addButton,
-		balanceLabel,
+		container.NewHBox(balanceLabel, filteredTotalsLabel, widget.NewLabel("Moeda base:"), mw.baseCurrencySelect),
+		accountBalancesLabel,
+		mw.rateWarning,
 	)
 
 	// Create main layout using Border layout to make table cover entire remaining size
@@ -161,6 +397,8 @@ func (mw *MainWindow) buildUI() {
 
 	mw.window.SetContent(mainLayout)
 
+	mw.baseCurrencySelect.SetSelected(mw.financeService.GetBaseCurrency())
+
 	// Update initial balance
 	mw.updateBalance()
 }
@@ -168,23 +406,64 @@ func (mw *MainWindow) buildUI() {
 // addTransaction handles adding a new transaction
 func (mw *MainWindow) addTransaction() {
 	valStr := mw.amountEntry.Text
-	val, err := strconv.ParseFloat(valStr, 64)
+	val, err := models.ParseMoney(valStr, mw.currencySelect.Selected)
 	if err != nil {
 		dialog.ShowError(fmt.Errorf("valor inválido"), mw.window)
 		return
 	}
 
-	typ := mw.typeSelect.Selected
+	typ, err := models.ParseTransactionType(mw.typeSelect.Selected)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("tipo inválido"), mw.window)
+		return
+	}
 	description := mw.descriptionEntry.Text
 	category := mw.categoryEntry.Text
 
 	// Create transaction with new fields
 	transaction := models.NewTransaction(typ, val, description, category)
-	mw.financeService.AddTransactionFromModel(transaction)
+	transaction.AccountID = mw.selectedAccountID()
+	transaction.Tags = models.ParseTags(mw.tagsEntry.Text)
+	// A blank category is filled by the categorization rules
+	transaction = mw.financeService.Categorize(transaction)
+	if err := mw.financeService.AddTransactionFromModel(transaction); err != nil {
+		dialog.ShowError(err, mw.window)
+		return
+	}
 
 	mw.updateBalance()
 	mw.clearForm()
-	mw.transactions.Refresh()
+	mw.reloadTransactions()
+}
+
+// updateCategorySuggestion offers the most likely category for the description while the category
+// field is empty; tapping the suggestion fills the field. Uncertain suggestions ask for review.
+func (mw *MainWindow) updateCategorySuggestion() {
+	description := strings.TrimSpace(mw.descriptionEntry.Text)
+	typ, err := models.ParseTransactionType(mw.typeSelect.Selected)
+	if description == "" || strings.TrimSpace(mw.categoryEntry.Text) != "" || err != nil {
+		mw.categorySuggestion.Hide()
+		return
+	}
+	suggestions := mw.financeService.SuggestCategories(description, typ, 1)
+	if len(suggestions) == 0 {
+		mw.categorySuggestion.Hide()
+		return
+	}
+
+	suggestion := suggestions[0]
+	if suggestion.NeedsReview() {
+		mw.categorySuggestion.SetText(fmt.Sprintf("Revisar: %s? (%.0f%%)", suggestion.Category, suggestion.Confidence*100))
+		mw.categorySuggestion.Importance = widget.WarningImportance
+	} else {
+		mw.categorySuggestion.SetText(fmt.Sprintf("%s (%.0f%%)", suggestion.Category, suggestion.Confidence*100))
+		mw.categorySuggestion.Importance = widget.LowImportance
+	}
+	mw.categorySuggestion.OnTapped = func() {
+		mw.categoryEntry.SetText(suggestion.Category)
+	}
+	mw.categorySuggestion.Show()
+	mw.categorySuggestion.Refresh()
 }
 
 // clearForm clears all form fields
@@ -192,7 +471,8 @@ func (mw *MainWindow) clearForm() {
 	mw.amountEntry.SetText("")
 	mw.descriptionEntry.SetText("")
 	mw.categoryEntry.SetText("")
-	mw.typeSelect.SetSelected("Receita")
+	mw.tagsEntry.SetText("")
+	mw.typeSelect.SetSelected(string(models.TransactionTypeIncome))
 }
 
 // importCSV handles CSV import
@@ -207,13 +487,12 @@ func (mw *MainWindow) importCSV() {
 		}
 		defer reader.Close()
 
-		err = mw.importExportService.ImportFromCSV(reader.URI().Path())
+		report, err := mw.importExportService.ImportFromCSV(reader.URI().Path(), mw.selectedAccountID())
 		if err != nil {
 			dialog.ShowError(fmt.Errorf("erro ao importar CSV: %v", err), mw.window)
 		} else {
-			dialog.ShowInformation("Sucesso", "CSV importado com sucesso!", mw.window)
-			mw.updateBalance()
-			mw.transactions.Refresh()
+			mw.showImportReport("CSV importado", report)
+			mw.Refresh()
 		}
 	}, mw.window)
 }
@@ -230,13 +509,34 @@ func (mw *MainWindow) importExcel() {
 		}
 		defer reader.Close()
 
-		err = mw.importExportService.ImportFromExcel(reader.URI().Path())
+		report, err := mw.importExportService.ImportFromExcel(reader.URI().Path(), mw.selectedAccountID())
 		if err != nil {
 			dialog.ShowError(fmt.Errorf("erro ao importar Excel: %v", err), mw.window)
 		} else {
-			dialog.ShowInformation("Sucesso", "Excel importado com sucesso!", mw.window)
-			mw.updateBalance()
-			mw.transactions.Refresh()
+			mw.showImportReport("Excel importado", report)
+			mw.Refresh()
+		}
+	}, mw.window)
+}
+
+// importExchangeRates handles exchange rate CSV import (PTAX or Data,Moeda,Taxa)
+func (mw *MainWindow) importExchangeRates() {
+	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		count, err := mw.importExportService.ImportExchangeRatesFromCSV(reader.URI().Path())
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("erro ao importar câmbio: %v", err), mw.window)
+		} else {
+			dialog.ShowInformation("Sucesso", fmt.Sprintf("%d cotações importadas com sucesso!", count), mw.window)
+			mw.Refresh()
 		}
 	}, mw.window)
 }
@@ -253,7 +553,7 @@ func (mw *MainWindow) exportCSV() {
 		}
 		defer writer.Close()
 
-		err = mw.importExportService.ExportToCSV(writer.URI().Path())
+		err = mw.importExportService.ExportToCSV(writer.URI().Path(), mw.currentQuery())
 		if err != nil {
 			dialog.ShowError(fmt.Errorf("erro ao exportar CSV: %v", err), mw.window)
 		} else {
@@ -274,7 +574,7 @@ func (mw *MainWindow) exportExcel() {
 		}
 		defer writer.Close()
 
-		err = mw.importExportService.ExportToExcel(writer.URI().Path())
+		err = mw.importExportService.ExportToExcel(writer.URI().Path(), mw.currentQuery())
 		if err != nil {
 			dialog.ShowError(fmt.Errorf("erro ao exportar Excel: %v", err), mw.window)
 		} else {
@@ -295,7 +595,8 @@ func (mw *MainWindow) exportPDF() {
 		}
 		defer writer.Close()
 
-		err = mw.pdfExportService.ExportToPDF(writer.URI().Path())
+		mw.pdfExportService.SetCategoryDepth(mw.categoryDepthSelect.SelectedIndex())
+		err = mw.pdfExportService.ExportToPDF(writer.URI().Path(), mw.currentQuery())
 		if err != nil {
 			dialog.ShowError(fmt.Errorf("erro ao exportar PDF: %v", err), mw.window)
 		} else {
@@ -304,14 +605,141 @@ func (mw *MainWindow) exportPDF() {
 	}, mw.window)
 }
 
+// formatValue shows the original value, followed by the converted value when it is in another currency
+func (mw *MainWindow) formatValue(tx models.Transaction) string {
+	if tx.Value.Currency == mw.financeService.GetBaseCurrency() {
+		return tx.Value.String()
+	}
+	base, err := mw.financeService.ValueInBase(tx)
+	if err != nil {
+		return fmt.Sprintf("%s (sem cotação)", tx.Value)
+	}
+	return fmt.Sprintf("%s (%s)", tx.Value, base)
+}
+
 // updateBalance updates the displayed balance
 func (mw *MainWindow) updateBalance() {
 	balance := mw.financeService.GetBalance()
-	mw.balance.Set(balance)
+	mw.balance.Set(fmt.Sprintf("Saldo: %s", balance))
+
+	var parts []string
+	for _, account := range mw.financeService.GetAccounts() {
+		parts = append(parts, fmt.Sprintf("%s: %s", account.Name, mw.financeService.GetAccountBalance(account.ID)))
+	}
+	mw.accountBalances.Set(strings.Join(parts, " | "))
+	mw.updateFilteredTotals()
+
+	if warning := missingRatesText(mw.financeService.MissingRates(time.Now())); warning != "" {
+		mw.rateWarning.SetText(warning)
+		mw.rateWarning.Show()
+	} else {
+		mw.rateWarning.Hide()
+	}
+}
+
+// missingRatesText warns that values without an exchange rate are left out of the totals, or is
+// empty when every value can be converted
+func missingRatesText(missing []models.MissingRate) string {
+	if len(missing) == 0 {
+		return ""
+	}
+	var labels []string
+	for _, rate := range missing {
+		labels = append(labels, rate.Label())
+	}
+	return fmt.Sprintf("Valores sem cotação ficaram fora dos totais: %s. Use \"Importar Câmbio\" para incluí-los.", strings.Join(labels, "; "))
 }
 
 // Refresh refreshes the UI components
 func (mw *MainWindow) Refresh() {
 	mw.updateBalance()
+	mw.categoryFilter.SetOptions(mw.categoryFilterOptions())
+	mw.tagFilter.SetOptions(mw.tagFilterOptions())
+	mw.reloadTransactions()
+}
+
+// reloadTransactions runs the current query once and redraws the table from its result
+func (mw *MainWindow) reloadTransactions() {
+	mw.visible = mw.financeService.QueryTransactions(mw.currentQuery())
 	mw.transactions.Refresh()
 }
+
+// currentQuery builds the query selected by the filters above the table; exports use it too
+func (mw *MainWindow) currentQuery() models.Query {
+	// Terms typed in the search box take precedence over the selectors; dates must match both
+	query := mw.searchQuery
+	query.Period = query.Period.Intersect(mw.period)
+	if query.AccountID == 0 && mw.accountFilter != nil && mw.accountFilter.Selected != "" && mw.accountFilter.Selected != allAccountsOption {
+		query.AccountID = mw.accountIDByName(mw.accountFilter.Selected)
+	}
+	if len(query.Categories) == 0 && mw.categoryFilter != nil && mw.categoryFilter.Selected != allCategoriesOption {
+		query.Categories = []string{mw.categoryFilter.Selected}
+	}
+	if len(query.Tags) == 0 && mw.tagFilter != nil && mw.tagFilter.Selected != allTagsOption {
+		query.Tags = []string{mw.tagFilter.Selected}
+	}
+	if mw.sortSelect != nil {
+		for _, option := range sortOptions {
+			if option.label == mw.sortSelect.Selected {
+				query.SortBy = option.field
+				query.Descending = option.descending
+			}
+		}
+	}
+	return query
+}
+
+// search parses the search box; on a syntax error the error is shown and the search is ignored
+func (mw *MainWindow) search(input string) {
+	query, err := mw.financeService.GetTransactionList().ParseSearch(input)
+	if err != nil {
+		mw.searchError.SetText(fmt.Sprintf("Erro na busca: %v", err))
+		mw.searchError.Show()
+		query = models.Query{}
+	} else {
+		mw.searchError.SetText("")
+		mw.searchError.Hide()
+	}
+	mw.searchQuery = query
+	mw.applyFilters()
+}
+
+// setPeriod changes the period shown in the table
+func (mw *MainWindow) setPeriod(period models.Period) {
+	mw.period = period
+	mw.periodLabel.SetText(period.Label())
+	if period.Kind == models.PeriodFiscalMonth {
+		mw.payDaySelect.Show()
+	} else {
+		mw.payDaySelect.Hide()
+	}
+	mw.applyFilters()
+}
+
+// applyFilters refreshes the table and the filtered totals after a filter changed
+func (mw *MainWindow) applyFilters() {
+	mw.updateFilteredTotals()
+	mw.reloadTransactions()
+}
+
+// updateFilteredTotals shows the totals of the visible transactions when any filter is active
+func (mw *MainWindow) updateFilteredTotals() {
+	query := mw.currentQuery()
+	if !query.IsFiltered() {
+		mw.filteredTotals.Set("")
+		return
+	}
+	summary := mw.reportService.Totals(query)
+	mw.filteredTotals.Set(fmt.Sprintf("| Filtro (%d): Receitas %s, Despesas %s, Saldo %s",
+		summary.Count, summary.Income, summary.Expense, summary.Balance()))
+}
+
+// tagFilterOptions lists every tag in use for the tag filter
+func (mw *MainWindow) tagFilterOptions() []string {
+	return append([]string{allTagsOption}, mw.financeService.GetTransactionList().GetTags()...)
+}
+
+// categoryFilterOptions lists every category path, parents included, for the category filter
+func (mw *MainWindow) categoryFilterOptions() []string {
+	return append([]string{allCategoriesOption}, mw.financeService.GetTransactionList().GetCategoryPaths()...)
+}
@@ -0,0 +1,215 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"finance_go/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showPurchaseDialog lists the installment purchases with their paid and pending installments
+// and lets the user add purchases or prepay or cancel the pending installments
+func (mw *MainWindow) showPurchaseDialog() {
+	var statuses []models.PurchaseStatus
+
+	list := widget.NewList(
+		func() int {
+			return len(statuses)
+		},
+		func() fyne.CanvasObject {
+			return container.NewVBox(widget.NewLabel(""), widget.NewProgressBar(), widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			if id >= len(statuses) {
+				return
+			}
+			status := statuses[id]
+			purchase := status.Purchase
+			box := o.(*fyne.Container)
+			title := box.Objects[0].(*widget.Label)
+			progress := box.Objects[1].(*widget.ProgressBar)
+			detail := box.Objects[2].(*widget.Label)
+
+			title.SetText(fmt.Sprintf("%s — %s em %dx (%s)", purchase.Description, purchase.Total, purchase.Installments, purchase.State))
+			progress.SetValue(0)
+			if count := status.Paid + status.Pending; count > 0 {
+				progress.SetValue(float64(status.Paid) / float64(count))
+			}
+			if status.Pending == 0 {
+				detail.SetText(fmt.Sprintf("%d parcelas pagas: %s", status.Paid, status.PaidAmount))
+			} else {
+				detail.SetText(fmt.Sprintf("%d pagas: %s | %d a vencer: %s | Próxima em %s",
+					status.Paid, status.PaidAmount, status.Pending, status.PendingAmount, status.NextDate.Format("02/01/2006")))
+			}
+		},
+	)
+
+	reload := func() {
+		statuses = mw.financeService.GetPurchaseStatus(time.Now())
+		list.Refresh()
+	}
+	changed := func() {
+		reload()
+		mw.Refresh()
+	}
+
+	list.OnSelected = func(id widget.ListItemID) {
+		list.UnselectAll()
+		if id >= len(statuses) {
+			return
+		}
+		status := statuses[id]
+		if status.Pending == 0 {
+			dialog.ShowInformation("Compra Parcelada", "Esta compra não tem parcelas a vencer.", mw.window)
+			return
+		}
+		mw.showPurchaseActions(status, changed)
+	}
+
+	newButton := widget.NewButton("Nova Compra Parcelada", func() {
+		mw.showNewPurchaseDialog(changed)
+	})
+
+	content := container.NewBorder(nil, newButton, nil, nil, list)
+
+	reload()
+	d := dialog.NewCustom("Compras Parceladas", "Fechar", content, mw.window)
+	d.Resize(fyne.NewSize(680, 480))
+	d.Show()
+}
+
+// showPurchaseActions offers to prepay or cancel the pending installments of a purchase
+func (mw *MainWindow) showPurchaseActions(status models.PurchaseStatus, onChanged func()) {
+	purchase := status.Purchase
+	var d dialog.Dialog
+
+	prepayButton := widget.NewButton("Antecipar Parcelas", func() {
+		d.Hide()
+		dateEntry := widget.NewEntry()
+		dateEntry.SetText(time.Now().Format("02/01/2006"))
+		amountEntry := widget.NewEntry()
+		amountEntry.SetText(status.PendingAmount.Decimal())
+		items := []*widget.FormItem{
+			widget.NewFormItem("Data", dateEntry),
+			widget.NewFormItem("Valor (com desconto)", amountEntry),
+		}
+		dialog.ShowForm("Antecipar Parcelas", "Antecipar", "Cancelar", items, func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			date, err := time.ParseInLocation("02/01/2006", strings.TrimSpace(dateEntry.Text), time.Local)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("data inválida, use DD/MM/AAAA"), mw.window)
+				return
+			}
+			amount, err := models.ParseMoney(amountEntry.Text, purchase.Total.Currency)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("valor inválido"), mw.window)
+				return
+			}
+			if _, err := mw.financeService.PrepayInstallmentPurchase(purchase.ID, date, amount); err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+			onChanged()
+		}, mw.window)
+	})
+
+	cancelButton := widget.NewButton("Cancelar Parcelas Restantes", func() {
+		d.Hide()
+		message := fmt.Sprintf("Excluir as %d parcelas a vencer de %s (%s)? As parcelas já vencidas serão mantidas.",
+			status.Pending, purchase.Description, status.PendingAmount)
+		dialog.ShowConfirm("Cancelar Compra", message, func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			removed, locked, err := mw.financeService.CancelInstallmentPurchase(purchase.ID, time.Now())
+			if err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+			onChanged()
+			if locked > 0 {
+				dialog.ShowInformation("Cancelar Compra", fmt.Sprintf("%d parcelas excluídas. %d parcelas conciliadas não puderam ser canceladas e foram mantidas.",
+					removed, locked), mw.window)
+			}
+		}, mw.window)
+	})
+	cancelButton.Importance = widget.DangerImportance
+
+	info := widget.NewLabel(fmt.Sprintf("%d parcelas a vencer: %s", status.Pending, status.PendingAmount))
+	content := container.NewVBox(info, prepayButton, cancelButton)
+	d = dialog.NewCustom(purchase.Description, "Fechar", content, mw.window)
+	d.Show()
+}
+
+// showNewPurchaseDialog opens a form to record a purchase split into monthly installments
+func (mw *MainWindow) showNewPurchaseDialog(onCreated func()) {
+	descriptionEntry := widget.NewEntry()
+	descriptionEntry.SetPlaceHolder("Ex.: Geladeira")
+	totalEntry := widget.NewEntry()
+	installmentsEntry := widget.NewEntry()
+	installmentsEntry.SetPlaceHolder("Ex.: 10")
+	firstDateEntry := widget.NewEntry()
+	firstDateEntry.SetText(time.Now().Format("02/01/2006"))
+	currencySelect := widget.NewSelect(models.SupportedCurrencies(), func(string) {})
+	currencySelect.SetSelected(mw.financeService.GetBaseCurrency())
+	categoryEntry := widget.NewSelectEntry(mw.financeService.GetTransactionList().GetCategoryPaths())
+	accountSelect := widget.NewSelect(mw.accountNames(), func(string) {})
+	if names := mw.accountNames(); len(names) > 0 {
+		accountSelect.SetSelected(names[0])
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Descrição", descriptionEntry),
+		widget.NewFormItem("Valor total", totalEntry),
+		widget.NewFormItem("Moeda", currencySelect),
+		widget.NewFormItem("Parcelas", installmentsEntry),
+		widget.NewFormItem("1ª parcela", firstDateEntry),
+		widget.NewFormItem("Categoria", categoryEntry),
+		widget.NewFormItem("Conta", accountSelect),
+	}
+
+	dialog.ShowForm("Nova Compra Parcelada", "Criar", "Cancelar", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		total, err := models.ParseMoney(totalEntry.Text, currencySelect.Selected)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("valor inválido"), mw.window)
+			return
+		}
+		installments, err := strconv.Atoi(strings.TrimSpace(installmentsEntry.Text))
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("número de parcelas inválido"), mw.window)
+			return
+		}
+		firstDate, err := time.ParseInLocation("02/01/2006", strings.TrimSpace(firstDateEntry.Text), time.Local)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("data inválida, use DD/MM/AAAA"), mw.window)
+			return
+		}
+
+		purchase := models.InstallmentPurchase{
+			Description:  descriptionEntry.Text,
+			Total:        total,
+			Installments: installments,
+			Category:     categoryEntry.Text,
+			AccountID:    mw.accountIDByName(accountSelect.Selected),
+			FirstDate:    firstDate,
+		}
+		if _, err := mw.financeService.AddInstallmentPurchase(purchase); err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		onCreated()
+	}, mw.window)
+}
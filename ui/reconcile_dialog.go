@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"finance_go/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showReconcileDialog checks an account against a bank statement: the user enters the statement
+// date and ending balance, ticks off the transactions on the statement until the difference is
+// zero, and finishes to lock them
+func (mw *MainWindow) showReconcileDialog() {
+	var reconciliation models.Reconciliation
+
+	accountSelect := widget.NewSelect(mw.accountNames(), func(string) {})
+	accountSelect.SetSelected(mw.defaultAccountName())
+	dateEntry := widget.NewEntry()
+	dateEntry.SetText(time.Now().Format("02/01/2006"))
+	balanceEntry := widget.NewEntry()
+	balanceEntry.SetPlaceHolder("Saldo final do extrato")
+
+	clearedLabel := widget.NewLabel("")
+	differenceLabel := widget.NewLabel("")
+	finishButton := widget.NewButton("Finalizar Conciliação", nil)
+	finishButton.Importance = widget.HighImportance
+	finishButton.Disable()
+
+	var list *widget.List
+	var load func() bool
+	list = widget.NewList(
+		func() int {
+			return len(reconciliation.Candidates)
+		},
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, widget.NewCheck("", nil), widget.NewLabel(""), widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			if id >= len(reconciliation.Candidates) {
+				return
+			}
+			tx := reconciliation.Candidates[id]
+			row := o.(*fyne.Container)
+			description := row.Objects[0].(*widget.Label)
+			check := row.Objects[1].(*widget.Check)
+			value := row.Objects[2].(*widget.Label)
+
+			description.SetText(fmt.Sprintf("%s  %s", tx.Date.Format("02/01/2006"), tx.Description))
+			value.SetText(mw.formatValue(tx))
+			// Detach the handler while syncing the check with the stored status
+			check.OnChanged = nil
+			check.SetChecked(tx.Status == models.StatusCleared)
+			check.OnChanged = func(checked bool) {
+				if err := mw.financeService.SetCleared(tx.ID, checked); err != nil {
+					dialog.ShowError(err, mw.window)
+				}
+				load()
+			}
+		},
+	)
+
+	// load recomputes the cleared balance and difference; it reports whether the inputs are valid
+	load = func() bool {
+		date, err := time.ParseInLocation("02/01/2006", strings.TrimSpace(dateEntry.Text), time.Local)
+		if err != nil {
+			differenceLabel.SetText("Data do extrato inválida, use DD/MM/AAAA")
+			return false
+		}
+		balance, err := models.ParseMoney(balanceEntry.Text, mw.financeService.GetBaseCurrency())
+		if err != nil {
+			differenceLabel.SetText("Informe o saldo final do extrato")
+			return false
+		}
+		result, err := mw.financeService.GetReconciliation(mw.accountIDByName(accountSelect.Selected), date, balance)
+		if err != nil {
+			dialog.ShowError(err, mw.window)
+			return false
+		}
+		reconciliation = result
+
+		difference := reconciliation.Difference()
+		clearedLabel.SetText(fmt.Sprintf("Saldo compensado: %s", reconciliation.ClearedBalance))
+		differenceLabel.SetText(fmt.Sprintf("Diferença: %s", difference))
+		if difference.IsZero() {
+			differenceLabel.Importance = widget.SuccessImportance
+			finishButton.Enable()
+		} else {
+			differenceLabel.Importance = widget.DangerImportance
+			finishButton.Disable()
+		}
+		differenceLabel.Refresh()
+		list.Refresh()
+		return true
+	}
+
+	accountSelect.OnChanged = func(string) { load() }
+	dateEntry.OnChanged = func(string) { load() }
+	balanceEntry.OnChanged = func(string) { load() }
+
+	finishButton.OnTapped = func() {
+		if !load() {
+			return
+		}
+		reconciled, err := mw.financeService.FinishReconciliation(reconciliation.AccountID, reconciliation.StatementDate, reconciliation.EndingBalance)
+		if err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		load()
+		mw.Refresh()
+		dialog.ShowInformation("Conciliação", fmt.Sprintf("%d transações conciliadas e bloqueadas.", reconciled), mw.window)
+	}
+
+	form := widget.NewForm(
+		widget.NewFormItem("Conta", accountSelect),
+		widget.NewFormItem("Data do extrato", dateEntry),
+		widget.NewFormItem("Saldo do extrato", balanceEntry),
+	)
+	header := container.NewVBox(form, container.NewHBox(clearedLabel, differenceLabel), widget.NewSeparator())
+	content := container.NewBorder(header, finishButton, nil, nil, list)
+
+	load()
+	d := dialog.NewCustom("Conciliação Bancária", "Fechar", content, mw.window)
+	d.Resize(fyne.NewSize(640, 560))
+	d.Show()
+}
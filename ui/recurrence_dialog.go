@@ -0,0 +1,284 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"finance_go/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showRecurrenceDialog lists the recurrence rules and lets the user manage them
+func (mw *MainWindow) showRecurrenceDialog() {
+	selected := -1
+
+	list := widget.NewList(
+		func() int {
+			return len(mw.recurrenceService.GetRules())
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			rules := mw.recurrenceService.GetRules()
+			if id < len(rules) {
+				o.(*widget.Label).SetText(describeRule(rules[id]))
+			}
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id
+	}
+
+	selectedRule := func() (models.RecurrenceRule, bool) {
+		rules := mw.recurrenceService.GetRules()
+		if selected < 0 || selected >= len(rules) {
+			dialog.ShowInformation("Recorrências", "Selecione uma regra na lista.", mw.window)
+			return models.RecurrenceRule{}, false
+		}
+		return rules[selected], true
+	}
+
+	newButton := widget.NewButton("Nova Regra", func() {
+		mw.showNewRecurrenceRuleDialog(list.Refresh)
+	})
+	skipButton := widget.NewButton("Pular Ocorrência", func() {
+		if rule, ok := selectedRule(); ok {
+			mw.showOccurrenceDialog(rule, true)
+		}
+	})
+	changeButton := widget.NewButton("Alterar Ocorrência", func() {
+		if rule, ok := selectedRule(); ok {
+			mw.showOccurrenceDialog(rule, false)
+		}
+	})
+	deleteButton := widget.NewButton("Excluir Regra", func() {
+		rule, ok := selectedRule()
+		if !ok {
+			return
+		}
+		dialog.ShowConfirm("Excluir Regra", "Excluir esta regra? As transações já geradas serão mantidas.", func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if err := mw.recurrenceService.DeleteRule(rule.ID); err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+			selected = -1
+			list.UnselectAll()
+			list.Refresh()
+		}, mw.window)
+	})
+	generateButton := widget.NewButton("Gerar Agora", func() {
+		created := mw.recurrenceService.GenerateDue(time.Now())
+		dialog.ShowInformation("Recorrências", fmt.Sprintf("%d transações geradas.", len(created)), mw.window)
+		list.Refresh()
+		mw.Refresh()
+	})
+
+	buttons := container.NewHBox(newButton, skipButton, changeButton, deleteButton, generateButton)
+	content := container.NewBorder(nil, buttons, nil, nil, list)
+
+	d := dialog.NewCustom("Recorrências", "Fechar", content, mw.window)
+	d.Resize(fyne.NewSize(700, 400))
+	d.Show()
+}
+
+// showNewRecurrenceRuleDialog opens a form to create a recurrence rule
+func (mw *MainWindow) showNewRecurrenceRuleDialog(onCreated func()) {
+	descriptionEntry := widget.NewEntry()
+	amountEntry := widget.NewEntry()
+	currencySelect := widget.NewSelect(models.SupportedCurrencies(), func(string) {})
+	currencySelect.SetSelected(mw.financeService.GetBaseCurrency())
+	typeSelect := widget.NewSelect(models.TransactionTypeNames(), func(string) {})
+	typeSelect.SetSelected(string(models.TransactionTypeExpense))
+	categoryEntry := widget.NewEntry()
+	accountItem, accountSelect := mw.accountFormItem(mw.selectedAccountID())
+
+	frequencySelect := widget.NewSelect(models.FrequencyNames(), func(string) {})
+	frequencySelect.SetSelected(string(models.FrequencyMonthly))
+	intervalEntry := widget.NewEntry()
+	intervalEntry.SetText("1")
+	dayEntry := widget.NewEntry()
+	dayEntry.SetPlaceHolder("Opcional")
+	startEntry := widget.NewEntry()
+	startEntry.SetText(time.Now().Format("02/01/2006"))
+	endEntry := widget.NewEntry()
+	endEntry.SetPlaceHolder("Opcional, DD/MM/AAAA")
+	occurrencesEntry := widget.NewEntry()
+	occurrencesEntry.SetPlaceHolder("Opcional")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Descrição", descriptionEntry),
+		widget.NewFormItem("Valor", amountEntry),
+		widget.NewFormItem("Moeda", currencySelect),
+		widget.NewFormItem("Tipo", typeSelect),
+		widget.NewFormItem("Categoria", categoryEntry),
+		accountItem,
+		widget.NewFormItem("Frequência", frequencySelect),
+		widget.NewFormItem("A cada", intervalEntry),
+		widget.NewFormItem("Dia do mês", dayEntry),
+		widget.NewFormItem("Início", startEntry),
+		widget.NewFormItem("Fim", endEntry),
+		widget.NewFormItem("Ocorrências", occurrencesEntry),
+	}
+
+	dialog.ShowForm("Nova Regra de Recorrência", "Criar", "Cancelar", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		value, err := models.ParseMoney(amountEntry.Text, currencySelect.Selected)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("valor inválido"), mw.window)
+			return
+		}
+		typ, err := models.ParseTransactionType(typeSelect.Selected)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("tipo inválido"), mw.window)
+			return
+		}
+		interval, err := parseOptionalInt(intervalEntry.Text)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("intervalo inválido"), mw.window)
+			return
+		}
+		day, err := parseOptionalInt(dayEntry.Text)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("dia do mês inválido"), mw.window)
+			return
+		}
+		occurrences, err := parseOptionalInt(occurrencesEntry.Text)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("número de ocorrências inválido"), mw.window)
+			return
+		}
+		start, err := time.ParseInLocation("02/01/2006", strings.TrimSpace(startEntry.Text), time.Local)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("data de início inválida, use DD/MM/AAAA"), mw.window)
+			return
+		}
+
+		rule := models.RecurrenceRule{
+			Type:        typ,
+			Value:       value,
+			Description: descriptionEntry.Text,
+			Category:    categoryEntry.Text,
+			AccountID:   mw.accountIDByName(accountSelect.Selected),
+			Frequency:   models.Frequency(frequencySelect.Selected),
+			Interval:    interval,
+			DayOfMonth:  day,
+			StartDate:   start,
+			Occurrences: occurrences,
+		}
+		if text := strings.TrimSpace(endEntry.Text); text != "" {
+			end, err := time.ParseInLocation("02/01/2006", text, time.Local)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("data de fim inválida, use DD/MM/AAAA"), mw.window)
+				return
+			}
+			rule.EndDate = &end
+		}
+
+		if _, err := mw.recurrenceService.AddRule(rule); err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		onCreated()
+	}, mw.window)
+}
+
+// showOccurrenceDialog skips or changes one of the next occurrences of a rule
+func (mw *MainWindow) showOccurrenceDialog(rule models.RecurrenceRule, skip bool) {
+	upcoming, err := mw.recurrenceService.UpcomingOccurrences(rule.ID, time.Now().AddDate(1, 0, 0))
+	if err != nil {
+		dialog.ShowError(err, mw.window)
+		return
+	}
+	if len(upcoming) == 0 {
+		dialog.ShowInformation("Recorrências", "Nenhuma ocorrência futura nos próximos 12 meses.", mw.window)
+		return
+	}
+
+	var dates []string
+	for _, date := range upcoming {
+		dates = append(dates, date.Format("02/01/2006"))
+	}
+	dateSelect := widget.NewSelect(dates, func(string) {})
+	dateSelect.SetSelected(dates[0])
+
+	amountEntry := widget.NewEntry()
+	amountEntry.SetText(rule.Value.Abs().Decimal())
+	descriptionEntry := widget.NewEntry()
+	descriptionEntry.SetText(rule.Description)
+
+	items := []*widget.FormItem{widget.NewFormItem("Ocorrência", dateSelect)}
+	title := "Pular Ocorrência"
+	if !skip {
+		title = "Alterar Ocorrência"
+		items = append(items,
+			widget.NewFormItem("Valor", amountEntry),
+			widget.NewFormItem("Descrição", descriptionEntry),
+		)
+	}
+
+	dialog.ShowForm(title, "Salvar", "Cancelar", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		date := upcoming[dateSelect.SelectedIndex()]
+		if skip {
+			err = mw.recurrenceService.SkipOccurrence(rule.ID, date)
+		} else {
+			value, parseErr := models.ParseMoney(amountEntry.Text, rule.Value.Currency)
+			if parseErr != nil {
+				dialog.ShowError(fmt.Errorf("valor inválido"), mw.window)
+				return
+			}
+			err = mw.recurrenceService.ChangeOccurrence(rule.ID, date, value, descriptionEntry.Text)
+		}
+		if err != nil {
+			dialog.ShowError(err, mw.window)
+		}
+	}, mw.window)
+}
+
+// describeRule returns a one-line summary of a recurrence rule
+func describeRule(rule models.RecurrenceRule) string {
+	summary := fmt.Sprintf("%s — %s %s", rule.Description, rule.Value, rule.Frequency)
+	if rule.Interval > 1 {
+		summary += fmt.Sprintf(" (a cada %d)", rule.Interval)
+	}
+	if rule.DayOfMonth > 0 {
+		summary += fmt.Sprintf(", dia %d", rule.DayOfMonth)
+	}
+	summary += fmt.Sprintf(", desde %s", rule.StartDate.Format("02/01/2006"))
+	if rule.EndDate != nil {
+		summary += fmt.Sprintf(" até %s", rule.EndDate.Format("02/01/2006"))
+	}
+	if rule.Occurrences > 0 {
+		summary += fmt.Sprintf(", %d vezes", rule.Occurrences)
+	}
+	return summary
+}
+
+// parseOptionalInt parses a non-negative integer, treating an empty string as zero
+func parseOptionalInt(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return n, nil
+}
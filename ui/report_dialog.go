@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"finance_go/models"
+	"finance_go/services"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// reportGranularities are the period kinds offered to break the report down
+var reportGranularities = []models.PeriodKind{models.PeriodDay, models.PeriodWeek, models.PeriodMonth, models.PeriodQuarter, models.PeriodYear}
+
+// showReportDialog shows totals per type and a time series with running balances for the
+// transactions selected by the current filters
+func (mw *MainWindow) showReportDialog() {
+	query := mw.currentQuery()
+	var series []services.PeriodTotal
+
+	typeLabel := widget.NewLabel("")
+	var parts []string
+	for _, total := range mw.reportService.TotalsByType(query) {
+		parts = append(parts, fmt.Sprintf("%s: %s (%d)", total.Type, total.Total, total.Count))
+	}
+	typeLabel.SetText(strings.Join(parts, " | "))
+
+	// Installments still to come are shown apart from what was already spent
+	committedLabel := widget.NewLabel("")
+	if report := mw.reportService.Build(query); report.Committed.Count > 0 {
+		committedLabel.SetText(fmt.Sprintf("Despesas realizadas: %s | Parcelas a vencer: %s (%d parcelas)",
+			report.Realized.Expense, report.Committed.Expense, report.Committed.Count))
+	} else {
+		committedLabel.Hide()
+	}
+
+	rateLabel := widget.NewLabel(missingRatesText(mw.financeService.MissingRates(time.Now())))
+	rateLabel.Importance = widget.DangerImportance
+	rateLabel.Wrapping = fyne.TextWrapWord
+	if rateLabel.Text == "" {
+		rateLabel.Hide()
+	}
+
+	table := widget.NewTable(
+		func() (int, int) {
+			return len(series) + 1, 5
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.TableCellID, o fyne.CanvasObject) {
+			label := o.(*widget.Label)
+			if id.Row == 0 {
+				label.SetText([]string{"Período", "Receitas", "Despesas", "Saldo", "Acumulado"}[id.Col])
+				return
+			}
+			total := series[id.Row-1]
+			switch id.Col {
+			case 0:
+				label.SetText(total.Period.Label())
+			case 1:
+				label.SetText(total.Totals.Income.String())
+			case 2:
+				label.SetText(total.Totals.Expense.String())
+			case 3:
+				label.SetText(total.Totals.Balance().String())
+			case 4:
+				label.SetText(total.Closing.String())
+			}
+		},
+	)
+	table.SetColumnWidth(0, 200)
+	for col := 1; col < 5; col++ {
+		table.SetColumnWidth(col, 130)
+	}
+
+	var options []string
+	for _, kind := range reportGranularities {
+		options = append(options, string(kind))
+	}
+	granularitySelect := widget.NewSelect(options, func(selected string) {
+		series = mw.reportService.TotalsByPeriod(query, models.PeriodKind(selected))
+		table.Refresh()
+	})
+	granularitySelect.SetSelected(string(models.PeriodMonth))
+
+	// The period report covers every transaction of the selected period, with opening and closing balances
+	period := mw.period
+	periodPDFButton := widget.NewButton("Exportar PDF do Período", func() {
+		dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+
+			if err := mw.pdfExportService.ExportPeriodReport(writer.URI().Path(), period); err != nil {
+				dialog.ShowError(fmt.Errorf("erro ao exportar PDF: %v", err), mw.window)
+			} else {
+				dialog.ShowInformation("Sucesso", "PDF exportado com sucesso!", mw.window)
+			}
+		}, mw.window)
+	})
+	if period.IsAll() {
+		periodPDFButton.Disable()
+	}
+
+	header := container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("Período: %s", query.Period.Label())),
+		typeLabel,
+		committedLabel,
+		rateLabel,
+		container.NewHBox(widget.NewLabel("Agrupar por:"), granularitySelect, periodPDFButton),
+	)
+	content := container.NewBorder(header, nil, nil, nil, table)
+
+	d := dialog.NewCustom("Relatório", "Fechar", content, mw.window)
+	d.Resize(fyne.NewSize(760, 500))
+	d.Show()
+}
@@ -0,0 +1,226 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"finance_go/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// anyOption leaves a rule condition unset
+const anyOption = "Qualquer"
+
+// showRuleDialog lists the categorization rules in the order they are tried and lets the user
+// add, reorder and delete them or run them over the existing transactions
+func (mw *MainWindow) showRuleDialog() {
+	var rules []models.CategoryRule
+
+	var list *widget.List
+	reload := func() {
+		rules = mw.financeService.GetRules()
+		list.Refresh()
+	}
+	move := func(id int, up bool) {
+		if err := mw.financeService.MoveRule(id, up); err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		reload()
+	}
+
+	list = widget.NewList(
+		func() int {
+			return len(rules)
+		},
+		func() fyne.CanvasObject {
+			buttons := container.NewHBox(
+				widget.NewButtonWithIcon("", theme.MoveUpIcon(), nil),
+				widget.NewButtonWithIcon("", theme.MoveDownIcon(), nil),
+				widget.NewButtonWithIcon("", theme.DeleteIcon(), nil),
+			)
+			return container.NewBorder(nil, nil, nil, buttons, widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			if id >= len(rules) {
+				return
+			}
+			rule := rules[id]
+			row := o.(*fyne.Container)
+			label := row.Objects[0].(*widget.Label)
+			buttons := row.Objects[1].(*fyne.Container)
+
+			label.SetText(fmt.Sprintf("%d. %s", id+1, mw.financeService.DescribeRule(rule)))
+			buttons.Objects[0].(*widget.Button).OnTapped = func() { move(rule.ID, true) }
+			buttons.Objects[1].(*widget.Button).OnTapped = func() { move(rule.ID, false) }
+			buttons.Objects[2].(*widget.Button).OnTapped = func() {
+				dialog.ShowConfirm("Excluir Regra", "Excluir esta regra? As categorias já definidas serão mantidas.", func(confirmed bool) {
+					if !confirmed {
+						return
+					}
+					if err := mw.financeService.DeleteRule(rule.ID); err != nil {
+						dialog.ShowError(err, mw.window)
+						return
+					}
+					reload()
+				}, mw.window)
+			}
+		},
+	)
+
+	newButton := widget.NewButton("Nova Regra", func() {
+		mw.showNewRuleDialog(reload)
+	})
+	applyButton := widget.NewButton("Aplicar às Transações", mw.showApplyRulesDialog)
+	hint := widget.NewLabel("Transações sem categoria recebem a categoria da primeira regra que atender a todas as condições.")
+	hint.Wrapping = fyne.TextWrapWord
+
+	content := container.NewBorder(hint, container.NewHBox(newButton, applyButton), nil, nil, list)
+
+	reload()
+	d := dialog.NewCustom("Regras de Categorização", "Fechar", content, mw.window)
+	d.Resize(fyne.NewSize(720, 480))
+	d.Show()
+}
+
+// showNewRuleDialog opens a form to create a categorization rule; blank conditions are ignored
+func (mw *MainWindow) showNewRuleDialog(onCreated func()) {
+	containsEntry := widget.NewEntry()
+	containsEntry.SetPlaceHolder("Ex.: UBER")
+	patternEntry := widget.NewEntry()
+	patternEntry.SetPlaceHolder("Ex.: netflix|spotify")
+	typeSelect := widget.NewSelect(append([]string{anyOption}, models.TransactionTypeNames()...), func(string) {})
+	typeSelect.SetSelected(anyOption)
+	minEntry := widget.NewEntry()
+	minEntry.SetPlaceHolder("Opcional")
+	maxEntry := widget.NewEntry()
+	maxEntry.SetPlaceHolder("Opcional")
+	accountSelect := widget.NewSelect(append([]string{anyOption}, mw.accountNames()...), func(string) {})
+	accountSelect.SetSelected(anyOption)
+	categoryEntry := widget.NewSelectEntry(mw.financeService.GetTransactionList().GetCategoryPaths())
+	categoryEntry.SetPlaceHolder("Ex.: Transporte")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Descrição contém", containsEntry),
+		widget.NewFormItem("Expressão regular", patternEntry),
+		widget.NewFormItem("Tipo", typeSelect),
+		widget.NewFormItem("Valor mínimo", minEntry),
+		widget.NewFormItem("Valor máximo", maxEntry),
+		widget.NewFormItem("Conta", accountSelect),
+		widget.NewFormItem("Categoria", categoryEntry),
+	}
+
+	dialog.ShowForm("Nova Regra", "Criar", "Cancelar", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		rule := models.CategoryRule{
+			Contains: containsEntry.Text,
+			Pattern:  patternEntry.Text,
+			Category: categoryEntry.Text,
+		}
+		if typeSelect.Selected != anyOption {
+			rule.Type = models.TransactionType(typeSelect.Selected)
+		}
+		if accountSelect.Selected != anyOption {
+			rule.AccountID = mw.accountIDByName(accountSelect.Selected)
+		}
+		for _, bound := range []struct {
+			entry *widget.Entry
+			value **models.Money
+			name  string
+		}{{minEntry, &rule.MinAmount, "valor mínimo"}, {maxEntry, &rule.MaxAmount, "valor máximo"}} {
+			if strings.TrimSpace(bound.entry.Text) == "" {
+				continue
+			}
+			amount, err := models.ParseMoney(bound.entry.Text, mw.financeService.GetBaseCurrency())
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s inválido", bound.name), mw.window)
+				return
+			}
+			amount = amount.Abs()
+			*bound.value = &amount
+		}
+
+		if _, err := mw.financeService.AddRule(rule); err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		onCreated()
+	}, mw.window)
+}
+
+// showApplyRulesDialog previews the categories the rules would set on existing transactions and
+// applies them on confirmation
+func (mw *MainWindow) showApplyRulesDialog() {
+	var changes []models.RuleChange
+
+	summary := widget.NewLabel("")
+	table := widget.NewTable(
+		func() (int, int) {
+			return len(changes) + 1, 4
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.TableCellID, o fyne.CanvasObject) {
+			label := o.(*widget.Label)
+			if id.Row == 0 {
+				label.SetText([]string{"Data", "Valor / Descrição", "Categoria atual", "Nova categoria"}[id.Col])
+				return
+			}
+			change := changes[id.Row-1]
+			switch id.Col {
+			case 0:
+				label.SetText(change.Transaction.Date.Format("02/01/2006"))
+			case 1:
+				label.SetText(fmt.Sprintf("%s %s", mw.formatValue(change.Transaction), change.Transaction.Description))
+			case 2:
+				label.SetText(change.From)
+			case 3:
+				label.SetText(change.To)
+			}
+		},
+	)
+	table.SetColumnWidth(0, 100)
+	table.SetColumnWidth(1, 300)
+	table.SetColumnWidth(2, 180)
+	table.SetColumnWidth(3, 180)
+
+	applyButton := widget.NewButton("Aplicar", nil)
+	applyButton.Importance = widget.HighImportance
+
+	overwriteCheck := widget.NewCheck("Substituir categorias existentes", nil)
+	preview := func() {
+		changes = mw.financeService.PreviewRules(overwriteCheck.Checked)
+		summary.SetText(fmt.Sprintf("%d transações terão a categoria alterada", len(changes)))
+		if len(changes) == 0 {
+			applyButton.Disable()
+		} else {
+			applyButton.Enable()
+		}
+		table.Refresh()
+	}
+	overwriteCheck.OnChanged = func(bool) { preview() }
+
+	applyButton.OnTapped = func() {
+		applied := mw.financeService.ApplyRules(overwriteCheck.Checked)
+		preview()
+		mw.Refresh()
+		dialog.ShowInformation("Regras Aplicadas", fmt.Sprintf("%d transações recategorizadas.", len(applied)), mw.window)
+	}
+
+	header := container.NewVBox(overwriteCheck, summary)
+	content := container.NewBorder(header, applyButton, nil, nil, table)
+
+	preview()
+	d := dialog.NewCustom("Aplicar Regras", "Fechar", content, mw.window)
+	d.Resize(fyne.NewSize(820, 480))
+	d.Show()
+}
@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"finance_go/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// splitRow holds the entries of one split in the split editor
+type splitRow struct {
+	amountEntry      *widget.Entry
+	categoryEntry    *widget.Entry
+	descriptionEntry *widget.Entry
+	container        *fyne.Container
+}
+
+// showSplitEditor lets the user divide a transaction into splits that must add up to its value.
+// Saving without any split turns it back into a regular transaction.
+func (mw *MainWindow) showSplitEditor(id int) {
+	tx, err := mw.financeService.GetTransactionByID(id)
+	if err != nil {
+		dialog.ShowError(err, mw.window)
+		return
+	}
+
+	var rows []*splitRow
+	rowsBox := container.NewVBox()
+	remainingLabel := widget.NewLabel("")
+
+	// parseSplits reads the rows, skipping completely empty ones
+	parseSplits := func() ([]models.Split, error) {
+		var splits []models.Split
+		for i, row := range rows {
+			if strings.TrimSpace(row.amountEntry.Text) == "" && strings.TrimSpace(row.categoryEntry.Text) == "" {
+				continue
+			}
+			value, err := models.ParseMoney(row.amountEntry.Text, tx.Value.Currency)
+			if err != nil {
+				return nil, fmt.Errorf("valor inválido na divisão %d", i+1)
+			}
+			splits = append(splits, models.Split{
+				Value:       tx.Type.SignedValue(value),
+				Category:    row.categoryEntry.Text,
+				Description: row.descriptionEntry.Text,
+			})
+		}
+		return splits, nil
+	}
+
+	updateRemaining := func() {
+		splits, err := parseSplits()
+		if err != nil {
+			remainingLabel.SetText(err.Error())
+			return
+		}
+		remaining := tx.Value.Abs()
+		for _, split := range splits {
+			remaining = remaining.Sub(split.Value.Abs())
+		}
+		remainingLabel.SetText(fmt.Sprintf("Total: %s | Restante: %s", tx.Value.Abs(), remaining))
+	}
+
+	var addRow func(split models.Split)
+	addRow = func(split models.Split) {
+		row := &splitRow{
+			amountEntry:      widget.NewEntry(),
+			categoryEntry:    widget.NewEntry(),
+			descriptionEntry: widget.NewEntry(),
+		}
+		row.amountEntry.SetPlaceHolder("Valor")
+		row.categoryEntry.SetPlaceHolder("Categoria")
+		row.descriptionEntry.SetPlaceHolder("Descrição")
+		if !split.Value.IsZero() {
+			row.amountEntry.SetText(split.Value.Abs().Decimal())
+		}
+		row.categoryEntry.SetText(split.Category)
+		row.descriptionEntry.SetText(split.Description)
+		row.amountEntry.OnChanged = func(string) { updateRemaining() }
+
+		removeButton := widget.NewButton("Remover", nil)
+		row.container = container.NewGridWithColumns(4, row.amountEntry, row.categoryEntry, row.descriptionEntry, removeButton)
+		removeButton.OnTapped = func() {
+			for i, existing := range rows {
+				if existing == row {
+					rows = append(rows[:i], rows[i+1:]...)
+					break
+				}
+			}
+			rowsBox.Remove(row.container)
+			updateRemaining()
+		}
+
+		rows = append(rows, row)
+		rowsBox.Add(row.container)
+		updateRemaining()
+	}
+
+	for _, split := range tx.Splits {
+		addRow(split)
+	}
+	if len(rows) == 0 {
+		addRow(models.Split{Value: tx.Value, Category: tx.Category})
+		addRow(models.Split{})
+	}
+
+	addButton := widget.NewButton("Adicionar Divisão", func() {
+		addRow(models.Split{})
+	})
+
+	// Validation errors are shown inline so the dialog keeps what the user typed
+	errorLabel := widget.NewLabel("")
+	errorLabel.Importance = widget.DangerImportance
+	errorLabel.Wrapping = fyne.TextWrapWord
+	errorLabel.Hide()
+	showError := func(err error) {
+		errorLabel.SetText(err.Error())
+		errorLabel.Show()
+	}
+
+	content := container.NewBorder(
+		container.NewVBox(widget.NewLabel(fmt.Sprintf("%s — %s", tx.Description, tx.Value)), remainingLabel),
+		container.NewVBox(addButton, errorLabel),
+		nil,
+		nil,
+		container.NewVScroll(rowsBox),
+	)
+
+	d := dialog.NewCustomWithoutButtons("Dividir Transação", content, mw.window)
+
+	saveButton := widget.NewButton("Salvar", func() {
+		splits, err := parseSplits()
+		if err != nil {
+			showError(err)
+			return
+		}
+		if len(splits) == 1 {
+			// A single split is just the whole transaction in that category, so it must carry the whole value
+			if splits[0].Value != tx.Value {
+				showError(fmt.Errorf("uma única divisão precisa ter o valor total da transação (%s)", tx.Value.Abs()))
+				return
+			}
+			tx.Category = splits[0].Category
+			splits = nil
+		}
+		if err := tx.SetSplits(splits); err != nil {
+			showError(err)
+			return
+		}
+		if len(splits) > 0 {
+			tx.Category = ""
+		}
+		if err := mw.financeService.UpdateTransaction(tx); err != nil {
+			showError(err)
+			return
+		}
+
+		d.Hide()
+		mw.Refresh()
+	})
+	saveButton.Importance = widget.HighImportance
+
+	d.SetButtons([]fyne.CanvasObject{widget.NewButton("Cancelar", d.Hide), saveButton})
+	d.Show()
+}
@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"finance_go/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showStatementDialog lists the statements of a credit card, newest first, with their totals and payments
+func (mw *MainWindow) showStatementDialog() {
+	var cards []string
+	for _, account := range mw.financeService.GetAccounts() {
+		if account.IsCreditCard() {
+			cards = append(cards, account.Name)
+		}
+	}
+	if len(cards) == 0 {
+		dialog.ShowInformation("Faturas", "Cadastre um cartão de crédito em \"Nova Conta\" com os dias de fechamento e vencimento.", mw.window)
+		return
+	}
+
+	var statements []models.Statement
+	cardID := 0
+
+	list := widget.NewList(
+		func() int {
+			return len(statements)
+		},
+		func() fyne.CanvasObject {
+			return container.NewVBox(widget.NewLabel(""), widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			if id >= len(statements) {
+				return
+			}
+			statement := statements[id]
+			box := o.(*fyne.Container)
+			title := box.Objects[0].(*widget.Label)
+			detail := box.Objects[1].(*widget.Label)
+
+			title.SetText(fmt.Sprintf("%s — %s (%s)", statement.Label(), statement.Total, statementState(statement)))
+			detail.Importance = widget.MediumImportance
+			if statement.IsClosed(time.Now()) && !statement.IsPaid() && time.Now().After(statement.Due.AddDate(0, 0, 1)) {
+				detail.Importance = widget.DangerImportance
+			}
+			detail.SetText(fmt.Sprintf("Compras de %s | Fecha em %s | Vence em %s | Pago: %s",
+				statement.Period.Label(), statement.Closing.Format("02/01/2006"), statement.Due.Format("02/01/2006"), statement.Paid))
+		},
+	)
+
+	reload := func() {
+		result, err := mw.financeService.GetStatements(cardID, time.Now())
+		if err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		// Newest first
+		statements = nil
+		for i := len(result) - 1; i >= 0; i-- {
+			statements = append(statements, result[i])
+		}
+		list.Refresh()
+	}
+
+	cardSelect := widget.NewSelect(cards, func(selected string) {
+		cardID = mw.accountIDByName(selected)
+		reload()
+	})
+
+	list.OnSelected = func(id widget.ListItemID) {
+		list.UnselectAll()
+		if id >= len(statements) {
+			return
+		}
+		mw.showStatementDetail(statements[id], func() {
+			reload()
+			mw.Refresh()
+		})
+	}
+
+	cardSelect.SetSelected(cards[0])
+	content := container.NewBorder(container.NewHBox(widget.NewLabel("Cartão:"), cardSelect), nil, nil, nil, list)
+
+	d := dialog.NewCustom("Faturas do Cartão", "Fechar", content, mw.window)
+	d.Resize(fyne.NewSize(720, 500))
+	d.Show()
+}
+
+// showStatementDetail shows the transactions of a statement and lets the user pay it from another account
+func (mw *MainWindow) showStatementDetail(statement models.Statement, onPaid func()) {
+	table := widget.NewTable(
+		func() (int, int) {
+			return len(statement.Transactions) + 1, 4
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.TableCellID, o fyne.CanvasObject) {
+			label := o.(*widget.Label)
+			if id.Row == 0 {
+				label.SetText([]string{"Data", "Descrição", "Categoria", "Valor"}[id.Col])
+				return
+			}
+			tx := statement.Transactions[id.Row-1]
+			switch id.Col {
+			case 0:
+				label.SetText(tx.Date.Format("02/01/2006"))
+			case 1:
+				label.SetText(tx.Description)
+			case 2:
+				label.SetText(tx.CategoryLabel())
+			case 3:
+				label.SetText(mw.formatValue(tx))
+			}
+		},
+	)
+	table.SetColumnWidth(0, 100)
+	table.SetColumnWidth(1, 260)
+	table.SetColumnWidth(2, 180)
+	table.SetColumnWidth(3, 140)
+
+	var d dialog.Dialog
+	payButton := widget.NewButton("Pagar Fatura", func() {
+		var accounts []string
+		for _, account := range mw.financeService.GetAccounts() {
+			if account.ID != statement.AccountID {
+				accounts = append(accounts, account.Name)
+			}
+		}
+		fromSelect := widget.NewSelect(accounts, func(string) {})
+		if len(accounts) > 0 {
+			fromSelect.SetSelected(accounts[0])
+		}
+		amountEntry := widget.NewEntry()
+		amountEntry.SetText(statement.Remaining().Decimal())
+		dateEntry := widget.NewEntry()
+		dateEntry.SetText(time.Now().Format("02/01/2006"))
+
+		items := []*widget.FormItem{
+			widget.NewFormItem("Pagar com", fromSelect),
+			widget.NewFormItem("Valor", amountEntry),
+			widget.NewFormItem("Data", dateEntry),
+		}
+		dialog.ShowForm("Pagar Fatura", "Pagar", "Cancelar", items, func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			amount, err := models.ParseMoney(amountEntry.Text, mw.financeService.GetBaseCurrency())
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("valor inválido"), mw.window)
+				return
+			}
+			date, err := time.ParseInLocation("02/01/2006", strings.TrimSpace(dateEntry.Text), time.Local)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("data inválida, use DD/MM/AAAA"), mw.window)
+				return
+			}
+			if err := mw.financeService.PayStatement(statement.AccountID, mw.accountIDByName(fromSelect.Selected), statement, amount, date); err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+			d.Hide()
+			onPaid()
+		}, mw.window)
+	})
+	if statement.IsPaid() {
+		payButton.Disable()
+	}
+
+	summary := widget.NewLabel(fmt.Sprintf("Total: %s | Pago: %s | A pagar: %s | Vencimento: %s",
+		statement.Total, statement.Paid, statement.Remaining(), statement.Due.Format("02/01/2006")))
+	content := container.NewBorder(container.NewVBox(summary, payButton), nil, nil, nil, table)
+
+	d = dialog.NewCustom(statement.Label(), "Fechar", content, mw.window)
+	d.Resize(fyne.NewSize(720, 500))
+	d.Show()
+}
+
+// statementState describes whether a statement is open, closed or paid
+func statementState(statement models.Statement) string {
+	switch {
+	case !statement.IsClosed(time.Now()):
+		return "Aberta"
+	case statement.IsPaid():
+		return "Paga"
+	default:
+		return "Fechada"
+	}
+}
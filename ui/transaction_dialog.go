@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"finance_go/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showEditTransactionDialog opens a dialog to edit or delete the transaction with the given ID
+func (mw *MainWindow) showEditTransactionDialog(id int) {
+	tx, err := mw.financeService.GetTransactionByID(id)
+	if err != nil {
+		dialog.ShowError(err, mw.window)
+		return
+	}
+
+	amountEntry := widget.NewEntry()
+	amountEntry.SetText(tx.Value.Abs().Decimal())
+
+	currencySelect := widget.NewSelect(models.SupportedCurrencies(), func(string) {})
+	currencySelect.SetSelected(tx.Value.Currency)
+
+	descriptionEntry := widget.NewEntry()
+	descriptionEntry.SetText(tx.Description)
+
+	categoryEntry := widget.NewEntry()
+	categoryEntry.SetText(tx.Category)
+	if tx.IsSplit() {
+		categoryEntry.SetText(tx.CategoryLabel())
+		categoryEntry.Disable()
+	}
+
+	tagsEntry := widget.NewEntry()
+	tagsEntry.SetText(models.FormatTags(tx.Tags))
+
+	typeSelect := widget.NewSelect(models.TransactionTypeNames(), func(string) {})
+	typeSelect.SetSelected(string(tx.Type))
+	if tx.Type == models.TransactionTypeTransfer {
+		// Transfer legs keep their type; delete and recreate the transfer to change it
+		typeSelect.SetOptions([]string{string(models.TransactionTypeTransfer)})
+		typeSelect.SetSelected(string(tx.Type))
+		typeSelect.Disable()
+	}
+
+	accountItem, accountSelect := mw.accountFormItem(tx.AccountID)
+
+	dateEntry := widget.NewEntry()
+	dateEntry.SetText(tx.Date.Format("02/01/2006"))
+
+	form := widget.NewForm(
+		widget.NewFormItem("Data", dateEntry),
+		widget.NewFormItem("Tipo", typeSelect),
+		widget.NewFormItem("Valor", amountEntry),
+		widget.NewFormItem("Moeda", currencySelect),
+		widget.NewFormItem("Descrição", descriptionEntry),
+		widget.NewFormItem("Categoria", categoryEntry),
+		widget.NewFormItem("Tags", tagsEntry),
+		accountItem,
+	)
+
+	d := dialog.NewCustomWithoutButtons("Editar Transação", form, mw.window)
+
+	saveButton := widget.NewButton("Salvar", func() {
+		value, err := models.ParseMoney(amountEntry.Text, currencySelect.Selected)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("valor inválido"), mw.window)
+			return
+		}
+
+		typ, err := models.ParseTransactionType(typeSelect.Selected)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("tipo inválido"), mw.window)
+			return
+		}
+
+		date, err := time.ParseInLocation("02/01/2006", strings.TrimSpace(dateEntry.Text), time.Local)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("data inválida, use DD/MM/AAAA"), mw.window)
+			return
+		}
+		// Keep the original time of day when only the date changes
+		if !date.Equal(truncateToDay(tx.Date)) {
+			tx.Date = date
+		}
+
+		if typ == models.TransactionTypeTransfer {
+			// Keep the direction of the transfer leg
+			value = value.Abs()
+			if tx.Value.IsNegative() {
+				value = value.Neg()
+			}
+		}
+
+		tx.Type = typ
+		tx.Value = typ.SignedValue(value)
+		if accountID := mw.accountIDByName(accountSelect.Selected); accountID != 0 {
+			tx.AccountID = accountID
+		}
+		tx.Description = descriptionEntry.Text
+		if !tx.IsSplit() {
+			tx.Category = categoryEntry.Text
+		}
+		tx.Tags = models.ParseTags(tagsEntry.Text)
+
+		if err := mw.financeService.UpdateTransaction(tx); err != nil {
+			dialog.ShowError(err, mw.window)
+			return
+		}
+
+		d.Hide()
+		mw.Refresh()
+	})
+	saveButton.Importance = widget.HighImportance
+
+	deleteButton := widget.NewButton("Excluir", func() {
+		dialog.ShowConfirm("Excluir Transação", "Tem certeza que deseja excluir esta transação?", func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if err := mw.attachmentService.DeleteTransaction(tx.ID); err != nil {
+				dialog.ShowError(err, mw.window)
+				return
+			}
+
+			d.Hide()
+			mw.Refresh()
+		}, mw.window)
+	})
+	deleteButton.Importance = widget.DangerImportance
+
+	splitButton := widget.NewButton("Dividir", func() {
+		d.Hide()
+		mw.showSplitEditor(tx.ID)
+	})
+	if tx.Type == models.TransactionTypeTransfer {
+		splitButton.Disable()
+	}
+
+	attachmentLabel := "Anexos"
+	if len(tx.Attachments) > 0 {
+		attachmentLabel = fmt.Sprintf("Anexos (%d)", len(tx.Attachments))
+	}
+	attachmentButton := widget.NewButton(attachmentLabel, func() {
+		d.Hide()
+		mw.showAttachmentDialog(tx.ID)
+	})
+
+	// Reconciled transactions keep their amount, date, type and account and cannot be deleted
+	if tx.IsLocked() {
+		amountEntry.Disable()
+		currencySelect.Disable()
+		typeSelect.Disable()
+		accountSelect.Disable()
+		dateEntry.Disable()
+		deleteButton.Disable()
+	}
+
+	cancelButton := widget.NewButton("Cancelar", d.Hide)
+
+	d.SetButtons([]fyne.CanvasObject{cancelButton, deleteButton, splitButton, attachmentButton, saveButton})
+	d.SetOnClosed(mw.transactions.UnselectAll)
+	d.Resize(fyne.NewSize(400, 300))
+	d.Show()
+}
+
+// truncateToDay returns midnight of the given time in the local time zone
+func truncateToDay(t time.Time) time.Time {
+	local := t.In(time.Local)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+}